@@ -0,0 +1,162 @@
+// Package apperrors classifies errors by Kind - config, auth, not found, rate limited, partial data and violated
+// SLOs - so the commands exit with the code of the kind and a hint of what to do, and the gui can show them
+package apperrors
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Kind - the class of an error
+type Kind int
+
+// kinds of errors
+const (
+	// Unknown - any other error
+	Unknown Kind = iota
+	// Config - the config, flags or arguments are invalid, e.g. an unknown run config or column, or a future date
+	Config
+	// Auth - the token is missing, invalid or lacks access
+	Auth
+	// NotFound - a project, repo or issue does not exist or is not visible to the token
+	NotFound
+	// RateLimited - the github api rate limit was reached
+	RateLimited
+	// PartialData - some of the results could not be produced, the others were
+	PartialData
+	// Violated - rules of the SLOs of the config were not met
+	Violated
+)
+
+// exit codes of the kinds, documented in the README
+var exitCodes = map[Kind]int{
+	Unknown:     1,
+	Config:      2,
+	Auth:        3,
+	NotFound:    4,
+	RateLimited: 5,
+	PartialData: 6,
+	Violated:    7,
+}
+
+var names = map[Kind]string{
+	Unknown:     "error",
+	Config:      "config error",
+	Auth:        "auth error",
+	NotFound:    "not found",
+	RateLimited: "rate limited",
+	PartialData: "partial data",
+	Violated:    "slo violated",
+}
+
+var hints = map[Kind]string{
+	Config:      "check the config and flags; github-metrics config validate lists the problems of config.yaml",
+	Auth:        "check the token (--token, GH_METRICS_API_TOKEN, .env or the credentials of the config) is valid and has the repo and read:org scopes",
+	NotFound:    "check the projectID, owner and repo names, and that the token has access to them",
+	RateLimited: "the github api rate limit was reached, retry once it resets or run from a store kept up to date with sync",
+	PartialData: "the results that could be produced were written, see the errors above",
+	Violated:    "the rules violated are reported above",
+}
+
+// String - returns the name of the kind
+func (k Kind) String() string {
+	if name, found := names[k]; found {
+		return name
+	}
+	return names[Unknown]
+}
+
+// ExitCode - returns the exit code of the commands for an error of the kind
+func (k Kind) ExitCode() int {
+	if code, found := exitCodes[k]; found {
+		return code
+	}
+	return exitCodes[Unknown]
+}
+
+// Hint - returns what to do about an error of the kind, blank when there is nothing to suggest
+func (k Kind) Hint() string {
+	return hints[k]
+}
+
+// Error - an error of a Kind
+type Error struct {
+	Kind Kind
+	// Msg - describes the error, or what was being done when Err happened
+	Msg string
+	Err error
+}
+
+func (e *Error) Error() string {
+	switch {
+	case e.Err == nil:
+		return e.Msg
+	case e.Msg == "":
+		return e.Err.Error()
+	default:
+		return e.Msg + ": " + e.Err.Error()
+	}
+}
+
+// Unwrap - returns the error wrapped
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// ErrorKind - returns the kind of the error
+func (e *Error) ErrorKind() Kind {
+	return e.Kind
+}
+
+// New - returns an error of the kind
+func New(kind Kind, msg string) error {
+	return &Error{Kind: kind, Msg: msg}
+}
+
+// Errorf - returns an error of the kind formatted like fmt.Errorf, %w wraps an error
+func Errorf(kind Kind, format string, args ...interface{}) error {
+	return &Error{Kind: kind, Err: fmt.Errorf(format, args...)}
+}
+
+// Wrap - returns err as an error of the kind, described by msg when not blank; nil when err is nil
+func Wrap(kind Kind, err error, msg string) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Kind: kind, Msg: msg, Err: err}
+}
+
+// KindOf - returns the kind of the first error in err's chain that has one, Unknown when none has
+func KindOf(err error) Kind {
+	for err != nil {
+		if kinded, ok := err.(interface{ ErrorKind() Kind }); ok {
+			return kinded.ErrorKind()
+		}
+		err = errors.Unwrap(err)
+	}
+	return Unknown
+}
+
+// Is - returns whether err is of the kind
+func Is(err error, kind Kind) bool {
+	return KindOf(err) == kind
+}
+
+// ExitCode - returns the exit code of err, 0 when nil
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	return KindOf(err).ExitCode()
+}
+
+// Message - returns the message of err for users, followed by the hint of its kind
+func Message(err error) string {
+	if err == nil {
+		return ""
+	}
+	if hint := KindOf(err).Hint(); hint != "" {
+		return err.Error() + "\n" + hint
+	}
+	return err.Error()
+}
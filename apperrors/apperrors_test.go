@@ -0,0 +1,77 @@
+package apperrors_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/3xcellent/github-metrics/apperrors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKindOf(t *testing.T) {
+	t.Run("returns the kind of an error", func(t *testing.T) {
+		assert.Equal(t, apperrors.Auth, apperrors.KindOf(apperrors.New(apperrors.Auth, "token rejected")))
+	})
+
+	t.Run("returns the kind of an error wrapped", func(t *testing.T) {
+		err := fmt.Errorf("board: %w", apperrors.Wrap(apperrors.NotFound, errors.New("404"), "getting project 42"))
+		assert.Equal(t, apperrors.NotFound, apperrors.KindOf(err))
+		assert.EqualError(t, err, "board: getting project 42: 404")
+	})
+
+	t.Run("returns the kind of errors that have one", func(t *testing.T) {
+		assert.Equal(t, apperrors.Config, apperrors.KindOf(fmt.Errorf("run: %w", columnError{})))
+	})
+
+	t.Run("returns unknown for other errors", func(t *testing.T) {
+		assert.Equal(t, apperrors.Unknown, apperrors.KindOf(errors.New("failed")))
+		assert.Equal(t, apperrors.Unknown, apperrors.KindOf(nil))
+	})
+}
+
+func TestExitCode(t *testing.T) {
+	for kind, code := range map[apperrors.Kind]int{
+		apperrors.Unknown:     1,
+		apperrors.Config:      2,
+		apperrors.Auth:        3,
+		apperrors.NotFound:    4,
+		apperrors.RateLimited: 5,
+		apperrors.PartialData: 6,
+		apperrors.Violated:    7,
+	} {
+		t.Run(kind.String(), func(t *testing.T) {
+			assert.Equal(t, code, apperrors.ExitCode(apperrors.New(kind, "failed")))
+		})
+	}
+
+	t.Run("is 0 without an error", func(t *testing.T) {
+		assert.Equal(t, 0, apperrors.ExitCode(nil))
+	})
+}
+
+func TestErrorf(t *testing.T) {
+	cause := errors.New("cause")
+	err := apperrors.Errorf(apperrors.RateLimited, "getting issues: %w", cause)
+
+	assert.EqualError(t, err, "getting issues: cause")
+	assert.True(t, errors.Is(err, cause))
+	assert.True(t, apperrors.Is(err, apperrors.RateLimited))
+}
+
+func TestMessage(t *testing.T) {
+	t.Run("follows the error with the hint of its kind", func(t *testing.T) {
+		err := apperrors.New(apperrors.Config, "unknown column")
+		assert.Equal(t, "unknown column\n"+apperrors.Config.Hint(), apperrors.Message(err))
+	})
+
+	t.Run("is the error without a hint", func(t *testing.T) {
+		assert.Equal(t, "failed", apperrors.Message(errors.New("failed")))
+	})
+}
+
+type columnError struct{}
+
+func (columnError) Error() string { return "unknown column" }
+
+func (columnError) ErrorKind() apperrors.Kind { return apperrors.Config }
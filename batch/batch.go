@@ -0,0 +1,131 @@
+// Package batch runs several metrics of several RunConfigs, fetching the data of each board once, and writes each
+// result to a file named by a template
+package batch
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/3xcellent/github-metrics/config"
+	"github.com/3xcellent/github-metrics/metrics/runners"
+	"github.com/3xcellent/github-metrics/output"
+	"github.com/pkg/errors"
+)
+
+// Batch - writes the results of runs in Format to Destination
+type Batch struct {
+	Client runners.Client
+	// Profiles - the clients of RunConfigs with a connection profile, Client is used for the others
+	Profiles runners.ProfileClients
+	Format   output.Format
+	// Destination - directory the files are written to, the working directory when blank
+	Destination string
+	Filename    *template.Template
+}
+
+// FileData - the data available to the Filename template
+type FileData struct {
+	Board  string
+	Metric string
+	Year   int
+	Month  string
+	Date   string
+	Ext    string
+}
+
+// Result - the outcome of running a metric of a board
+type Result struct {
+	Board    string
+	Metric   string
+	File     string
+	Duration time.Duration
+	Err      error
+}
+
+// New - returns a Batch writing files named by the filename template, config.DefaultFilename when blank
+func New(client runners.Client, format output.Format, destination, filename string) (*Batch, error) {
+	if filename == "" {
+		filename = config.DefaultFilename
+	}
+	tmpl, err := template.New("filename").Parse(filename)
+	if err != nil {
+		return nil, errors.Wrap(err, "filename")
+	}
+	return &Batch{Client: client, Format: format, Destination: destination, Filename: tmpl}, nil
+}
+
+// Run - runs each metric of each RunConfig and writes its result; a failed run does not stop the others. The
+// metrics of a board share the data fetched for it
+func (b *Batch) Run(ctx context.Context, runConfigs config.RunConfigs, metrics []string) []Result {
+	results := make([]Result, 0, len(runConfigs)*len(metrics))
+	for _, runCfg := range runConfigs {
+		client := runners.NewCachingClient(b.Profiles.Get(runCfg.Profile, b.Client))
+		for _, metric := range metrics {
+			began := time.Now()
+			file, err := b.run(ctx, client, runCfg, metric)
+			results = append(results, Result{
+				Board:    runCfg.Name,
+				Metric:   metric,
+				File:     file,
+				Duration: time.Since(began),
+				Err:      err,
+			})
+		}
+	}
+	return results
+}
+
+func (b *Batch) run(ctx context.Context, client runners.Client, runCfg config.RunConfig, metric string) (string, error) {
+	runCfg.MetricName = metric
+	runner, err := runners.New(runCfg, client)
+	if err != nil {
+		return "", err
+	}
+	if err = runner.Run(ctx); err != nil {
+		return "", err
+	}
+
+	buf := new(bytes.Buffer)
+	err = b.Filename.Execute(buf, FileData{
+		Board:  strings.Replace(runCfg.Name, " ", "_", -1),
+		Metric: metric,
+		Year:   runCfg.StartDate.Year(),
+		Month:  fmt.Sprintf("%02d", runCfg.StartDate.Month()),
+		Date:   runCfg.StartDate.Format("2006-01-02"),
+		Ext:    b.Format.Ext(),
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "filename")
+	}
+
+	path := filepath.Join(b.Destination, buf.String())
+	if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	if err = output.Write(f, b.Format, output.Result{Rows: runner.Values(), Records: runner.Results()}); err != nil {
+		f.Close()
+		return "", err
+	}
+	return path, f.Close()
+}
+
+// Failed - returns the results that failed
+func Failed(results []Result) []Result {
+	failed := make([]Result, 0)
+	for _, result := range results {
+		if result.Err != nil {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
@@ -0,0 +1,93 @@
+package batch_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/3xcellent/github-metrics/batch"
+	"github.com/3xcellent/github-metrics/config"
+	"github.com/3xcellent/github-metrics/metrics/runners"
+	"github.com/3xcellent/github-metrics/metrics/runners/runnersfakes"
+	"github.com/3xcellent/github-metrics/models"
+	"github.com/3xcellent/github-metrics/output"
+	"github.com/3xcellent/github-metrics/tools/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatch_Run(t *testing.T) {
+	cols := testhelpers.NewProjectColumns(3)
+	start := time.Date(2001, 2, 1, 0, 0, 0, 0, time.Local)
+	runConfigs := config.RunConfigs{
+		{Name: "My Board", ProjectID: 1, Owner: "owner", StartColumn: cols[1].Name, EndColumn: cols[2].Name, StartDate: start, EndDate: start.AddDate(0, 1, 0)},
+		{Name: "Other", ProjectID: 2, Owner: "owner", StartColumn: cols[1].Name, EndColumn: cols[2].Name, StartDate: start, EndDate: start.AddDate(0, 1, 0)},
+	}
+
+	fakeClient := new(runnersfakes.FakeClient)
+	fakeClient.GetProjectStub = func(ctx context.Context, projectID int64) (models.Project, error) {
+		if projectID == 2 {
+			return models.Project{}, errors.New("not found")
+		}
+		return models.Project{ID: projectID, Name: "My Board"}, nil
+	}
+	fakeClient.GetProjectColumnsReturns(cols, nil)
+	fakeClient.GetReposFromProjectColumnReturns(models.Repositories{{Name: "repo"}}, nil)
+	fakeClient.GetIssuesReturns(models.Issues{{Owner: "owner", RepoName: "repo", Number: 1}}, nil)
+
+	dir, err := ioutil.TempDir("", "batch")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	b, err := batch.New(fakeClient, output.JSON, dir, "")
+	require.NoError(t, err)
+	results := b.Run(context.Background(), runConfigs, []string{"issues", "columns"})
+	require.Len(t, results, 4)
+
+	t.Run("writes each metric of each board", func(t *testing.T) {
+		assert.NoError(t, results[0].Err)
+		assert.Equal(t, filepath.Join(dir, "My_Board_issues_2001-02.json"), results[0].File)
+		assert.FileExists(t, results[0].File)
+		assert.NoError(t, results[1].Err)
+		assert.Equal(t, filepath.Join(dir, "My_Board_columns_2001-02.json"), results[1].File)
+		assert.FileExists(t, results[1].File)
+	})
+
+	t.Run("fetches the data of a board once for all its metrics", func(t *testing.T) {
+		assert.Equal(t, 1, fakeClient.GetIssuesCallCount())
+		assert.Equal(t, 1, fakeClient.GetIssueEventsCallCount())
+	})
+
+	t.Run("keeps running after a failure", func(t *testing.T) {
+		failed := batch.Failed(results)
+		require.Len(t, failed, 2)
+		assert.Equal(t, "Other", failed[0].Board)
+		assert.EqualError(t, failed[0].Err, "not found")
+	})
+
+	t.Run("runs boards with a connection profile with its client", func(t *testing.T) {
+		profileClient := new(runnersfakes.FakeClient)
+		profileClient.GetProjectReturns(models.Project{ID: 3, Name: "Internal"}, nil)
+		profileClient.GetProjectColumnsReturns(cols, nil)
+		profileClient.GetReposFromProjectColumnReturns(models.Repositories{{Name: "repo"}}, nil)
+		b.Profiles = runners.ProfileClients{"enterprise": profileClient}
+		defer func() { b.Profiles = nil }()
+
+		internal := runConfigs[0]
+		internal.Name, internal.ProjectID, internal.Profile = "Internal", 3, "enterprise"
+		results := b.Run(context.Background(), config.RunConfigs{internal}, []string{"issues"})
+		require.Len(t, results, 1)
+		assert.NoError(t, results[0].Err)
+		assert.Equal(t, 1, profileClient.GetIssuesCallCount())
+		assert.Equal(t, 1, fakeClient.GetIssuesCallCount())
+	})
+
+	t.Run("rejects invalid filename templates", func(t *testing.T) {
+		_, err := batch.New(fakeClient, output.CSV, dir, "{{.Board")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,205 @@
+package charts
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
+)
+
+// point - x,y pixel coordinates on a canvas
+type point struct {
+	X, Y float64
+}
+
+// textAnchor - horizontal alignment of text relative to its x coordinate
+type textAnchor string
+
+const (
+	anchorStart  textAnchor = "start"
+	anchorMiddle textAnchor = "middle"
+	anchorEnd    textAnchor = "end"
+)
+
+// canvas - drawing primitives implemented by each output format
+type canvas interface {
+	Polygon(pts []point, fill color.NRGBA)
+	Line(from, to point, stroke color.NRGBA, width float64, dashed bool)
+	Rect(x, y, w, h float64, fill color.NRGBA)
+	Circle(center point, r float64, fill color.NRGBA)
+	Text(at point, s string, anchor textAnchor, fill color.NRGBA)
+}
+
+// drawer - a chart that can lay itself out on a canvas of the size it reports
+type drawer interface {
+	size() (width, height int)
+	draw(c canvas)
+}
+
+// renderSVG - draws d as a standalone svg document to w
+func renderSVG(w io.Writer, d drawer) error {
+	width, height := d.size()
+	c := &svgCanvas{}
+	d.draw(c)
+
+	_, err := fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="12">`+"\n", width, height, width, height)
+	if err != nil {
+		return err
+	}
+	if _, err = fmt.Fprintf(w, `<rect width="%d" height="%d" fill="white"/>`+"\n", width, height); err != nil {
+		return err
+	}
+	if _, err = c.buf.WriteTo(w); err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "</svg>\n")
+	return err
+}
+
+// renderPNG - rasterizes d and encodes it as png to w
+func renderPNG(w io.Writer, d drawer) error {
+	width, height := d.size()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+	d.draw(&pngCanvas{img: img})
+	return png.Encode(w, img)
+}
+
+type svgCanvas struct {
+	buf bytes.Buffer
+}
+
+func svgColor(c color.NRGBA) string {
+	if c.A == 0xff {
+		return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+	}
+	return fmt.Sprintf("rgba(%d,%d,%d,%.2f)", c.R, c.G, c.B, float64(c.A)/0xff)
+}
+
+func (s *svgCanvas) Polygon(pts []point, fill color.NRGBA) {
+	if len(pts) == 0 {
+		return
+	}
+	s.buf.WriteString(`<path d="`)
+	for i, p := range pts {
+		cmd := "L"
+		if i == 0 {
+			cmd = "M"
+		}
+		fmt.Fprintf(&s.buf, "%s%.1f %.1f ", cmd, p.X, p.Y)
+	}
+	fmt.Fprintf(&s.buf, `Z" fill="%s"/>`+"\n", svgColor(fill))
+}
+
+func (s *svgCanvas) Line(from, to point, stroke color.NRGBA, width float64, dashed bool) {
+	dash := ""
+	if dashed {
+		dash = ` stroke-dasharray="6 4"`
+	}
+	fmt.Fprintf(&s.buf, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s" stroke-width="%.1f"%s/>`+"\n",
+		from.X, from.Y, to.X, to.Y, svgColor(stroke), width, dash)
+}
+
+func (s *svgCanvas) Rect(x, y, w, h float64, fill color.NRGBA) {
+	fmt.Fprintf(&s.buf, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"/>`+"\n", x, y, w, h, svgColor(fill))
+}
+
+func (s *svgCanvas) Circle(center point, r float64, fill color.NRGBA) {
+	fmt.Fprintf(&s.buf, `<circle cx="%.1f" cy="%.1f" r="%.1f" fill="%s"/>`+"\n", center.X, center.Y, r, svgColor(fill))
+}
+
+func (s *svgCanvas) Text(at point, text string, anchor textAnchor, fill color.NRGBA) {
+	fmt.Fprintf(&s.buf, `<text x="%.1f" y="%.1f" text-anchor="%s" fill="%s">%s</text>`+"\n",
+		at.X, at.Y, anchor, svgColor(fill), html.EscapeString(text))
+}
+
+type pngCanvas struct {
+	img *image.RGBA
+}
+
+func (p *pngCanvas) fill(pts []point, c color.NRGBA) {
+	if len(pts) < 3 {
+		return
+	}
+	b := p.img.Bounds()
+	z := vector.NewRasterizer(b.Dx(), b.Dy())
+	z.MoveTo(float32(pts[0].X), float32(pts[0].Y))
+	for _, pt := range pts[1:] {
+		z.LineTo(float32(pt.X), float32(pt.Y))
+	}
+	z.ClosePath()
+	z.Draw(p.img, b, image.NewUniform(c), image.Point{})
+}
+
+func (p *pngCanvas) Polygon(pts []point, fill color.NRGBA) {
+	p.fill(pts, fill)
+}
+
+func (p *pngCanvas) Line(from, to point, stroke color.NRGBA, width float64, dashed bool) {
+	dx, dy := to.X-from.X, to.Y-from.Y
+	length := math.Hypot(dx, dy)
+	if length == 0 {
+		return
+	}
+	segments := [][2]float64{{0, 1}}
+	if dashed {
+		segments = segments[:0]
+		for start := 0.0; start < length; start += 10 {
+			segments = append(segments, [2]float64{start / length, math.Min(start+6, length) / length})
+		}
+	}
+	// offset perpendicular to the line by half the stroke width
+	nx, ny := -dy/length*width/2, dx/length*width/2
+	for _, seg := range segments {
+		a := point{from.X + dx*seg[0], from.Y + dy*seg[0]}
+		b := point{from.X + dx*seg[1], from.Y + dy*seg[1]}
+		p.fill([]point{
+			{a.X + nx, a.Y + ny},
+			{b.X + nx, b.Y + ny},
+			{b.X - nx, b.Y - ny},
+			{a.X - nx, a.Y - ny},
+		}, stroke)
+	}
+}
+
+func (p *pngCanvas) Rect(x, y, w, h float64, fill color.NRGBA) {
+	p.fill([]point{{x, y}, {x + w, y}, {x + w, y + h}, {x, y + h}}, fill)
+}
+
+func (p *pngCanvas) Circle(center point, r float64, fill color.NRGBA) {
+	const steps = 16
+	pts := make([]point, 0, steps)
+	for i := 0; i < steps; i++ {
+		a := 2 * math.Pi * float64(i) / steps
+		pts = append(pts, point{center.X + r*math.Cos(a), center.Y + r*math.Sin(a)})
+	}
+	p.fill(pts, fill)
+}
+
+func (p *pngCanvas) Text(at point, text string, anchor textAnchor, fill color.NRGBA) {
+	face := basicfont.Face7x13
+	d := font.Drawer{
+		Dst:  p.img,
+		Src:  image.NewUniform(fill),
+		Face: face,
+	}
+	x := at.X
+	switch anchor {
+	case anchorMiddle:
+		x -= float64(d.MeasureString(text).Round()) / 2
+	case anchorEnd:
+		x -= float64(d.MeasureString(text).Round())
+	}
+	d.Dot = fixed.P(int(x), int(at.Y))
+	d.DrawString(text)
+}
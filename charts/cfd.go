@@ -0,0 +1,147 @@
+package charts
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/3xcellent/github-metrics/config"
+	"github.com/3xcellent/github-metrics/metrics"
+	"github.com/3xcellent/github-metrics/metrics/runners"
+	"github.com/pkg/errors"
+)
+
+// Annotation - a labeled vertical marker drawn at Date
+type Annotation struct {
+	Date  time.Time
+	Label string
+}
+
+// Annotations - parses config annotations using the metrics date key format
+func Annotations(cfgAnnotations []config.Annotation, loc *time.Location) ([]Annotation, error) {
+	annotations := make([]Annotation, 0, len(cfgAnnotations))
+	for _, a := range cfgAnnotations {
+		date, err := time.ParseInLocation(metrics.FmtDateKey, a.Date, loc)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid annotation date %q", a.Date)
+		}
+		annotations = append(annotations, Annotation{Date: date, Label: a.Label})
+	}
+	return annotations, nil
+}
+
+// CumulativeFlow - stacked area chart of the number of issues in each column per day
+type CumulativeFlow struct {
+	Title       string
+	Columns     []string
+	Dates       []time.Time
+	Counts      metrics.DateColMap
+	Annotations []Annotation
+	Width       int
+	Height      int
+}
+
+// NewCumulativeFlow - returns a CumulativeFlow for the dates and columns of a completed ColumnsRunner
+func NewCumulativeFlow(r *runners.ColumnsRunner) *CumulativeFlow {
+	dates := make([]time.Time, 0)
+	for d := r.StartDate; d.Before(r.EndDate); d = d.AddDate(0, 0, 1) {
+		dates = append(dates, d)
+	}
+	return &CumulativeFlow{
+		Title:   fmt.Sprintf("%s - Cumulative Flow %d-%02d", r.ProjectName, r.StartDate.Year(), r.StartDate.Month()),
+		Columns: r.ColumnNames,
+		Dates:   dates,
+		Counts:  r.Cols,
+		Width:   DefaultWidth,
+		Height:  DefaultHeight,
+	}
+}
+
+// SVG - writes the chart as an svg document
+func (cfd *CumulativeFlow) SVG(w io.Writer) error {
+	return renderSVG(w, cfd)
+}
+
+// PNG - writes the chart as a png image
+func (cfd *CumulativeFlow) PNG(w io.Writer) error {
+	return renderPNG(w, cfd)
+}
+
+func (cfd *CumulativeFlow) size() (int, int) {
+	return cfd.Width, cfd.Height
+}
+
+// stacks - returns the cumulative totals for each date with the last column at the bottom;
+// stacks[col][date] is the top edge of the band for Columns[col]
+func (cfd *CumulativeFlow) stacks() ([][]float64, float64) {
+	stacks := make([][]float64, len(cfd.Columns))
+	for col := range stacks {
+		stacks[col] = make([]float64, len(cfd.Dates))
+	}
+	max := 0.0
+	for dateIdx, date := range cfd.Dates {
+		total := 0.0
+		for col := len(cfd.Columns) - 1; col >= 0; col-- {
+			val, _ := cfd.Counts.DateColumn(date, cfd.Columns[col])
+			total += float64(val)
+			stacks[col][dateIdx] = total
+		}
+		max = math.Max(max, total)
+	}
+	return stacks, max
+}
+
+func (cfd *CumulativeFlow) draw(c canvas) {
+	a := newPlotArea(cfd.Width, cfd.Height)
+	stacks, max := cfd.stacks()
+	yMax, yStep := countAxisMax(max, 5)
+	drawFrame(c, a, cfd.Title, "Date", "Issues", yMax, yStep)
+
+	lastIdx := float64(len(cfd.Dates) - 1)
+	xAt := func(idx int) float64 { return a.x(float64(idx), 0, lastIdx) }
+
+	legend := make([]legendItem, 0, len(cfd.Columns))
+	for col, name := range cfd.Columns {
+		legend = append(legend, legendItem{Name: name, Color: seriesColor(col)})
+		if len(cfd.Dates) == 0 {
+			continue
+		}
+		band := make([]point, 0, len(cfd.Dates)*2)
+		for dateIdx := range cfd.Dates {
+			band = append(band, point{xAt(dateIdx), a.y(stacks[col][dateIdx], yMax)})
+		}
+		for dateIdx := len(cfd.Dates) - 1; dateIdx >= 0; dateIdx-- {
+			lower := 0.0
+			if col < len(cfd.Columns)-1 {
+				lower = stacks[col+1][dateIdx]
+			}
+			band = append(band, point{xAt(dateIdx), a.y(lower, yMax)})
+		}
+		c.Polygon(band, seriesColor(col))
+	}
+	drawLegend(c, a, legend)
+
+	step := int(math.Ceil(float64(len(cfd.Dates)) / 10))
+	for dateIdx := 0; dateIdx < len(cfd.Dates); dateIdx += step {
+		x := xAt(dateIdx)
+		c.Line(point{x, a.Bottom()}, point{x, a.Bottom() + 4}, black, 1, false)
+		c.Text(point{x, a.Bottom() + 18}, cfd.Dates[dateIdx].Format("Jan 02"), anchorMiddle, black)
+	}
+
+	for _, annotation := range cfd.Annotations {
+		idx := -1
+		for dateIdx, date := range cfd.Dates {
+			if metrics.DateKey(date) == metrics.DateKey(annotation.Date) {
+				idx = dateIdx
+				break
+			}
+		}
+		if idx < 0 {
+			continue
+		}
+		x := xAt(idx)
+		c.Line(point{x, a.Top}, point{x, a.Bottom()}, black, 1.5, true)
+		c.Text(point{x + 4, a.Top + 12}, annotation.Label, anchorStart, black)
+	}
+}
@@ -0,0 +1,93 @@
+package charts_test
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/3xcellent/github-metrics/charts"
+	"github.com/3xcellent/github-metrics/config"
+	"github.com/3xcellent/github-metrics/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCFD() *charts.CumulativeFlow {
+	startDate := time.Date(2001, 2, 3, 0, 0, 0, 0, time.UTC)
+	dates := []time.Time{startDate, startDate.AddDate(0, 0, 1), startDate.AddDate(0, 0, 2)}
+	return &charts.CumulativeFlow{
+		Title:   "Board - Cumulative Flow",
+		Columns: []string{"In Progress", "Review & QA", "Done"},
+		Dates:   dates,
+		Counts: metrics.DateColMap{
+			"2001-02-03": {"In Progress": 2},
+			"2001-02-04": {"In Progress": 1, "Review & QA": 1},
+			"2001-02-05": {"Review & QA": 1, "Done": 1},
+		},
+		Annotations: []charts.Annotation{{Date: dates[1], Label: "release"}},
+		Width:       charts.DefaultWidth,
+		Height:      charts.DefaultHeight,
+	}
+}
+
+func TestCumulativeFlow_SVG(t *testing.T) {
+	var buf bytes.Buffer
+	err := newTestCFD().SVG(&buf)
+	require.NoError(t, err)
+	svg := buf.String()
+
+	t.Run("writes a standalone svg document", func(t *testing.T) {
+		assert.True(t, strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg"`))
+		assert.True(t, strings.HasSuffix(svg, "</svg>\n"))
+	})
+
+	t.Run("draws a band for each column", func(t *testing.T) {
+		assert.Equal(t, 3, strings.Count(svg, "<path "))
+	})
+
+	t.Run("lists columns in the legend in board order with escaped names", func(t *testing.T) {
+		inProgress := strings.Index(svg, ">In Progress<")
+		review := strings.Index(svg, ">Review &amp; QA<")
+		done := strings.Index(svg, ">Done<")
+		require.True(t, inProgress > 0 && review > 0 && done > 0)
+		assert.True(t, inProgress < review && review < done)
+	})
+
+	t.Run("labels the date axis", func(t *testing.T) {
+		assert.Contains(t, svg, ">Feb 03<")
+		assert.Contains(t, svg, ">Feb 05<")
+	})
+
+	t.Run("draws annotation markers", func(t *testing.T) {
+		assert.Contains(t, svg, `stroke-dasharray`)
+		assert.Contains(t, svg, ">release<")
+	})
+}
+
+func TestCumulativeFlow_PNG(t *testing.T) {
+	var buf bytes.Buffer
+	err := newTestCFD().PNG(&buf)
+	require.NoError(t, err)
+
+	t.Run("writes a png of the chart dimensions", func(t *testing.T) {
+		img, err := png.Decode(&buf)
+		require.NoError(t, err)
+		assert.Equal(t, charts.DefaultWidth, img.Bounds().Dx())
+		assert.Equal(t, charts.DefaultHeight, img.Bounds().Dy())
+	})
+}
+
+func TestAnnotations(t *testing.T) {
+	t.Run("parses dates using the date key format", func(t *testing.T) {
+		annotations, err := charts.Annotations([]config.Annotation{{Date: "2001-02-04", Label: "release"}}, time.UTC)
+		require.NoError(t, err)
+		assert.Equal(t, []charts.Annotation{{Date: time.Date(2001, 2, 4, 0, 0, 0, 0, time.UTC), Label: "release"}}, annotations)
+	})
+
+	t.Run("returns an error for invalid dates", func(t *testing.T) {
+		_, err := charts.Annotations([]config.Annotation{{Date: "02/04/2001"}}, time.UTC)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,154 @@
+// Package charts renders metrics results as standalone SVG and PNG images
+package charts
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+)
+
+// default chart dimensions in pixels
+const (
+	DefaultWidth  = 960
+	DefaultHeight = 540
+
+	marginTop    = 48
+	marginBottom = 56
+	marginLeft   = 64
+	legendWidth  = 180
+	legendRow    = 18
+)
+
+var (
+	black     = color.NRGBA{A: 0xff}
+	gray      = color.NRGBA{R: 0x88, G: 0x88, B: 0x88, A: 0xff}
+	lightGray = color.NRGBA{R: 0xe0, G: 0xe0, B: 0xe0, A: 0xff}
+
+	// palette - colors assigned to series in order, repeating when exhausted
+	palette = []color.NRGBA{
+		{R: 0x1f, G: 0x77, B: 0xb4, A: 0xff},
+		{R: 0xff, G: 0x7f, B: 0x0e, A: 0xff},
+		{R: 0x2c, G: 0xa0, B: 0x2c, A: 0xff},
+		{R: 0xd6, G: 0x27, B: 0x28, A: 0xff},
+		{R: 0x94, G: 0x67, B: 0xbd, A: 0xff},
+		{R: 0x8c, G: 0x56, B: 0x4b, A: 0xff},
+		{R: 0xe3, G: 0x77, B: 0xc2, A: 0xff},
+		{R: 0x7f, G: 0x7f, B: 0x7f, A: 0xff},
+		{R: 0xbc, G: 0xbd, B: 0x22, A: 0xff},
+		{R: 0x17, G: 0xbe, B: 0xcf, A: 0xff},
+	}
+)
+
+// seriesColor - returns the palette color for the series at index idx
+func seriesColor(idx int) color.NRGBA {
+	return palette[idx%len(palette)]
+}
+
+// plotArea - the rectangle inside the margins where data is drawn
+type plotArea struct {
+	Left, Top, Width, Height float64
+}
+
+func newPlotArea(width, height int) plotArea {
+	return plotArea{
+		Left:   marginLeft,
+		Top:    marginTop,
+		Width:  float64(width) - marginLeft - legendWidth,
+		Height: float64(height) - marginTop - marginBottom,
+	}
+}
+
+func (a plotArea) Right() float64  { return a.Left + a.Width }
+func (a plotArea) Bottom() float64 { return a.Top + a.Height }
+
+// y - maps value within [0, max] to a vertical pixel position
+func (a plotArea) y(value, max float64) float64 {
+	if max <= 0 {
+		return a.Bottom()
+	}
+	return a.Bottom() - value/max*a.Height
+}
+
+// x - maps value within [min, max] to a horizontal pixel position
+func (a plotArea) x(value, min, max float64) float64 {
+	if max <= min {
+		return a.Left + a.Width/2
+	}
+	return a.Left + (value-min)/(max-min)*a.Width
+}
+
+// niceStep - rounds rough up to 1, 2, 5 or 10 times a power of ten
+func niceStep(rough float64) float64 {
+	if rough <= 0 {
+		return 1
+	}
+	exp := math.Pow(10, math.Floor(math.Log10(rough)))
+	for _, m := range []float64{1, 2, 5, 10} {
+		if rough <= m*exp {
+			return m * exp
+		}
+	}
+	return 10 * exp
+}
+
+// axisMax - returns a rounded maximum for the axis and the distance between its ticks
+func axisMax(max float64, ticks int) (float64, float64) {
+	step := niceStep(max / float64(ticks))
+	top := math.Ceil(max/step) * step
+	if top == 0 {
+		top = step
+	}
+	return top, step
+}
+
+// countAxisMax - axisMax for whole number values, ticks are never less than 1 apart
+func countAxisMax(max float64, ticks int) (float64, float64) {
+	top, step := axisMax(max, ticks)
+	if step < 1 {
+		return math.Max(math.Ceil(top), 1), 1
+	}
+	return top, step
+}
+
+func fmtTick(v float64) string {
+	if v == math.Trunc(v) {
+		return fmt.Sprintf("%.0f", v)
+	}
+	return fmt.Sprintf("%.1f", v)
+}
+
+// drawFrame - draws the title, axis lines, y axis ticks and gridlines and the axis labels
+func drawFrame(c canvas, a plotArea, title, xLabel, yLabel string, yMax, yStep float64) {
+	c.Text(point{a.Left, marginTop / 2}, title, anchorStart, black)
+
+	for v := 0.0; v <= yMax+yStep/2; v += yStep {
+		y := a.y(v, yMax)
+		if v > 0 {
+			c.Line(point{a.Left, y}, point{a.Right(), y}, lightGray, 1, false)
+		}
+		c.Line(point{a.Left - 4, y}, point{a.Left, y}, black, 1, false)
+		c.Text(point{a.Left - 8, y + 4}, fmtTick(v), anchorEnd, black)
+	}
+
+	c.Line(point{a.Left, a.Top}, point{a.Left, a.Bottom()}, black, 1, false)
+	c.Line(point{a.Left, a.Bottom()}, point{a.Right(), a.Bottom()}, black, 1, false)
+
+	c.Text(point{a.Left + a.Width/2, a.Bottom() + 40}, xLabel, anchorMiddle, black)
+	c.Text(point{a.Left, a.Top - 8}, yLabel, anchorEnd, gray)
+}
+
+// legendItem - name and color of a series shown in the legend
+type legendItem struct {
+	Name  string
+	Color color.NRGBA
+}
+
+// drawLegend - lists items top to bottom to the right of the plot area
+func drawLegend(c canvas, a plotArea, items []legendItem) {
+	x := a.Right() + 16
+	for i, item := range items {
+		y := a.Top + float64(i)*legendRow
+		c.Rect(x, y, 12, 12, item.Color)
+		c.Text(point{x + 18, y + 11}, item.Name, anchorStart, black)
+	}
+}
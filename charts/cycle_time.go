@@ -0,0 +1,225 @@
+package charts
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/3xcellent/github-metrics/metrics"
+	"github.com/3xcellent/github-metrics/metrics/runners"
+)
+
+// percentiles drawn as reference lines on cycle time charts
+var cycleTimePercentiles = []float64{50, 85, 95}
+
+// CycleTime - the cycle time of a single completed issue
+type CycleTime struct {
+	Completed time.Time
+	Days      float64
+	Type      string
+}
+
+// CycleTimes - slice of CycleTime
+type CycleTimes []CycleTime
+
+// NewCycleTimes - returns the cycle times of the issues completed during an IssuesRunner run
+func NewCycleTimes(r *runners.IssuesRunner) CycleTimes {
+	cycleTimes := make(CycleTimes, 0)
+	for _, issue := range r.CompletedIssues() {
+		cycleTimes = append(cycleTimes, CycleTime{
+			Completed: issue.CompletedAt(),
+			Days:      issue.CalcDays(),
+			Type:      issue.Type,
+		})
+	}
+	return cycleTimes
+}
+
+// Days - returns the cycle time days of each issue
+func (cts CycleTimes) Days() []float64 {
+	days := make([]float64, 0, len(cts))
+	for _, ct := range cts {
+		days = append(days, ct.Days)
+	}
+	return days
+}
+
+// Types - returns the sorted, distinct issue types
+func (cts CycleTimes) Types() []string {
+	found := map[string]bool{}
+	types := make([]string, 0)
+	for _, ct := range cts {
+		if !found[ct.Type] {
+			found[ct.Type] = true
+			types = append(types, ct.Type)
+		}
+	}
+	sort.Strings(types)
+	return types
+}
+
+func (cts CycleTimes) maxDays() float64 {
+	max := 0.0
+	for _, ct := range cts {
+		max = math.Max(max, ct.Days)
+	}
+	return max
+}
+
+func typeLegend(types []string) ([]legendItem, map[string]int) {
+	legend := make([]legendItem, 0, len(types))
+	colorIdx := map[string]int{}
+	for idx, t := range types {
+		colorIdx[t] = idx
+		legend = append(legend, legendItem{Name: t, Color: seriesColor(idx)})
+	}
+	return legend, colorIdx
+}
+
+// CycleTimeScatter - scatterplot of completion date vs cycle time days with percentile lines
+type CycleTimeScatter struct {
+	Title      string
+	StartDate  time.Time
+	EndDate    time.Time
+	CycleTimes CycleTimes
+	Width      int
+	Height     int
+}
+
+// NewCycleTimeScatter - returns a CycleTimeScatter for a completed IssuesRunner
+func NewCycleTimeScatter(r *runners.IssuesRunner) *CycleTimeScatter {
+	return &CycleTimeScatter{
+		Title:      fmt.Sprintf("%s - Cycle Time %d-%02d", r.ProjectName, r.StartDate.Year(), r.StartDate.Month()),
+		StartDate:  r.StartDate,
+		EndDate:    r.EndDate,
+		CycleTimes: NewCycleTimes(r),
+		Width:      DefaultWidth,
+		Height:     DefaultHeight,
+	}
+}
+
+// SVG - writes the chart as an svg document
+func (s *CycleTimeScatter) SVG(w io.Writer) error {
+	return renderSVG(w, s)
+}
+
+func (s *CycleTimeScatter) size() (int, int) {
+	return s.Width, s.Height
+}
+
+func (s *CycleTimeScatter) draw(c canvas) {
+	a := newPlotArea(s.Width, s.Height)
+	yMax, yStep := axisMax(s.CycleTimes.maxDays(), 5)
+	drawFrame(c, a, s.Title, "Completed", "Days", yMax, yStep)
+
+	start, end := float64(s.StartDate.Unix()), float64(s.EndDate.Unix())
+	days := int(math.Round(s.EndDate.Sub(s.StartDate).Hours() / 24))
+	step := int(math.Ceil(float64(days) / 10))
+	if step < 1 {
+		step = 1
+	}
+	for d := s.StartDate; d.Before(s.EndDate); d = d.AddDate(0, 0, step) {
+		x := a.x(float64(d.Unix()), start, end)
+		c.Line(point{x, a.Bottom()}, point{x, a.Bottom() + 4}, black, 1, false)
+		c.Text(point{x, a.Bottom() + 18}, d.Format("Jan 02"), anchorMiddle, black)
+	}
+
+	legend, colorIdx := typeLegend(s.CycleTimes.Types())
+	for _, ct := range s.CycleTimes {
+		x := a.x(float64(ct.Completed.Unix()), start, end)
+		c.Circle(point{x, a.y(ct.Days, yMax)}, 4, seriesColor(colorIdx[ct.Type]))
+	}
+
+	if len(s.CycleTimes) > 0 {
+		days := s.CycleTimes.Days()
+		for _, p := range cycleTimePercentiles {
+			value := metrics.Percentile(days, p)
+			y := a.y(value, yMax)
+			c.Line(point{a.Left, y}, point{a.Right(), y}, gray, 1, true)
+			c.Text(point{a.Right() - 4, y - 4}, fmt.Sprintf("p%.0f %.1fd", p, value), anchorEnd, gray)
+		}
+	}
+	drawLegend(c, a, legend)
+}
+
+// CycleTimeHistogram - number of completed issues per cycle time bin, stacked by issue type
+type CycleTimeHistogram struct {
+	Title      string
+	CycleTimes CycleTimes
+	Width      int
+	Height     int
+}
+
+// NewCycleTimeHistogram - returns a CycleTimeHistogram for a completed IssuesRunner
+func NewCycleTimeHistogram(r *runners.IssuesRunner) *CycleTimeHistogram {
+	return &CycleTimeHistogram{
+		Title:      fmt.Sprintf("%s - Cycle Time Distribution %d-%02d", r.ProjectName, r.StartDate.Year(), r.StartDate.Month()),
+		CycleTimes: NewCycleTimes(r),
+		Width:      DefaultWidth,
+		Height:     DefaultHeight,
+	}
+}
+
+// SVG - writes the chart as an svg document
+func (h *CycleTimeHistogram) SVG(w io.Writer) error {
+	return renderSVG(w, h)
+}
+
+func (h *CycleTimeHistogram) size() (int, int) {
+	return h.Width, h.Height
+}
+
+// bins - returns the width of each bin in days and the counts per type for each bin
+func (h *CycleTimeHistogram) bins() (float64, []map[string]int) {
+	width := math.Max(1, niceStep(h.CycleTimes.maxDays()/20))
+	numBins := int(math.Floor(h.CycleTimes.maxDays()/width)) + 1
+	bins := make([]map[string]int, numBins)
+	for idx := range bins {
+		bins[idx] = map[string]int{}
+	}
+	for _, ct := range h.CycleTimes {
+		bins[int(math.Floor(ct.Days/width))][ct.Type]++
+	}
+	return width, bins
+}
+
+func (h *CycleTimeHistogram) draw(c canvas) {
+	a := newPlotArea(h.Width, h.Height)
+	binWidth, bins := h.bins()
+	types := h.CycleTimes.Types()
+
+	maxCount := 0
+	for _, bin := range bins {
+		total := 0
+		for _, count := range bin {
+			total += count
+		}
+		if total > maxCount {
+			maxCount = total
+		}
+	}
+	yMax, yStep := countAxisMax(float64(maxCount), 5)
+	drawFrame(c, a, h.Title, "Cycle Time (days)", "Issues", yMax, yStep)
+
+	legend, colorIdx := typeLegend(types)
+	barWidth := a.Width / float64(len(bins))
+	step := int(math.Ceil(float64(len(bins)) / 10))
+	for idx, bin := range bins {
+		x := a.Left + float64(idx)*barWidth
+		total := 0
+		for _, t := range types {
+			if bin[t] == 0 {
+				continue
+			}
+			top := a.y(float64(total+bin[t]), yMax)
+			c.Rect(x+1, top, barWidth-2, a.y(float64(total), yMax)-top, seriesColor(colorIdx[t]))
+			total += bin[t]
+		}
+		if idx%step == 0 {
+			c.Text(point{x, a.Bottom() + 18}, fmtTick(float64(idx)*binWidth), anchorMiddle, black)
+		}
+	}
+	drawLegend(c, a, legend)
+}
@@ -0,0 +1,80 @@
+package charts_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/3xcellent/github-metrics/charts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testStartDate  = time.Date(2001, 2, 1, 0, 0, 0, 0, time.UTC)
+	testCycleTimes = charts.CycleTimes{
+		{Completed: testStartDate.AddDate(0, 0, 3), Days: 2.5, Type: "Enhancement"},
+		{Completed: testStartDate.AddDate(0, 0, 5), Days: 1.2, Type: "Bug"},
+		{Completed: testStartDate.AddDate(0, 0, 9), Days: 7.8, Type: "Enhancement"},
+		{Completed: testStartDate.AddDate(0, 0, 20), Days: 3.1, Type: "Tech Debt"},
+	}
+)
+
+func TestCycleTimes_Types(t *testing.T) {
+	t.Run("returns distinct sorted types", func(t *testing.T) {
+		assert.Equal(t, []string{"Bug", "Enhancement", "Tech Debt"}, testCycleTimes.Types())
+	})
+}
+
+func TestCycleTimeScatter_SVG(t *testing.T) {
+	scatter := &charts.CycleTimeScatter{
+		Title:      "Board - Cycle Time",
+		StartDate:  testStartDate,
+		EndDate:    testStartDate.AddDate(0, 1, 0),
+		CycleTimes: testCycleTimes,
+		Width:      charts.DefaultWidth,
+		Height:     charts.DefaultHeight,
+	}
+	var buf bytes.Buffer
+	require.NoError(t, scatter.SVG(&buf))
+	svg := buf.String()
+
+	t.Run("draws a point for each issue", func(t *testing.T) {
+		assert.Equal(t, len(testCycleTimes), strings.Count(svg, "<circle "))
+	})
+
+	t.Run("draws p50, p85 and p95 lines", func(t *testing.T) {
+		assert.Contains(t, svg, ">p50 2.8d<")
+		assert.Contains(t, svg, ">p85 5.7d<")
+		assert.Contains(t, svg, ">p95 7.1d<")
+	})
+
+	t.Run("lists issue types in the legend", func(t *testing.T) {
+		for _, issueType := range testCycleTimes.Types() {
+			assert.Contains(t, svg, ">"+issueType+"<")
+		}
+	})
+}
+
+func TestCycleTimeHistogram_SVG(t *testing.T) {
+	histogram := &charts.CycleTimeHistogram{
+		Title:      "Board - Cycle Time Distribution",
+		CycleTimes: testCycleTimes,
+		Width:      charts.DefaultWidth,
+		Height:     charts.DefaultHeight,
+	}
+	var buf bytes.Buffer
+	require.NoError(t, histogram.SVG(&buf))
+	svg := buf.String()
+
+	t.Run("draws a bar segment for each type in each occupied one day bin", func(t *testing.T) {
+		// 3 legend swatches + 4 bars (bins 1, 2, 3 and 7 each hold one issue) + background
+		assert.Equal(t, 3+4+1, strings.Count(svg, "<rect "))
+	})
+
+	t.Run("labels the bins in days", func(t *testing.T) {
+		assert.Contains(t, svg, ">0<")
+		assert.Contains(t, svg, ">7<")
+	})
+}
@@ -2,22 +2,27 @@ package client
 
 import (
 	"context"
-	"errors"
+	"fmt"
 	"regexp"
 	"strconv"
 
+	"github.com/3xcellent/github-metrics/apperrors"
 	"github.com/3xcellent/github-metrics/config"
+	"github.com/3xcellent/github-metrics/logging"
 	"github.com/google/go-github/v32/github"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/oauth2"
 )
 
+var logger = logging.For("client")
+
 const archived = "archived"
 const all = "all"
 
 // MetricsClient provides access to user datea through an authenticated github.Client connection
 type MetricsClient struct {
-	c *github.Client
+	c     *github.Client
+	stats *statsTransport
 }
 
 // errors
@@ -30,49 +35,45 @@ const (
 func New(ctx context.Context, config config.APIConfig) (*MetricsClient, error) {
 	token := config.Token
 	if token == "" {
-		return nil, errors.New(ErrAccessTokenNotSet)
+		return nil, apperrors.New(apperrors.Auth, ErrAccessTokenNotSet)
 	}
+	// the token is never logged, wherever it appears
+	logging.AddSecret(token)
 
 	authenticatedClient := oauth2.NewClient(ctx, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}))
+	stats := newStatsTransport(authenticatedClient.Transport)
+	authenticatedClient.Transport = stats
 	if config.BaseURL == "" {
-		logrus.Debugf("creating new client with token: %s", token)
-		return &MetricsClient{c: github.NewClient(authenticatedClient)}, nil
+		logger.Debug("creating new client")
+		return &MetricsClient{c: github.NewClient(authenticatedClient), stats: stats}, nil
 	}
 
 	if config.UploadURL == "" {
 		config.UploadURL = config.BaseURL
 	}
 
-	logrus.Debug("using enterprise client")
-	logrus.Debugf("\tToken: %s", token)
-	logrus.Debugf("\tBaseURL: %s", config.BaseURL)
-	logrus.Debugf("\tUploadURL: %s", config.UploadURL)
+	logger.WithFields(logrus.Fields{"baseURL": config.BaseURL, "uploadURL": config.UploadURL}).Debug("creating new enterprise client")
 	client, err := github.NewEnterpriseClient(config.BaseURL, config.UploadURL, authenticatedClient)
 	if err != nil {
-		return nil, err
+		return nil, apperrors.Wrap(apperrors.Config, err, "invalid baseURL or uploadURL")
 	}
 
-	return &MetricsClient{c: client}, nil
+	return &MetricsClient{c: client, stats: stats}, nil
 }
 
 // Issue URLs look like: https://api.github.com/repos/3xcellent/github-metrics/issues/2
 // captures {$1:"3xcellent",$2:"github-metrics",$3:"2"}
-const regexParseIssueURL = `^.*\/repos\/(\S+)\/(\S+)\/issues\/(\d+)`
+var issueURLRegexp = regexp.MustCompile(`^.*\/repos\/(\S+)\/(\S+)\/issues\/(\d+)`)
 
-// ParseIssueURL -- returns owner, repo, and issuenumber from an Issue URL
-func ParseIssueURL(url string) (owner string, repo string, issueNumber int) {
-	re, err := regexp.Compile(regexParseIssueURL)
-	if err != nil {
-		panic("unable to parse regex")
-		//fmt.Printf("could not compile regex: %v", err)
-	}
-	matches := re.FindStringSubmatch(url)
-	if len(matches) < 1 {
-		logrus.Errorf("Error parsing url: %s", url)
+// ParseIssueURL -- returns owner, repo, and issuenumber from an Issue URL, err when it is not one
+func ParseIssueURL(url string) (owner string, repo string, issueNumber int, err error) {
+	matches := issueURLRegexp.FindStringSubmatch(url)
+	if len(matches) < 4 {
+		return "", "", 0, fmt.Errorf("not an issue url: %q", url)
 	}
 	number, err := strconv.Atoi(matches[3])
 	if err != nil {
-		panic(err)
+		return "", "", 0, fmt.Errorf("issue number of %q: %w", url, err)
 	}
-	return matches[1], matches[2], number
+	return matches[1], matches[2], number, nil
 }
@@ -2,8 +2,11 @@ package client
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/3xcellent/github-metrics/apperrors"
 	"github.com/3xcellent/github-metrics/config"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -46,3 +49,85 @@ func TestClient_New(t *testing.T) {
 		})
 	})
 }
+
+func TestClient_APIStats(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "4999")
+		w.Write([]byte(`{"id": 1, "name": "board"}`))
+	}))
+	defer server.Close()
+
+	actClient, err := New(context.Background(), config.APIConfig{Token: "token", BaseURL: server.URL + "/"})
+	require.NoError(t, err)
+
+	t.Run("before any request", func(t *testing.T) {
+		assert.Equal(t, APIStats{Calls: 0, RateLimitRemaining: -1}, actClient.APIStats())
+	})
+
+	t.Run("counts requests and records the rate limit remaining", func(t *testing.T) {
+		_, err := actClient.GetProject(context.Background(), 1)
+		require.NoError(t, err)
+		assert.Equal(t, APIStats{Calls: 1, RateLimitRemaining: 4999}, actClient.APIStats())
+	})
+}
+
+func TestClient_Errors(t *testing.T) {
+	for status, kind := range map[int]apperrors.Kind{
+		http.StatusUnauthorized:        apperrors.Auth,
+		http.StatusNotFound:            apperrors.NotFound,
+		http.StatusInternalServerError: apperrors.Unknown,
+	} {
+		t.Run(http.StatusText(status), func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(status)
+				w.Write([]byte(`{"message": "failed"}`))
+			}))
+			defer server.Close()
+
+			actClient, err := New(context.Background(), config.APIConfig{Token: "token", BaseURL: server.URL + "/"})
+			require.NoError(t, err)
+
+			_, err = actClient.GetProject(context.Background(), 42)
+			require.Error(t, err)
+			assert.Equal(t, kind, apperrors.KindOf(err), "%v", err)
+			assert.Contains(t, err.Error(), "getting project 42")
+		})
+	}
+
+	t.Run("rate limited", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-RateLimit-Limit", "5000")
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", "4102444800")
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message": "API rate limit exceeded"}`))
+		}))
+		defer server.Close()
+
+		actClient, err := New(context.Background(), config.APIConfig{Token: "token", BaseURL: server.URL + "/"})
+		require.NoError(t, err)
+
+		_, err = actClient.GetProjectColumns(context.Background(), 42)
+		assert.Equal(t, apperrors.RateLimited, apperrors.KindOf(err), "%v", err)
+	})
+
+	t.Run("without a token", func(t *testing.T) {
+		_, err := New(context.Background(), config.APIConfig{})
+		assert.Equal(t, apperrors.Auth, apperrors.KindOf(err))
+	})
+}
+
+func TestParseIssueURL(t *testing.T) {
+	t.Run("returns the owner, repo and number of the issue", func(t *testing.T) {
+		owner, repo, number, err := ParseIssueURL("https://api.github.com/repos/3xcellent/github-metrics/issues/2")
+		require.NoError(t, err)
+		assert.Equal(t, "3xcellent", owner)
+		assert.Equal(t, "github-metrics", repo)
+		assert.Equal(t, 2, number)
+	})
+
+	t.Run("returns an error for other urls", func(t *testing.T) {
+		_, _, _, err := ParseIssueURL("https://api.github.com/projects/columns/cards/1")
+		assert.EqualError(t, err, `not an issue url: "https://api.github.com/projects/columns/cards/1"`)
+	})
+}
@@ -0,0 +1,32 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/3xcellent/github-metrics/apperrors"
+	"github.com/google/go-github/v32/github"
+)
+
+// apiError - returns err of the github api classified by its kind: auth when the token is rejected or lacks
+// access, rate limited when a rate limit was reached, not found when github answered 404; nil when err is nil
+func apiError(err error, msg string) error {
+	if err == nil {
+		return nil
+	}
+	var rateLimitErr *github.RateLimitError
+	var abuseErr *github.AbuseRateLimitError
+	if errors.As(err, &rateLimitErr) || errors.As(err, &abuseErr) {
+		return apperrors.Wrap(apperrors.RateLimited, err, msg)
+	}
+	var respErr *github.ErrorResponse
+	if errors.As(err, &respErr) && respErr.Response != nil {
+		switch respErr.Response.StatusCode {
+		case http.StatusUnauthorized, http.StatusForbidden:
+			return apperrors.Wrap(apperrors.Auth, err, msg)
+		case http.StatusNotFound:
+			return apperrors.Wrap(apperrors.NotFound, err, msg)
+		}
+	}
+	return apperrors.Wrap(apperrors.Unknown, err, msg)
+}
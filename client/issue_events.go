@@ -2,6 +2,7 @@ package client
 
 import (
 	"context"
+	"fmt"
 	"strings"
 
 	"github.com/3xcellent/github-metrics/models"
@@ -16,7 +17,7 @@ func (m *MetricsClient) GetIssueEvents(ctx context.Context, repoOwner, repoName
 	for {
 		events, resp, err := m.c.Issues.ListIssueEvents(ctx, repoOwner, repoName, issueNumber, opt)
 		if err != nil {
-			return nil, err
+			return nil, apiError(err, fmt.Sprintf("getting events of issue %s/%s#%d", repoOwner, repoName, issueNumber))
 		}
 		issueEvents = append(issueEvents, MapToIssueEvents(events)...)
 		if resp.NextPage == 0 {
@@ -42,6 +43,7 @@ func MapToIssueEvent(e *github.IssueEvent) models.IssueEvent {
 	return models.IssueEvent{
 		Event:              e.GetEvent(), // TODO: remove since using Type throughout
 		ProjectID:          e.GetProjectCard().GetProjectID(),
+		ProjectCardID:      e.GetProjectCard().GetID(),
 		Type:               models.IssueEventType(strings.ToUpper(e.GetEvent())),
 		ColumnName:         e.GetProjectCard().GetColumnName(),
 		PreviousColumnName: e.GetProjectCard().GetPreviousColumnName(),
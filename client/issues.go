@@ -2,26 +2,26 @@ package client
 
 import (
 	"context"
-	"errors"
+	"fmt"
 	"time"
 
+	"github.com/3xcellent/github-metrics/apperrors"
 	"github.com/3xcellent/github-metrics/models"
 	"github.com/google/go-github/v32/github"
-	"github.com/sirupsen/logrus"
 )
 
 // GetIssues - uses owner, list of repo names, begindate and enddates to retrieve []*github.Issue and map to models.Issues
 func (m *MetricsClient) GetIssues(ctx context.Context, repoOwner string, repos []string, beginDate, endDate time.Time) (models.Issues, error) {
 	if repoOwner == "" {
-		return nil, errors.New("owner cannot be blank")
+		return nil, apperrors.New(apperrors.Config, "owner cannot be blank")
 	}
 	projectIssues := make(models.Issues, 0)
 	for _, repo := range repos {
 		repoIssues := make(models.Issues, 0)
-		logrus.Debugf("getting issues for repo: %s", repo)
+		logger.Debugf("getting issues for repo: %s", repo)
 		opt := github.ListOptions{PerPage: 100}
 		for {
-			logrus.Debugf("getting issues for repo: %s page %d", repo, opt.Page)
+			logger.Debugf("getting issues for repo: %s page %d", repo, opt.Page)
 			issuesForPage, resp, err := m.c.Issues.ListByRepo(ctx, repoOwner, repo, &github.IssueListByRepoOptions{
 				//Milestone: "",
 				State: all,
@@ -33,22 +33,18 @@ func (m *MetricsClient) GetIssues(ctx context.Context, repoOwner string, repos [
 				ListOptions: opt,
 			})
 			if err != nil {
-				return nil, err
+				return nil, apiError(err, fmt.Sprintf("getting issues of repo %s/%s", repoOwner, repo))
 			}
-			if resp != nil && resp.StatusCode == 404 {
-				logrus.Warnf("URL Not Found: %s", resp.Request.URL.String())
-				continue
-			}
-			logrus.Debugf("retrieved %d issue for page %d", len(issuesForPage), opt.Page)
+			logger.Debugf("retrieved %d issue for page %d", len(issuesForPage), opt.Page)
 			for _, issue := range issuesForPage {
 				issue := mapToIssue(issue)
 				issue.RepoName = repo
 				issue.Owner = repoOwner
 				if issue.CreatedAt.After(endDate) {
-					logrus.Debugf("skipping.... issue.CreatedAt: %s - After(%s)", issue.CreatedAt.String(), endDate.String())
+					logger.Debugf("skipping.... issue.CreatedAt: %s - After(%s)", issue.CreatedAt.String(), endDate.String())
 					continue
 				}
-				logrus.Debugf("\tadding issue: %s/%d - %s", issue.RepoName, issue.Number, issue.Title)
+				logger.Debugf("\tadding issue: %s/%d - %s", issue.RepoName, issue.Number, issue.Title)
 				repoIssues = append(repoIssues, issue)
 			}
 
@@ -57,7 +53,7 @@ func (m *MetricsClient) GetIssues(ctx context.Context, repoOwner string, repos [
 			}
 			opt.Page = resp.NextPage
 		}
-		logrus.Debugf("repo %s has %d issues", repo, len(repoIssues))
+		logger.Debugf("repo %s has %d issues", repo, len(repoIssues))
 		projectIssues = append(projectIssues, repoIssues...)
 	}
 	return projectIssues, nil
@@ -67,11 +63,14 @@ func (m *MetricsClient) GetIssues(ctx context.Context, repoOwner string, repos [
 // Returns empty issue, err when not found.
 func (m *MetricsClient) GetIssue(ctx context.Context, repoOwner, repoName string, issueNumber int) (models.Issue, error) {
 	ghIssue, _, err := m.c.Issues.Get(ctx, repoOwner, repoName, issueNumber)
+	if err != nil {
+		return models.Issue{}, apiError(err, fmt.Sprintf("getting issue %s/%s#%d", repoOwner, repoName, issueNumber))
+	}
 	issue := mapToIssue(ghIssue)
 	issue.Owner = repoOwner
 	issue.RepoName = repoName
 	issue.Number = issueNumber
-	return issue, err
+	return issue, nil
 }
 
 func mapToIssue(ghIssue *github.Issue) models.Issue {
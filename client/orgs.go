@@ -5,7 +5,6 @@ import (
 
 	"github.com/3xcellent/github-metrics/models"
 	"github.com/google/go-github/v32/github"
-	"github.com/sirupsen/logrus"
 )
 
 // GetUserOrgs - returns gihub organizations by owner, returns nil, err on error
@@ -16,11 +15,11 @@ func (m *MetricsClient) GetUserOrgs(ctx context.Context, owner string) (models.O
 	for {
 		pageOrgs, resp, err := m.c.Organizations.List(ctx, "", opt)
 		if err != nil {
-			return nil, err
+			return nil, apiError(err, "getting organizations of the user")
 		}
 
 		for _, org := range pageOrgs {
-			logrus.Debugf("User Org found: \"%s\" - %5d - %s", org.GetName(), org.GetID(), org.GetURL())
+			logger.Debugf("User Org found: \"%s\" - %5d - %s", org.GetName(), org.GetID(), org.GetURL())
 			orgs = append(orgs, mapToOrganzation(org))
 		}
 
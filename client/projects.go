@@ -2,21 +2,22 @@ package client
 
 import (
 	"context"
+	"fmt"
 	"regexp"
 
+	"github.com/3xcellent/github-metrics/apperrors"
 	"github.com/3xcellent/github-metrics/models"
 	"github.com/google/go-github/v32/github"
-	"github.com/sirupsen/logrus"
 )
 
 // GetProject - retrieves project from github and maps to models.Project, returns empty project, err on error
 func (m *MetricsClient) GetProject(ctx context.Context, projectID int64) (models.Project, error) {
-	logrus.Debugf("\tgetting project for id: %d", projectID)
+	logger.Debugf("\tgetting project for id: %d", projectID)
 	project, _, err := m.c.Projects.GetProject(ctx, projectID)
 	if err != nil {
-		return models.Project{}, err
+		return models.Project{}, apiError(err, fmt.Sprintf("getting project %d", projectID))
 	}
-	logrus.Debugf("\tfound project: %q", project.GetName())
+	logger.Debugf("\tfound project: %q", project.GetName())
 	return mapToProject(project), nil
 }
 
@@ -39,8 +40,11 @@ func (m *MetricsClient) GetProjects(ctx context.Context, owner string) (models.P
 		return nil, err
 	}
 	for _, r := range repos {
-		projectOwner, projectRepo := parseRepoURL(r.URL)
-		logrus.Debugf("repo: %s/%s (%s)", projectOwner, projectRepo, r.URL)
+		projectOwner, projectRepo, err := parseRepoURL(r.URL)
+		if err != nil {
+			return nil, err
+		}
+		logger.Debugf("repo: %s/%s (%s)", projectOwner, projectRepo, r.URL)
 		repoProjects, err := m.getRepoProjects(ctx, projectOwner, projectRepo)
 		if err != nil {
 			return nil, err
@@ -51,7 +55,7 @@ func (m *MetricsClient) GetProjects(ctx context.Context, owner string) (models.P
 }
 
 func (m *MetricsClient) getOrgProjects(ctx context.Context, owner string) ([]*github.Project, error) {
-	logrus.Debugf("getting org projects for: %s", owner)
+	logger.Debugf("getting org projects for: %s", owner)
 
 	projects := make([]*github.Project, 0)
 	opt := github.ListOptions{
@@ -61,10 +65,10 @@ func (m *MetricsClient) getOrgProjects(ctx context.Context, owner string) ([]*gi
 	for {
 		projects, resp, err := m.c.Organizations.ListProjects(ctx, owner, &github.ProjectListOptions{ListOptions: opt})
 		if err != nil {
-			return nil, err
+			return nil, apiError(err, fmt.Sprintf("getting projects of org %s", owner))
 		}
 		for _, p := range projects {
-			logrus.Debugf("Organization Project (page %d): found \"%s\" - %5d", opt.Page, p.GetName(), p.GetID())
+			logger.Debugf("Organization Project (page %d): found \"%s\" - %5d", opt.Page, p.GetName(), p.GetID())
 			projects = append(projects, p)
 		}
 		if resp.NextPage == 0 {
@@ -77,7 +81,7 @@ func (m *MetricsClient) getOrgProjects(ctx context.Context, owner string) ([]*gi
 }
 
 func (m *MetricsClient) getUserProjects(ctx context.Context, owner string) (models.Projects, error) {
-	logrus.Debugf("getting projects for user: %s", owner)
+	logger.Debugf("getting projects for user: %s", owner)
 
 	projects := make(models.Projects, 0)
 	opt := github.ListOptions{PerPage: 100}
@@ -85,10 +89,10 @@ func (m *MetricsClient) getUserProjects(ctx context.Context, owner string) (mode
 	for {
 		projects, resp, err := m.c.Users.ListProjects(ctx, owner, &github.ProjectListOptions{ListOptions: opt})
 		if err != nil {
-			return nil, err
+			return nil, apiError(err, fmt.Sprintf("getting projects of user %s", owner))
 		}
 		for _, p := range projects {
-			logrus.Debugf("\tfound \"%s\" - %5d", p.GetName(), p.GetID())
+			logger.Debugf("\tfound \"%s\" - %5d", p.GetName(), p.GetID())
 			projects = append(projects, p)
 		}
 		if resp.NextPage == 0 {
@@ -101,7 +105,7 @@ func (m *MetricsClient) getUserProjects(ctx context.Context, owner string) (mode
 }
 
 func (m *MetricsClient) getRepoProjects(ctx context.Context, owner, repo string) (models.Projects, error) {
-	logrus.Debugf("getting repo projects for: %s/%s", owner, repo)
+	logger.Debugf("getting repo projects for: %s/%s", owner, repo)
 
 	projects := make(models.Projects, 0)
 	opt := github.ListOptions{PerPage: 100}
@@ -113,13 +117,15 @@ func (m *MetricsClient) getRepoProjects(ctx context.Context, owner, repo string)
 			repo,
 			&github.ProjectListOptions{ListOptions: opt})
 		if err != nil {
-			logrus.Warnf("err accessing projects for \"%s\"-\"%s\": %s", owner, repo, err.Error())
-		}
-		if resp.StatusCode != 200 {
+			// repos without projects enabled answer 410, those the token cannot see 404
+			logger.Warnf("err accessing projects for \"%s\"-\"%s\": %s", owner, repo, err.Error())
+			if apperrors.Is(apiError(err, ""), apperrors.RateLimited) {
+				return nil, apiError(err, fmt.Sprintf("getting projects of repo %s/%s", owner, repo))
+			}
 			break
 		}
 		for _, p := range pageProjects {
-			logrus.Debugf("\tfound: \"%s\" - %5d", p.GetName(), p.GetID())
+			logger.Debugf("\tfound: \"%s\" - %5d", p.GetName(), p.GetID())
 			projects = append(projects, mapRepoProject(p, owner, repo))
 		}
 		if resp.NextPage == 0 {
@@ -165,18 +171,13 @@ func mapToProject(project *github.Project) models.Project {
 
 // Project URLs look like: https://api.github.com/repos/3xcellent/github-metrics/
 // captures {$1:"3xcellent",$2:"github-metrics"}
-const regexParseRepoURL = `^.*\/repos\/(\S+)\/(\S+)(\/|\z)`
+var repoURLRegexp = regexp.MustCompile(`^.*\/repos\/(\S+)\/(\S+)(\/|\z)`)
 
-// ParseRepoURL -- returns owner, and repo from a Project URL
-func parseRepoURL(url string) (owner string, repo string) {
-	re, err := regexp.Compile(regexParseRepoURL)
-	if err != nil {
-		panic("unable to parse regex")
-		//fmt.Printf("could not compile regex: %v", err)
-	}
-	matches := re.FindStringSubmatch(url)
-	if len(matches) < 2 {
-		logrus.Errorf("Error parsing url: %s", url)
+// parseRepoURL -- returns owner, and repo from a Project URL, err when it is not one
+func parseRepoURL(url string) (owner string, repo string, err error) {
+	matches := repoURLRegexp.FindStringSubmatch(url)
+	if len(matches) < 3 {
+		return "", "", fmt.Errorf("not a repo url: %q", url)
 	}
-	return matches[1], matches[2]
+	return matches[1], matches[2], nil
 }
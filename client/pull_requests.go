@@ -2,10 +2,10 @@ package client
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/3xcellent/github-metrics/models"
 	"github.com/google/go-github/v32/github"
-	"github.com/sirupsen/logrus"
 )
 
 // GetPullRequests - uses owner and reponame to retrieve []*github.PullRequest and map to models.PullRequests
@@ -25,9 +25,9 @@ func (m *MetricsClient) GetPullRequests(ctx context.Context, owner, repoName str
 			},
 		})
 		if err != nil {
-			return nil, err
+			return nil, apiError(err, fmt.Sprintf("getting pull requests of repo %s/%s", owner, repoName))
 		}
-		logrus.Debugf("%s/%d - %d", repoName, opt.Page, len(prs))
+		logger.Debugf("%s/%d - %d", repoName, opt.Page, len(prs))
 		pagedPRs := mapToPullRequests(prs, owner, repoName)
 		repoPullRequests = append(repoPullRequests, pagedPRs...)
 		if resp.NextPage == 0 {
@@ -2,11 +2,11 @@ package client
 
 import (
 	"context"
+	"fmt"
 	"strings"
 
 	"github.com/3xcellent/github-metrics/models"
 	"github.com/google/go-github/v32/github"
-	"github.com/sirupsen/logrus"
 )
 
 // GetReposFromProjectColumn - returns slice of repo names gathered from the issues found in the columnID provided.
@@ -15,14 +15,14 @@ func (m *MetricsClient) GetReposFromProjectColumn(ctx context.Context, colID int
 	repoMap := map[string]models.Repository{}
 	state := all
 	opt := github.ListOptions{PerPage: 100}
-	logrus.Debugf("getting repos for project: %d", colID)
+	logger.Debugf("getting repos for project: %d", colID)
 	for {
 		cards, resp, err := m.c.Projects.ListProjectCards(ctx, colID, &github.ProjectCardListOptions{
 			ArchivedState: &state,
 			ListOptions:   opt,
 		})
 		if err != nil {
-			return nil, err
+			return nil, apiError(err, fmt.Sprintf("getting cards of column %d", colID))
 		}
 
 		for _, c := range cards {
@@ -30,7 +30,11 @@ func (m *MetricsClient) GetReposFromProjectColumn(ctx context.Context, colID int
 				continue
 			}
 
-			owner, repoName, _ := ParseIssueURL(c.GetContentURL())
+			owner, repoName, _, err := ParseIssueURL(c.GetContentURL())
+			if err != nil {
+				logger.Warnf("skipping card %d: %s", c.GetID(), err)
+				continue
+			}
 
 			if _, found := repoMap[repoName]; !found {
 				repo := models.Repository{
@@ -38,7 +42,7 @@ func (m *MetricsClient) GetReposFromProjectColumn(ctx context.Context, colID int
 					Owner: owner,
 				}
 				repoMap[repoName] = repo
-				logrus.Debugf("\tadding repo %s", repoName)
+				logger.Debugf("\tadding repo %s", repoName)
 				repos = append(repos, repo)
 			}
 		}
@@ -48,7 +52,7 @@ func (m *MetricsClient) GetReposFromProjectColumn(ctx context.Context, colID int
 		opt.Page = resp.NextPage
 	}
 
-	logrus.Infof("repos found: %s", strings.Join(repos.Names(), ","))
+	logger.Infof("repos found: %s", strings.Join(repos.Names(), ","))
 	return repos, nil
 }
 
@@ -60,10 +64,10 @@ func (m *MetricsClient) GetUserRepos(ctx context.Context, owner string) (models.
 		pageRepos, resp, err := m.c.Repositories.List(ctx, "", &github.RepositoryListOptions{ListOptions: opt})
 
 		if err != nil {
-			return nil, err
+			return nil, apiError(err, "getting repos of the user")
 		}
 		for _, r := range pageRepos {
-			logrus.Debugf("User Repo found: \"%s\" - %5d - %s", r.GetName(), r.GetID(), r.GetURL())
+			logger.Debugf("User Repo found: \"%s\" - %5d - %s", r.GetName(), r.GetID(), r.GetURL())
 			repos = append(repos, mapToRepository(r))
 		}
 		if resp.NextPage == 0 {
@@ -0,0 +1,55 @@
+package client
+
+import (
+	"net/http"
+	"strconv"
+	"sync/atomic"
+)
+
+// APIStats - counts of the requests made to the github api by a MetricsClient
+type APIStats struct {
+	Calls              int64
+	RateLimitRemaining int64
+}
+
+// statsTransport - counts requests and records the rate limit remaining from the response headers
+type statsTransport struct {
+	base               http.RoundTripper
+	calls              int64
+	rateLimitRemaining int64
+}
+
+func newStatsTransport(base http.RoundTripper) *statsTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &statsTransport{base: base, rateLimitRemaining: -1}
+}
+
+// RoundTrip - implements http.RoundTripper
+func (t *statsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	atomic.AddInt64(&t.calls, 1)
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	if remaining, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Remaining"), 10, 64); err == nil {
+		atomic.StoreInt64(&t.rateLimitRemaining, remaining)
+	}
+	return resp, nil
+}
+
+func (t *statsTransport) stats() APIStats {
+	return APIStats{
+		Calls:              atomic.LoadInt64(&t.calls),
+		RateLimitRemaining: atomic.LoadInt64(&t.rateLimitRemaining),
+	}
+}
+
+// APIStats - returns the number of api requests made and the rate limit remaining, -1 until a response reports it
+func (m *MetricsClient) APIStats() APIStats {
+	if m.stats == nil {
+		return APIStats{RateLimitRemaining: -1}
+	}
+	return m.stats.stats()
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/3xcellent/github-metrics/apperrors"
+	"github.com/3xcellent/github-metrics/config"
+	"github.com/3xcellent/github-metrics/slo"
+	"github.com/spf13/cobra"
+)
+
+var (
+	checkCmd = &cobra.Command{
+		Use:   "check [board_name...]",
+		Short: "check the SLOs of the config, for CI pipelines",
+		Long:  "checks the rules of the SLOs of the config against the metrics of each named board, or of every run config, for the year and month or the last --days. Prints a report of the rules and exits with code 7 when any is violated; --junit also writes the report as JUnit XML so CI systems show violated rules as test failures",
+		RunE:  check,
+	}
+	checkJUnit string
+	checkDays  int
+)
+
+func init() {
+	checkCmd.Flags().StringVar(&checkJUnit, "junit", "", "also write the results as JUnit XML to this file")
+	checkCmd.Flags().IntVar(&checkDays, "days", 0, "check the last number of days up to today, instead of the year and month")
+}
+
+func check(c *cobra.Command, args []string) error {
+	ctx := c.Context()
+
+	cfg, err := config.NewDefaultConfig()
+	if err != nil {
+		return err
+	}
+	Config = cfg
+	if len(cfg.SLOs) == 0 {
+		return apperrors.New(apperrors.Config, "no SLOs configured")
+	}
+	if problems := slo.Validate(cfg); len(problems) > 0 {
+		return apperrors.Errorf(apperrors.Config, "%s", problems[0])
+	}
+	if len(args) == 0 {
+		args = cfg.RunConfigs.SortedNames()
+	}
+
+	runConfigs := make(config.RunConfigs, 0, len(args))
+	for _, name := range args {
+		runCfg, err := cfg.GetRunConfig(name)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		if checkDays > 0 {
+			now := time.Now()
+			runCfg.EndDate = time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location())
+			runCfg.StartDate = runCfg.EndDate.AddDate(0, 0, -checkDays)
+		}
+		runConfigs = append(runConfigs, runCfg)
+	}
+
+	metricsClient, profiles, closeClients, err := newClients(ctx, cfg, runConfigs)
+	if err != nil {
+		return err
+	}
+	defer closeClients()
+	checker := slo.Checker{Client: metricsClient, Profiles: profiles}
+	results := checker.Check(ctx, cfg.SLOs, runConfigs)
+
+	w := tabwriter.NewWriter(c.OutOrStdout(), 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "Board\tSLO\tRule\tActual\tStatus")
+	for _, result := range results {
+		actual := slo.FormatValue(result.Actual)
+		if result.Err != nil {
+			actual = result.Err.Error()
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", result.Board, slo.Name(result.SLO), slo.Rule(result.SLO), actual, result.Status())
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if checkJUnit != "" {
+		if err := writeJUnit(checkJUnit, results); err != nil {
+			return err
+		}
+	}
+	return slo.Err(results)
+}
+
+func writeJUnit(path string, results []slo.Result) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := slo.WriteJUnit(f, results); err != nil {
+		return err
+	}
+	return f.Close()
+}
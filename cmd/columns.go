@@ -1,29 +1,45 @@
 package cmd
 
 import (
-	"encoding/csv"
-	"os"
+	"io"
 
+	"github.com/3xcellent/github-metrics/charts"
 	"github.com/3xcellent/github-metrics/metrics/runners"
-	"github.com/sirupsen/logrus"
+	"github.com/3xcellent/github-metrics/output"
 	"github.com/spf13/cobra"
 )
 
-var columnsCmd = &cobra.Command{
-	Use:   "columns [board_name]",
-	Short: "output number of issues in each column for a github board to csv",
-	Long:  "aggregate column totals for a github repoName board within year and month provided (default is current year and month)",
-	RunE:  columns,
-	Args:  cobra.MinimumNArgs(1),
+var (
+	columnsCmd = &cobra.Command{
+		Use:   "columns [board_name]",
+		Short: "output number of issues in each column for a github board to csv",
+		Long:  "aggregate column totals for a github repoName board within year and month provided (default is current year and month); use --format svg or png to draw a cumulative flow diagram instead, html for a standalone report or xlsx for a workbook of all metrics",
+		RunE:  columns,
+		Args:  cobra.MinimumNArgs(1),
+	}
+	columnsFormat string
+)
+
+func init() {
+	addFormatFlag(columnsCmd, &columnsFormat, append(dataFormats, svgFormat, pngFormat, htmlFormat, output.XLSX)...)
 }
 
 func columns(c *cobra.Command, args []string) error {
 	ctx := c.Context()
 
-	client, runCfg, err := SetupCLI(ctx, args[0])
+	format, err := output.ParseFormat(columnsFormat, append(dataFormats, svgFormat, pngFormat, htmlFormat, output.XLSX)...)
+	if err != nil {
+		return err
+	}
+
+	client, runCfg, closeClient, err := SetupCLI(ctx, args[0])
 	if err != nil {
 		return err
 	}
+	defer closeClient()
+	if err := requireFile(format, runCfg.CreateFile); err != nil {
+		return err
+	}
 
 	runCfg.MetricName = "columns"
 
@@ -36,34 +52,28 @@ func columns(c *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
-	outpath := runner.RunName()
-	var writer *csv.Writer
-	if runCfg.CreateFile {
-		logrus.Debugf("writing to: %s", outpath)
-		output, err := os.Create(outpath)
-		if err != nil {
-			panic(err)
-		}
-		writer = csv.NewWriter(output)
-	} else {
-		writer = csv.NewWriter(c.OutOrStdout())
-	}
-	defer writer.Flush()
-
-	for _, rowValues := range runner.Values() {
-		if err := writer.Write(rowValues); err != nil {
-			return err
-		}
-	}
 
-	c.Println()
-	if runCfg.CreateFile {
-		wd, err := os.Getwd()
+	switch format {
+	case htmlFormat:
+		return writeReport(c, client, runCfg, nil, runner.(*runners.ColumnsRunner))
+	case output.XLSX:
+		return writeWorkbook(c, client, runCfg, nil, runner.(*runners.ColumnsRunner))
+	case svgFormat, pngFormat:
+		cfd := charts.NewCumulativeFlow(runner.(*runners.ColumnsRunner))
+		cfd.Annotations, err = charts.Annotations(runCfg.Annotations, runCfg.StartDate.Location())
 		if err != nil {
 			return err
 		}
-		c.Printf("Wrote to: file://%s/%s\n", wd, outpath)
+		return writeOutput(c, runCfg.CreateFile, withExtension(runner.RunName(), format.Ext()), func(w io.Writer) error {
+			if format == pngFormat {
+				return cfd.PNG(w)
+			}
+			return cfd.SVG(w)
+		})
 	}
 
-	return nil
+	return writeResult(c, runCfg.CreateFile, runner.RunName(), format, output.Result{
+		Rows:    runner.Values(),
+		Records: runner.Results(),
+	})
 }
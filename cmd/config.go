@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/3xcellent/github-metrics/apperrors"
+	"github.com/3xcellent/github-metrics/client"
+	"github.com/3xcellent/github-metrics/config"
+	"github.com/3xcellent/github-metrics/credentials"
+	"github.com/3xcellent/github-metrics/metrics/runners"
+	"github.com/3xcellent/github-metrics/schedule"
+	"github.com/3xcellent/github-metrics/slo"
+	"github.com/spf13/cobra"
+)
+
+var (
+	configCmd = &cobra.Command{
+		Use:   "config",
+		Short: "check the config",
+	}
+	configValidateCmd = &cobra.Command{
+		Use:   "validate",
+		Short: "check config.yaml for errors, and with --remote that its boards and columns exist",
+		Long:  "checks the config file for invalid yaml, unknown settings, values of the wrong type, run configs without a name or projectID or with duplicate names, invalid schedules and SLOs; with --remote also checks with github that the project of each run config exists and that its start and end columns are columns of the board, with the start before the end. Exits with code 2 when any problem is found",
+		RunE:  configValidate,
+	}
+	configFile   string
+	configRemote bool
+)
+
+func init() {
+	configValidateCmd.Flags().StringVar(&configFile, "file", "config.yaml", "config file to check")
+	configValidateCmd.Flags().BoolVar(&configRemote, "remote", false, "also check the boards and columns with github")
+	configCmd.AddCommand(configValidateCmd)
+}
+
+func configValidate(c *cobra.Command, args []string) error {
+	data, err := ioutil.ReadFile(configFile)
+	if err != nil {
+		return apperrors.Wrap(apperrors.Config, err, "")
+	}
+
+	problems := config.Validate(data)
+	cfg, err := config.Parse(data)
+	if err == nil && len(problems) == 0 {
+		problems = append(problems, validateSchedules(cfg)...)
+		problems = append(problems, slo.Validate(cfg)...)
+		problems = append(problems, validateCredentials(cfg)...)
+		if configRemote {
+			remoteProblems, err := validateRemote(c, cfg)
+			if err != nil {
+				return err
+			}
+			problems = append(problems, remoteProblems...)
+		}
+	}
+
+	for _, problem := range problems {
+		fmt.Fprintf(c.OutOrStdout(), "%s: %s\n", configFile, problem)
+	}
+	if len(problems) == 1 {
+		return apperrors.Errorf(apperrors.Config, "1 problem found in %s", configFile)
+	}
+	if len(problems) > 0 {
+		return apperrors.Errorf(apperrors.Config, "%d problems found in %s", len(problems), configFile)
+	}
+	fmt.Fprintf(c.OutOrStdout(), "%s: ok\n", configFile)
+	return nil
+}
+
+func validateSchedules(cfg *config.AppConfig) []config.Problem {
+	if err := schedule.New(nil, cfg, nil).Validate(); err != nil {
+		return []config.Problem{{Path: "Schedules", Message: err.Error()}}
+	}
+	return nil
+}
+
+// validateCredentials - returns a problem for each api settings or profile with unknown or incomplete credentials
+func validateCredentials(cfg *config.AppConfig) []config.Problem {
+	problems := make([]config.Problem, 0)
+	if _, err := credentials.Providers(cfg.API); err != nil {
+		problems = append(problems, config.Problem{Path: "API.credentials", Message: err.Error()})
+	}
+	for idx, name := range cfg.ProfileNames() {
+		api, err := cfg.APIConfig(name)
+		if err == nil {
+			_, err = credentials.Providers(api)
+		}
+		if err != nil {
+			problems = append(problems, config.Problem{Path: fmt.Sprintf("Profiles[%d].credentials", idx), Message: err.Error()})
+		}
+	}
+	return problems
+}
+
+// validateRemote - returns a problem for each run config whose project or columns are not found on github
+func validateRemote(c *cobra.Command, cfg *config.AppConfig) ([]config.Problem, error) {
+	// also loads .env, where the tokenEnv of profiles may be set
+	token, err := config.Token()
+	if err != nil {
+		return nil, err
+	}
+	if cfg.API.Token == "" {
+		cfg.API.Token = token
+	}
+	if profile != "" {
+		cfg.Profile = profile
+	}
+
+	// github clients by connection profile
+	clients := make(map[string]*client.MetricsClient)
+	problems := make([]config.Problem, 0)
+	for _, name := range cfg.RunConfigs.SortedNames() {
+		runCfg, err := cfg.GetRunConfig(name)
+		if err != nil {
+			return nil, err
+		}
+		ghClient, found := clients[runCfg.Profile]
+		if !found {
+			if ghClient, err = newGithubClient(c.Context(), cfg, runCfg.Profile); err != nil {
+				return nil, err
+			}
+			clients[runCfg.Profile] = ghClient
+		}
+		err = runners.NewBaseRunner(runCfg, ghClient).Validate(c.Context())
+		var unknown *runners.UnknownColumnError
+		switch {
+		case err == nil:
+			fmt.Fprintf(c.OutOrStdout(), "%s: %s: project %d, %s to %s found\n", configFile, name, runCfg.ProjectID, runCfg.StartColumn, runCfg.EndColumn)
+		case errors.As(err, &unknown):
+			problems = append(problems, config.Problem{Path: name, Message: err.Error() + "; use one of these names, or add the old name to columnAliases"})
+		default:
+			problems = append(problems, config.Problem{Path: name, Message: err.Error()})
+		}
+	}
+	return problems, nil
+}
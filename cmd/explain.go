@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/3xcellent/github-metrics/metrics"
+	"github.com/3xcellent/github-metrics/metrics/runners"
+	"github.com/3xcellent/github-metrics/output"
+	"github.com/spf13/cobra"
+)
+
+const explainTimeFormat = "2006-01-02 15:04"
+
+var (
+	explainCmd = &cobra.Command{
+		Use:   "explain [board_name] --repoName [repo] --issueNumber [number]",
+		Short: "explain how the metrics of a single issue were calculated",
+		Long:  "replays the events of one issue and prints its timeline, how each event was used to set column dates (accepted, ignored as a backward move, inferred), blocked intervals and the final calculated values",
+		RunE:  explain,
+		Args:  cobra.MinimumNArgs(1),
+	}
+	explainFormat string
+)
+
+func init() {
+	addFormatFlag(explainCmd, &explainFormat, output.Text, output.JSON, output.NDJSON)
+}
+
+func explain(c *cobra.Command, args []string) error {
+	ctx := c.Context()
+
+	format, err := output.ParseFormat(explainFormat, output.Text, output.JSON, output.NDJSON)
+	if err != nil {
+		return err
+	}
+
+	client, runCfg, closeClient, err := SetupCLI(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	defer closeClient()
+
+	runCfg.MetricName = "issues"
+	explanation, err := runners.NewIssuesRunner(runCfg, client).Explain(ctx)
+	if err != nil {
+		return err
+	}
+
+	if format != output.Text {
+		return output.Write(c.OutOrStdout(), format, output.Result{Records: explanation})
+	}
+	return writeExplanation(c.OutOrStdout(), explanation)
+}
+
+// writeExplanation - writes the explanation as aligned, human readable text
+func writeExplanation(out io.Writer, e metrics.Explanation) error {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "%s - %s\n", e.Issue, e.Title)
+	fmt.Fprintf(w, "project: %d\tcreated: %s\ton project: %t\n", e.ProjectID, e.Created.Format(explainTimeFormat), e.Values.OnProject)
+
+	fmt.Fprintln(w, "\nEvents")
+	for _, event := range e.Events {
+		column := event.Column
+		if event.PreviousColumn != "" {
+			column = event.PreviousColumn + " -> " + event.Column
+		}
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n", event.At.Format(explainTimeFormat), event.Type, column, event.Label)
+	}
+
+	fmt.Fprintln(w, "\nDecisions")
+	for _, d := range e.Decisions {
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\n", d.At.Format(explainTimeFormat), d.Action, d.Column, d.Event, d.Reason)
+	}
+
+	fmt.Fprintln(w, "\nBlocked")
+	for _, b := range e.BlockedIntervals {
+		fmt.Fprintf(w, "  %s\t%s\t%.1f days\n", b.From.Format(explainTimeFormat), b.To.Format(explainTimeFormat), b.Days())
+	}
+
+	fmt.Fprintln(w, "\nColumn Dates")
+	for _, col := range e.ColumnDates {
+		source := "observed"
+		if col.Inferred {
+			source = "inferred"
+		}
+		fmt.Fprintf(w, "  %s\t%s\t%s\n", col.Column, col.Date.Format(explainTimeFormat), source)
+	}
+
+	fmt.Fprintln(w, "\nValues")
+	fmt.Fprintf(w, "  Development Days\t%.1f\n", e.Values.CycleTimeDays)
+	fmt.Fprintf(w, "  Lead Time Days\t%.1f\n", e.Values.LeadTimeDays)
+	fmt.Fprintf(w, "  Time To Start Days\t%.1f\n", e.Values.TimeToStartDays)
+	fmt.Fprintf(w, "  Blocked Days\t%.1f\n", e.Values.BlockedDays)
+
+	if len(e.Anomalies) > 0 {
+		fmt.Fprintln(w, "\nAnomalies")
+		for _, anomaly := range e.Anomalies {
+			fmt.Fprintf(w, "  %s\t%s\n", anomaly.Kind, anomaly.Detail)
+		}
+	}
+	return w.Flush()
+}
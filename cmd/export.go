@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"errors"
+
+	"github.com/3xcellent/github-metrics/store"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportCmd = &cobra.Command{
+		Use:   "export [board_name] --sqlite [path]",
+		Short: "export the raw github data of a board to a sqlite database",
+		Long:  "writes the repositories, issues, labels, issue events, project columns and pull requests of a board within year and month provided into normalised tables of a sqlite database, with indexes and views of the dates each issue entered each column; existing rows are updated, so several months or boards can be exported into the same file",
+		RunE:  export,
+		Args:  cobra.MinimumNArgs(1),
+	}
+	exportSQLite string
+)
+
+func init() {
+	exportCmd.Flags().StringVar(&exportSQLite, "sqlite", "", "path of the sqlite database to write, created when missing")
+}
+
+func export(c *cobra.Command, args []string) error {
+	ctx := c.Context()
+	if exportSQLite == "" {
+		return errors.New("--sqlite path required")
+	}
+
+	client, runCfg, err := setupGithubCLI(ctx, args[0])
+	if err != nil {
+		return err
+	}
+
+	s, err := store.Open(exportSQLite)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	if err = s.Export(ctx, client, runCfg); err != nil {
+		return err
+	}
+	c.Printf("Exported %s to: %s\n", runCfg.Name, exportSQLite)
+	return nil
+}
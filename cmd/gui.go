@@ -2,7 +2,6 @@ package cmd
 
 import (
 	"context"
-	"log"
 	"os"
 
 	"gioui.org/app"
@@ -25,7 +24,7 @@ func startGUI(c *cobra.Command, args []string) error {
 	if Config == nil {
 		Config, err = config.NewDefaultConfig()
 		if err != nil {
-			panic(err)
+			return err
 		}
 	}
 
@@ -33,7 +32,7 @@ func startGUI(c *cobra.Command, args []string) error {
 
 	go func() {
 		if err := gui.Start(ctx, Config, args); err != nil {
-			log.Fatal(err)
+			exit(err)
 		}
 		os.Exit(0)
 	}()
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"context"
+	"os"
+
+	"github.com/3xcellent/github-metrics/client"
+	"github.com/3xcellent/github-metrics/config"
+	"github.com/3xcellent/github-metrics/metrics/runners"
+	"github.com/3xcellent/github-metrics/wizard"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+var (
+	initCmd = &cobra.Command{
+		Use:   "init",
+		Short: "interactively create config.yaml and .env",
+		Long:  "asks for the github api settings and access token, lists the owner's projects to pick boards from and the columns of each board to pick its start and end columns, then writes config.yaml and the token to .env; existing files are only overwritten when confirmed",
+		RunE:  initWizard,
+	}
+)
+
+func initWizard(c *cobra.Command, args []string) error {
+	w := wizard.New(c.InOrStdin(), c.OutOrStdout(), func(ctx context.Context, api config.APIConfig) (runners.Client, error) {
+		return client.New(ctx, api)
+	})
+	if fd := int(os.Stdin.Fd()); c.InOrStdin() == os.Stdin && terminal.IsTerminal(fd) {
+		w.ReadToken = func() (string, error) {
+			token, err := terminal.ReadPassword(fd)
+			return string(token), err
+		}
+	}
+	return w.Run(c.Context())
+}
@@ -1,30 +1,50 @@
 package cmd
 
 import (
-	"encoding/csv"
-	"os"
+	"strings"
 
+	"github.com/3xcellent/github-metrics/charts"
 	"github.com/3xcellent/github-metrics/metrics/runners"
-	"github.com/sirupsen/logrus"
+	"github.com/3xcellent/github-metrics/output"
 	"github.com/spf13/cobra"
 )
 
-var issuesCmd = &cobra.Command{
-	Use:   "issues [board_name]",
-	Short: "gathers metrics from issues on a board and outputs as csv",
-	Long:  "gathers issues from a github repoName board, calculates column and blocked durations, and outputs as comma separated values (.csv)",
-	RunE:  issues,
-	Args:  cobra.MinimumNArgs(1),
+var (
+	issuesCmd = &cobra.Command{
+		Use:   "issues [board_name]",
+		Short: "gathers metrics from issues on a board and outputs as csv",
+		Long:  "gathers issues from a github repoName board, calculates column and blocked durations, and outputs as comma separated values (.csv), json, ndjson, an html report or an xlsx workbook",
+		RunE:  issues,
+		Args:  cobra.MinimumNArgs(1),
+	}
+	issuesCharts  bool
+	issuesSummary bool
+	issuesFormat  string
+)
+
+func init() {
+	issuesCmd.Flags().BoolVarP(&issuesCharts, "charts", "", false, "also write cycle time scatterplot and histogram .svg files")
+	issuesCmd.Flags().BoolVarP(&issuesSummary, "summary", "", false, "also output cycle time, lead time and time to start statistics")
+	addFormatFlag(issuesCmd, &issuesFormat, append(dataFormats, htmlFormat, output.XLSX)...)
 }
 
 func issues(c *cobra.Command, args []string) error {
 	ctx := c.Context()
 
-	client, runCfg, err := SetupCLI(ctx, args[0])
+	format, err := output.ParseFormat(issuesFormat, append(dataFormats, htmlFormat, output.XLSX)...)
 	if err != nil {
 		return err
 	}
 
+	client, runCfg, closeClient, err := SetupCLI(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	defer closeClient()
+	if err := requireFile(format, runCfg.CreateFile); err != nil {
+		return err
+	}
+
 	runCfg.MetricName = "issues"
 
 	runner, err := runners.New(runCfg, client)
@@ -37,34 +57,44 @@ func issues(c *cobra.Command, args []string) error {
 		return err
 	}
 
-	outpath := runner.RunName()
-	var writer *csv.Writer
-	if runCfg.CreateFile {
-		logrus.Debugf("writing to: %s", outpath)
-		output, err := os.Create(outpath)
-		if err != nil {
-			panic(err)
-		}
-		writer = csv.NewWriter(output)
-	} else {
-		writer = csv.NewWriter(c.OutOrStdout())
+	if format == htmlFormat {
+		return writeReport(c, client, runCfg, runner.(*runners.IssuesRunner), nil)
+	}
+	if format == output.XLSX {
+		return writeWorkbook(c, client, runCfg, runner.(*runners.IssuesRunner), nil)
 	}
-	defer writer.Flush()
 
-	for _, rowValues := range runner.Values() {
-		if err := writer.Write(rowValues); err != nil {
-			return err
-		}
+	result := output.Result{
+		Rows:    runner.Values(),
+		Records: runner.Results(),
+	}
+	if issuesSummary {
+		summary := runner.(*runners.IssuesRunner).Summary()
+		err = writeResultWithSummary(c, runCfg.CreateFile, runner.RunName(), format, "issues", result, output.Result{
+			Rows:    summary.Values(),
+			Records: summary,
+		})
+	} else {
+		err = writeResult(c, runCfg.CreateFile, runner.RunName(), format, result)
+	}
+	if err != nil {
+		return err
 	}
 
-	c.Println()
-	if runCfg.CreateFile {
-		wd, err := os.Getwd()
-		if err != nil {
-			return err
-		}
-		c.Printf("Wrote to: file://%s/%s\n", wd, outpath)
+	if issuesCharts {
+		return writeCycleTimeCharts(c, runner.(*runners.IssuesRunner))
 	}
 
 	return nil
 }
+
+// writeCycleTimeCharts - writes the cycle time charts next to the runner's csv file
+func writeCycleTimeCharts(c *cobra.Command, runner *runners.IssuesRunner) error {
+	baseName := strings.TrimSuffix(runner.RunName(), ".csv")
+
+	err := writeOutput(c, true, baseName+"_cycle_time_scatter.svg", charts.NewCycleTimeScatter(runner).SVG)
+	if err != nil {
+		return err
+	}
+	return writeOutput(c, true, baseName+"_cycle_time_histogram.svg", charts.NewCycleTimeHistogram(runner).SVG)
+}
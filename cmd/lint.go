@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"github.com/3xcellent/github-metrics/metrics/runners"
+	"github.com/3xcellent/github-metrics/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	lintCmd = &cobra.Command{
+		Use:     "lint [board_name]",
+		Aliases: []string{"quality"},
+		Short:   "report board data quality anomalies that make metrics unreliable",
+		Long:    "replays the events of every issue on a board and lists cards dropped directly into the end column, issues closed but never moved, skipped columns, unknown columns and dates inferred rather than observed, followed by a board data confidence score",
+		RunE:    lint,
+		Args:    cobra.MinimumNArgs(1),
+	}
+	lintFormat string
+)
+
+func init() {
+	addFormatFlag(lintCmd, &lintFormat, dataFormats...)
+}
+
+func lint(c *cobra.Command, args []string) error {
+	ctx := c.Context()
+
+	format, err := output.ParseFormat(lintFormat, dataFormats...)
+	if err != nil {
+		return err
+	}
+
+	client, runCfg, closeClient, err := SetupCLI(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	defer closeClient()
+
+	runCfg.MetricName = "quality"
+
+	runner, err := runners.New(runCfg, client)
+	if err != nil {
+		return err
+	}
+
+	err = runner.Run(ctx)
+	if err != nil {
+		return err
+	}
+
+	report := runner.(*runners.QualityRunner).Report()
+	return writeResultWithSummary(c, runCfg.CreateFile, runner.RunName(), format, "anomalies", output.Result{
+		Rows:    runner.Values(),
+		Records: runner.Results(),
+	}, output.Result{
+		Rows:    report.Values(),
+		Records: report,
+	})
+}
@@ -2,21 +2,33 @@ package cmd
 
 import (
 	"context"
+	"fmt"
 
-	"github.com/3xcellent/github-metrics/client"
 	"github.com/3xcellent/github-metrics/config"
+	"github.com/3xcellent/github-metrics/output"
 	"github.com/spf13/cobra"
 )
 
-var orgsCommand = &cobra.Command{
-	Use:   "orgs",
-	Short: "lists available orgs",
-	Long:  "lists available orgs",
-	RunE:  getOrgs,
+var (
+	orgsCommand = &cobra.Command{
+		Use:   "orgs",
+		Short: "lists available orgs",
+		Long:  "lists available orgs",
+		RunE:  getOrgs,
+	}
+	orgsFormat string
+)
+
+func init() {
+	addFormatFlag(orgsCommand, &orgsFormat, output.Text, output.CSV, output.JSON, output.NDJSON)
 }
 
 func getOrgs(c *cobra.Command, args []string) error {
-	var err error
+	format, err := output.ParseFormat(orgsFormat, output.Text, output.CSV, output.JSON, output.NDJSON)
+	if err != nil {
+		return err
+	}
+
 	if Config == nil {
 		Config, err = config.NewDefaultConfig()
 		if err != nil {
@@ -25,22 +37,23 @@ func getOrgs(c *cobra.Command, args []string) error {
 	}
 
 	ctx := context.Background()
-	ghClient, err := client.New(ctx, Config.API)
+	ghClient, err := newGithubClient(ctx, Config, "")
 	if err != nil {
-		panic(err)
+		return err
 	}
 
-	orgs, err := ghClient.GetUserOrgs(ctx, Config.Owner)
+	orgs, err := ghClient.GetUserOrgs(ctx, Config.ProfileOwner(""))
 	if err != nil {
 		return err
 	}
 
+	result := output.Result{
+		Rows:    [][]string{{"Name", "ID", "URL", "Repos URL"}},
+		Records: orgs,
+	}
 	for _, org := range orgs {
-		c.Printf("Name:\t%s\n", org.Name)
-		c.Println("ID:\t", org.ID)
-		c.Println("URL:\t", org.URL)
-		c.Println("repos url:\t", org.ReposURL)
+		result.Rows = append(result.Rows, []string{org.Name, fmt.Sprint(org.ID), org.URL, org.ReposURL})
 	}
 
-	return nil
+	return output.Write(c.OutOrStdout(), format, result)
 }
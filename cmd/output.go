@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	"github.com/3xcellent/github-metrics/apperrors"
+	"github.com/3xcellent/github-metrics/output"
+	"github.com/spf13/cobra"
+)
+
+// chart and report formats, only available for the issues and columns commands
+const (
+	svgFormat  output.Format = "svg"
+	pngFormat  output.Format = "png"
+	htmlFormat output.Format = "html"
+)
+
+// dataFormats - output formats supported by every metrics command
+var dataFormats = []output.Format{output.CSV, output.JSON, output.NDJSON}
+
+// binaryFormats - output formats only written to files, never to stdout
+var binaryFormats = []output.Format{pngFormat, output.XLSX}
+
+// requireFile - returns a Config error when format is binary and createFile is not set
+func requireFile(format output.Format, createFile bool) error {
+	for _, binary := range binaryFormats {
+		if format == binary && !createFile {
+			return apperrors.Errorf(apperrors.Config, "--format %s is only written to a file, add --create-file", format)
+		}
+	}
+	return nil
+}
+
+// addFormatFlag - adds the --format flag to cmd accepting formats, the first is the default
+func addFormatFlag(cmd *cobra.Command, format *string, formats ...output.Format) {
+	cmd.Flags().StringVarP(format, "format", "f", string(formats[0]), output.Usage(formats...))
+}
+
+// withExtension - replaces the extension of the runner's file name
+func withExtension(runName, ext string) string {
+	return strings.TrimSuffix(runName, ".csv") + "." + ext
+}
+
+// writeResult - writes the result in format to outpath, with the format's extension, when createFile is set, otherwise to stdout
+func writeResult(c *cobra.Command, createFile bool, outpath string, format output.Format, result output.Result) error {
+	return writeOutput(c, createFile, withExtension(outpath, format.Ext()), func(w io.Writer) error {
+		return output.Write(w, format, result)
+	})
+}
+
+// writeResultWithSummary - writes the result to outpath and its summary to the _summary file of outpath when
+// createFile is set, otherwise to stdout; json and ndjson on stdout are one document of the records, under name, and
+// the summary
+func writeResultWithSummary(c *cobra.Command, createFile bool, outpath string, format output.Format, name string, result, summary output.Result) error {
+	if !createFile && (format == output.JSON || format == output.NDJSON) {
+		return writeResult(c, false, outpath, format, output.Result{
+			Records: map[string]interface{}{name: result.Records, "summary": summary.Records},
+		})
+	}
+	if err := writeResult(c, createFile, outpath, format, result); err != nil {
+		return err
+	}
+	return writeResult(c, createFile, strings.TrimSuffix(outpath, ".csv")+"_summary.csv", format, summary)
+}
+
+// writeOutput - writes to outpath when createFile is set, otherwise to stdout
+func writeOutput(c *cobra.Command, createFile bool, outpath string, write func(io.Writer) error) error {
+	if !createFile {
+		return write(c.OutOrStdout())
+	}
+
+	logger.Debugf("writing to: %s", outpath)
+	output, err := os.Create(outpath)
+	if err != nil {
+		return err
+	}
+	defer output.Close()
+
+	if err := write(output); err != nil {
+		return err
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	c.Printf("Wrote to: file://%s/%s\n", wd, outpath)
+	return nil
+}
@@ -0,0 +1,102 @@
+package cmd_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/3xcellent/github-metrics/apperrors"
+	"github.com/3xcellent/github-metrics/cmd"
+	"github.com/3xcellent/github-metrics/models"
+	"github.com/3xcellent/github-metrics/store"
+	"github.com/3xcellent/github-metrics/tools/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// inBoardDir - changes to a temporary directory with a config of a board synced to a store, restored and removed
+// by the returned func
+func inBoardDir(t *testing.T) (storePath string, cleanup func()) {
+	ctx := context.Background()
+	cols := testhelpers.NewProjectColumns(3)
+	start := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	dir, err := ioutil.TempDir("", "cmd")
+	require.NoError(t, err)
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	cleanup = func() {
+		os.Chdir(wd)
+		os.RemoveAll(dir)
+	}
+
+	storePath = filepath.Join(dir, "board.db")
+	s, err := store.Open(storePath)
+	require.NoError(t, err)
+	defer s.Close()
+	require.NoError(t, s.SaveProject(ctx, models.Project{ID: 1, Name: "Board", Owner: "owner"}, cols))
+	require.NoError(t, s.SaveColumnRepositories(ctx, cols[2].ID, models.Repositories{{Owner: "owner", Name: "repo", ID: 9}}))
+	require.NoError(t, s.SaveIssues(ctx, models.Issues{{
+		Owner: "owner", RepoName: "repo", Number: 1, Title: "done", CreatedAt: start,
+		Events: models.IssueEvents{
+			{Event: "added_to_project", ProjectID: 1, Type: models.AddedToProject, ColumnName: cols[1].Name, CreatedAt: start.AddDate(0, 0, 1)},
+			{Event: "moved_columns_in_project", ProjectID: 1, Type: models.MovedColumns, ColumnName: cols[2].Name, PreviousColumnName: cols[1].Name, CreatedAt: start.AddDate(0, 0, 3)},
+		},
+	}}))
+
+	config := fmt.Sprintf("---\nOwner: owner\nRunConfigs:\n  - name: Board\n    projectID: 1\n    startColumn: %s\n    endColumn: %s\n", cols[1].Name, cols[2].Name)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "config.yaml"), []byte(config), 0644))
+	return storePath, cleanup
+}
+
+func TestSummaryOnStdout(t *testing.T) {
+	storePath, cleanup := inBoardDir(t)
+	defer cleanup()
+	args := []string{"--store", storePath, "--year", "2020", "--month", "6", "--format", "json"}
+
+	t.Run("issues writes the issues and summary as one json document", func(t *testing.T) {
+		out, err := executeCommandWithContext(context.Background(), cmd.MetricsCommand, append([]string{"issues", "Board", "--summary"}, args...)...)
+		require.NoError(t, err)
+
+		var document struct {
+			Issues  []map[string]interface{} `json:"issues"`
+			Summary map[string]interface{}   `json:"summary"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(out), &document), out)
+		assert.Len(t, document.Issues, 1)
+		assert.NotEmpty(t, document.Summary)
+	})
+
+	t.Run("lint writes the anomalies and summary as one json document", func(t *testing.T) {
+		out, err := executeCommandWithContext(context.Background(), cmd.MetricsCommand, append([]string{"lint", "Board"}, args...)...)
+		require.NoError(t, err)
+
+		var document struct {
+			Anomalies []map[string]interface{} `json:"anomalies"`
+			Summary   map[string]interface{}   `json:"summary"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(out), &document), out)
+		assert.NotEmpty(t, document.Summary)
+	})
+}
+
+func TestBinaryFormatsOnStdout(t *testing.T) {
+	storePath, cleanup := inBoardDir(t)
+	defer cleanup()
+	args := []string{"--store", storePath, "--year", "2020", "--month", "6"}
+
+	t.Run("columns and issues return a config error for binary formats without --create-file", func(t *testing.T) {
+		for _, run := range [][]string{{"columns", "Board", "--format", "png"}, {"columns", "Board", "--format", "xlsx"}, {"issues", "Board", "--format", "xlsx"}} {
+			out, err := executeCommandWithContext(context.Background(), cmd.MetricsCommand, append(run, args...)...)
+			require.Error(t, err, run)
+			assert.True(t, apperrors.Is(err, apperrors.Config), run)
+			assert.NotContains(t, out, "PNG", run)
+		}
+	})
+}
@@ -2,23 +2,34 @@ package cmd
 
 import (
 	"context"
+	"fmt"
 
-	"github.com/3xcellent/github-metrics/client"
 	"github.com/3xcellent/github-metrics/config"
+	"github.com/3xcellent/github-metrics/output"
 	"github.com/spf13/cobra"
 )
 
-var projectCommand = &cobra.Command{
-	Use:   "project [name]",
-	Short: "shows information about a specific project",
-	Long:  "shows information about a specific project",
-	RunE:  getProject,
-	Args:  cobra.MinimumNArgs(1),
+var (
+	projectCommand = &cobra.Command{
+		Use:   "project [name]",
+		Short: "shows information about a specific project",
+		Long:  "shows information about a specific project",
+		RunE:  getProject,
+		Args:  cobra.MinimumNArgs(1),
+	}
+	projectFormat string
+)
+
+func init() {
+	addFormatFlag(projectCommand, &projectFormat, output.Text, output.CSV, output.JSON, output.NDJSON)
 }
 
 func getProject(c *cobra.Command, args []string) error {
+	format, err := output.ParseFormat(projectFormat, output.Text, output.CSV, output.JSON, output.NDJSON)
+	if err != nil {
+		return err
+	}
 
-	var err error
 	if Config == nil {
 		Config, err = config.NewDefaultConfig()
 		if err != nil {
@@ -32,9 +43,9 @@ func getProject(c *cobra.Command, args []string) error {
 	}
 
 	ctx := context.Background()
-	ghClient, err := client.New(ctx, Config.API)
+	ghClient, err := newGithubClient(ctx, Config, runCfg.Profile)
 	if err != nil {
-		panic(err)
+		return err
 	}
 
 	project, err := ghClient.GetProject(ctx, runCfg.ProjectID)
@@ -42,11 +53,11 @@ func getProject(c *cobra.Command, args []string) error {
 		return err
 	}
 
-	c.Println("Name:\t", project.Name)
-	c.Println("ID:\t", project.ID)
-	c.Println("URL:\t", project.URL)
-	c.Println("Owner:\t", project.OwnerURL)
-	c.Println("Body:\t", project.Body)
-
-	return nil
+	return output.Write(c.OutOrStdout(), format, output.Result{
+		Rows: [][]string{
+			{"Name", "ID", "URL", "Owner", "Body"},
+			{project.Name, fmt.Sprint(project.ID), project.URL, project.OwnerURL, project.Body},
+		},
+		Records: project,
+	})
 }
@@ -2,23 +2,34 @@ package cmd
 
 import (
 	"context"
-	"errors"
+	"fmt"
 
-	"github.com/3xcellent/github-metrics/client"
+	"github.com/3xcellent/github-metrics/apperrors"
 	"github.com/3xcellent/github-metrics/config"
-	"github.com/sirupsen/logrus"
+	"github.com/3xcellent/github-metrics/output"
 	"github.com/spf13/cobra"
 )
 
-var projectsCommand = &cobra.Command{
-	Use:   "projects",
-	Short: "lists available projects",
-	Long:  "lists available projects",
-	RunE:  getProjects,
+var (
+	projectsCommand = &cobra.Command{
+		Use:   "projects",
+		Short: "lists available projects",
+		Long:  "lists available projects",
+		RunE:  getProjects,
+	}
+	projectsFormat string
+)
+
+func init() {
+	addFormatFlag(projectsCommand, &projectsFormat, output.Text, output.CSV, output.JSON, output.NDJSON)
 }
 
 func getProjects(c *cobra.Command, args []string) error {
-	var err error
+	format, err := output.ParseFormat(projectsFormat, output.Text, output.CSV, output.JSON, output.NDJSON)
+	if err != nil {
+		return err
+	}
+
 	if Config == nil {
 		Config, err = config.NewDefaultConfig()
 		if err != nil {
@@ -26,34 +37,34 @@ func getProjects(c *cobra.Command, args []string) error {
 		}
 	}
 
-	logrus.Debugf("getting projects for owner: %s", Config.Owner)
-
 	ctx := context.Background()
-	ghClient, err := client.New(ctx, Config.API)
+	ghClient, err := newGithubClient(ctx, Config, "")
 	if err != nil {
-		panic(err)
+		return err
 	}
 
+	owner := Config.ProfileOwner("")
 	if len(args) > 0 && args[0] != "" {
-		Config.Owner = args[0]
+		owner = args[0]
 	}
 
-	if Config.Owner == "" {
-		panic(errors.New("must provid owner"))
+	if owner == "" {
+		return apperrors.New(apperrors.Config, "owner required, pass it or set the owner of the config or its profile")
 	}
+	logger.Debugf("getting projects for owner: %s", owner)
 
-	projects, err := ghClient.GetProjects(ctx, Config.Owner)
+	projects, err := ghClient.GetProjects(ctx, owner)
 	if err != nil {
 		return err
 	}
 
+	result := output.Result{
+		Rows:    [][]string{{"Name", "ID", "URL", "Owner", "Repo"}},
+		Records: projects,
+	}
 	for _, p := range projects {
-		c.Println("Name:\t", p.Name)
-		c.Println("ID:\t", p.ID)
-		c.Println("URL:\t", p.URL)
-		c.Println("Owner:\t", p.Owner)
-		c.Println("Repo:\t", p.Repo)
+		result.Rows = append(result.Rows, []string{p.Name, fmt.Sprint(p.ID), p.URL, p.Owner, p.Repo})
 	}
 
-	return nil
+	return output.Write(c.OutOrStdout(), format, result)
 }
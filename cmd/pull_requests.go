@@ -1,16 +1,15 @@
 package cmd
 
 import (
-	"encoding/csv"
-	"errors"
 	"fmt"
-	"os"
 	"strings"
 
+	"github.com/3xcellent/github-metrics/apperrors"
 	"github.com/3xcellent/github-metrics/client"
 	"github.com/3xcellent/github-metrics/config"
 	"github.com/3xcellent/github-metrics/metrics"
 	"github.com/3xcellent/github-metrics/models"
+	"github.com/3xcellent/github-metrics/output"
 	"github.com/spf13/cobra"
 )
 
@@ -21,15 +20,21 @@ var (
 		Long:  "aggregate duration pull_request are open for the list of github repos either using --repoName=repo1,repo2 flag or name of the board to gather all repos within year and month provided (default is current year and month)",
 		RunE:  pullRequests,
 	}
-	repoNames string
+	repoNames          string
+	pullRequestsFormat string
 )
 
 func init() {
-	pullRequestsCmd.Flags().StringVarP(&repoNames, "repoNames", "r", "", "list of repos to generate reports for (repo1,repo2)")
+	pullRequestsCmd.Flags().StringVar(&repoNames, "repoNames", "", "list of repos to generate reports for (repo1,repo2)")
+	addFormatFlag(pullRequestsCmd, &pullRequestsFormat, dataFormats...)
 }
 
 func pullRequests(c *cobra.Command, args []string) error {
-	var err error
+	format, err := output.ParseFormat(pullRequestsFormat, dataFormats...)
+	if err != nil {
+		return err
+	}
+
 	if Config == nil {
 		Config, err = config.NewDefaultConfig()
 		if err != nil {
@@ -38,19 +43,18 @@ func pullRequests(c *cobra.Command, args []string) error {
 	}
 
 	if len(args) == 0 && repoNames == "" {
-		return errors.New("project name or --repoName required")
-	}
-	ghClient, err := client.New(c.Context(), Config.API)
-	if err != nil {
-		return err
+		return apperrors.New(apperrors.Config, "project name or --repoNames required")
 	}
 
-	runCfg, err := Config.GetRunConfig(args[0])
-	if err != nil {
-		return err
+	// without a project the repos of --repoNames are of the owner of the selected profile
+	runCfg := config.RunConfig{Owner: Config.ProfileOwner("")}
+	if len(args) > 0 {
+		runCfg, err = Config.GetRunConfig(args[0])
+		if err != nil {
+			return err
+		}
 	}
-
-	projectColumns, err := ghClient.GetProjectColumns(c.Context(), runCfg.ProjectID)
+	ghClient, err := newGithubClient(c.Context(), Config, runCfg.Profile)
 	if err != nil {
 		return err
 	}
@@ -64,6 +68,13 @@ func pullRequests(c *cobra.Command, args []string) error {
 			})
 		}
 	} else {
+		projectColumns, err := ghClient.GetProjectColumns(c.Context(), runCfg.ProjectID)
+		if err != nil {
+			return err
+		}
+		if len(projectColumns) == 0 {
+			return apperrors.Errorf(apperrors.Config, "project %d of %s has no columns", runCfg.ProjectID, runCfg.Name)
+		}
 		repoList, err = ghClient.GetReposFromProjectColumn(c.Context(), projectColumns[len(projectColumns)-1].ID)
 		if err != nil {
 			return err
@@ -72,52 +83,53 @@ func pullRequests(c *cobra.Command, args []string) error {
 
 	for _, repo := range repoList {
 		repoName := strings.Trim(repo.Name, " ")
-		output, err := os.Create(fmt.Sprintf("%s_pullrequests.csv", repoName))
-		writer := csv.NewWriter(output)
-
-		cols := []string{
-			"repo",
-			"issueNumber",
-			"CreatedAt",
-			"CreatedBy",
-			"Group",
-			"ClosedAt",
-			"RequestedReviewers",
-			"Days Open",
-		}
-
-		err = writer.Write(cols)
+		prs, err := ghClient.GetPullRequests(c.Context(), runCfg.Owner, repo.Name)
 		if err != nil {
-			panic(err)
+			return err
 		}
 
-		prs, err := ghClient.GetPullRequests(c.Context(), Config.Owner, repo.Name)
-		if err != nil {
-			return err
+		result := output.Result{
+			Rows: [][]string{{
+				"repo",
+				"issueNumber",
+				"CreatedAt",
+				"CreatedBy",
+				"Group",
+				"ClosedAt",
+				"RequestedReviewers",
+				"Days Open",
+			}},
 		}
+		records := make([]metrics.PullRequestResult, 0, len(prs))
 		for _, pr := range prs {
 			if pr.ClosedAt.IsZero() {
 				// pr is still open
 				continue
 			}
 
-			_, repoName, issueNumber := client.ParseIssueURL(pr.IssueURL)
-			cols := []string{
-				repoName,
+			_, prRepoName, issueNumber, err := client.ParseIssueURL(pr.IssueURL)
+			if err != nil {
+				return err
+			}
+			group := Config.CreatedByGroup(pr.CreatedByUser)
+			result.Rows = append(result.Rows, []string{
+				prRepoName,
 				fmt.Sprintf("%d", issueNumber),
 				pr.CreatedAt.String(),
 				pr.CreatedByUser,
-				Config.CreatedByGroup(pr.CreatedByUser),
+				group,
 				pr.ClosedAt.String(),
 				strings.Join(pr.RequestedReviewers, ","),
 				metrics.FmtDaysHours(pr.ClosedAt.Sub(pr.CreatedAt)),
-			}
-			err := writer.Write(cols)
-			if err != nil {
-				panic(err)
-			}
+			})
+			records = append(records, metrics.NewPullRequestResult(pr, prRepoName, issueNumber, group))
+		}
+		result.Records = records
+
+		err = writeResult(c, true, fmt.Sprintf("%s_pullrequests.csv", repoName), format, result)
+		if err != nil {
+			return err
 		}
-		writer.Flush()
 	}
 	return nil
 }
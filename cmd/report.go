@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"io"
+
+	"github.com/3xcellent/github-metrics/config"
+	"github.com/3xcellent/github-metrics/metrics/runners"
+	"github.com/3xcellent/github-metrics/report"
+	"github.com/spf13/cobra"
+)
+
+// writeReport - writes the html report of a run; the issues or columns runner not provided is run first
+func writeReport(c *cobra.Command, client runners.Client, runCfg config.RunConfig, issuesRunner *runners.IssuesRunner, columnsRunner *runners.ColumnsRunner) error {
+	ctx := c.Context()
+	if issuesRunner == nil {
+		runCfg.MetricName = "issues"
+		issuesRunner = runners.NewIssuesRunner(runCfg, client)
+		if err := issuesRunner.Run(ctx); err != nil {
+			return err
+		}
+	}
+	if columnsRunner == nil {
+		runCfg.MetricName = "columns"
+		columnsRunner = runners.NewColumnsRunner(runCfg, client)
+		if err := columnsRunner.Run(ctx); err != nil {
+			return err
+		}
+	}
+
+	r, err := report.New(runCfg, issuesRunner, columnsRunner)
+	if err != nil {
+		return err
+	}
+	tmpl, err := report.Template(runCfg.ReportTemplate)
+	if err != nil {
+		return err
+	}
+
+	reportRunner := *issuesRunner.Runner
+	reportRunner.MetricName = "report"
+	return writeOutput(c, runCfg.CreateFile, withExtension(reportRunner.RunName(), "html"), func(w io.Writer) error {
+		return r.Write(w, tmpl)
+	})
+}
@@ -4,20 +4,30 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/3xcellent/github-metrics/client"
 	"github.com/3xcellent/github-metrics/config"
+	"github.com/3xcellent/github-metrics/output"
 	"github.com/spf13/cobra"
 )
 
-var reposCommand = &cobra.Command{
-	Use:   "repos",
-	Short: "shows list of repos",
-	Long:  "shows list of repos",
-	RunE:  repos,
+var (
+	reposCommand = &cobra.Command{
+		Use:   "repos",
+		Short: "shows list of repos",
+		Long:  "shows list of repos",
+		RunE:  repos,
+	}
+	reposFormat string
+)
+
+func init() {
+	addFormatFlag(reposCommand, &reposFormat, output.Text, output.CSV, output.JSON, output.NDJSON)
 }
 
 func repos(c *cobra.Command, args []string) error {
-	var err error
+	format, err := output.ParseFormat(reposFormat, output.Text, output.CSV, output.JSON, output.NDJSON)
+	if err != nil {
+		return err
+	}
 	ctx := context.Background()
 
 	if Config == nil {
@@ -28,24 +38,26 @@ func repos(c *cobra.Command, args []string) error {
 
 	}
 
-	client, err := client.New(ctx, Config.API)
+	client, err := newGithubClient(ctx, Config, "")
 	if err != nil {
 		return err
 	}
 
-	repos, err := client.GetUserRepos(ctx, Config.Owner)
+	repos, err := client.GetUserRepos(ctx, Config.ProfileOwner(""))
 	if err != nil {
 		return err
 	}
 
+	result := output.Result{
+		Rows:    [][]string{{"Name", "ID", "URL"}},
+		Records: repos,
+	}
 	for _, r := range repos {
-		fmt.Printf("name: %s", r.Name)
-		fmt.Printf("id: %v", r.ID)
-		fmt.Printf("url: %s", r.URL)
+		result.Rows = append(result.Rows, []string{r.Name, fmt.Sprint(r.ID), r.URL})
 	}
 
 	// repos := Config.GithubClient.GetReposFromIssuesOnColumn(ctx, boardColumns[Config.EndColumnIndex].ID)
 	// c.Println(strings.Join(repos, ", "))
 
-	return nil
+	return output.Write(c.OutOrStdout(), format, result)
 }
@@ -2,16 +2,26 @@ package cmd
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
+	"github.com/3xcellent/github-metrics/apperrors"
 	"github.com/3xcellent/github-metrics/client"
 	"github.com/3xcellent/github-metrics/config"
-	"github.com/sirupsen/logrus"
+	"github.com/3xcellent/github-metrics/credentials"
+	"github.com/3xcellent/github-metrics/logging"
+	"github.com/3xcellent/github-metrics/metrics/runners"
+	"github.com/3xcellent/github-metrics/store"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
+var logger = logging.For("cmd")
+
 var (
 	// MetricsCommand - the root cobra.Command
 	MetricsCommand = &cobra.Command{
@@ -19,6 +29,12 @@ var (
 		Short: "github-metrics",
 		Long:  `Github Metrics gathers data from a github server and generates csv reports`,
 		Args:  cobra.MinimumNArgs(1),
+		// Execute prints errors with the hint of their kind
+		SilenceErrors: true,
+		// once flags and args are valid, errors are not about usage
+		PersistentPreRun: func(c *cobra.Command, args []string) {
+			c.SilenceUsage = true
+		},
 	}
 	verbose     bool
 	askForDate  bool
@@ -30,6 +46,10 @@ var (
 	outpath     string
 	repoName    string
 	newFile     bool
+	storePath   string
+	profile     string
+	logFormat   string
+	logLevel    string
 
 	// Config - instance of the config for CLI
 	Config *config.AppConfig
@@ -47,6 +67,11 @@ func init() {
 	MetricsCommand.PersistentFlags().StringVarP(&repoName, "repoName", "r", "", "repoName (use with repoName)")
 	MetricsCommand.PersistentFlags().IntVarP(&issueNumber, "issueNumber", "i", 0, "issueNumber (use with issueNumber)")
 	MetricsCommand.PersistentFlags().BoolVarP(&newFile, "create-file", "c", false, "set outpath path to [board_name]_[command_name]_[year]_[month].csv)")
+	MetricsCommand.PersistentFlags().StringVarP(&storePath, "store", "", "", "run metrics from the sqlite store at this path, kept up to date with the sync command")
+	MetricsCommand.PersistentFlags().StringVarP(&profile, "profile", "", "", "connection profile used by commands and run configs without a profile")
+
+	MetricsCommand.PersistentFlags().StringVarP(&logFormat, "log-format", "", "", "log output format, text (default) or json")
+	MetricsCommand.PersistentFlags().StringVarP(&logLevel, "log-level", "", "", "log level of every package, optionally followed by package levels, e.g. info,client=debug (default info, debug with --verbose)")
 
 	viper.BindPFlag("verbose", MetricsCommand.PersistentFlags().Lookup("verbose"))
 	viper.BindPFlag("api.token", MetricsCommand.PersistentFlags().Lookup("token"))
@@ -58,42 +83,183 @@ func init() {
 	viper.BindPFlag("createFile", MetricsCommand.PersistentFlags().Lookup("create-file"))
 	viper.BindPFlag("repoName", MetricsCommand.PersistentFlags().Lookup("repoName"))
 	viper.BindPFlag("issueNumber", MetricsCommand.PersistentFlags().Lookup("issueNumber"))
+	viper.BindPFlag("store", MetricsCommand.PersistentFlags().Lookup("store"))
+	viper.BindPFlag("profile", MetricsCommand.PersistentFlags().Lookup("profile"))
+	viper.BindPFlag("logFormat", MetricsCommand.PersistentFlags().Lookup("log-format"))
+	viper.BindPFlag("logLevel", MetricsCommand.PersistentFlags().Lookup("log-level"))
 
 	MetricsCommand.AddCommand(
+		checkCmd,
 		guiCmd,
 		orgsCommand,
 		projectCommand,
 		projectsCommand,
+		initCmd,
 		issuesCmd,
+		lintCmd,
 		columnsCmd,
+		configCmd,
+		explainCmd,
+		exportCmd,
 		pullRequestsCmd,
 		reposCommand,
+		runCmd,
+		scheduleCmd,
+		serveCmd,
+		syncCmd,
+		trendCmd,
 	)
 }
 
-// Execute runs the command
+// Execute runs the command, exiting with the code of the kind of error it returns, see apperrors
 func Execute() {
-	if err := MetricsCommand.Execute(); err != nil {
-		logrus.Debug(err)
-		os.Exit(1)
+	c, err := MetricsCommand.ExecuteC()
+	if err == nil {
+		return
+	}
+	if !c.SilenceUsage {
+		// the command did not run: it is unknown, or its flags or args are invalid
+		err = apperrors.Wrap(apperrors.Config, err, "")
 	}
+	exit(err)
 }
 
-// SetupCLI - will return context
-func SetupCLI(ctx context.Context, runCfgName string) (*client.MetricsClient, config.RunConfig, error) {
-	cfg, err := config.NewDefaultConfig()
+// interruptContext - returns ctx cancelled on an interrupt or SIGTERM, so long running commands stop cleanly; a second
+// signal exits immediately
+func interruptContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		defer signal.Stop(signals)
+		select {
+		case sig := <-signals:
+			logger.Infof("received %s, stopping", sig)
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
+// exit - prints err followed by the hint of its kind and exits with the code of its kind
+func exit(err error) {
+	logger.Debugf("%+v", err)
+	fmt.Fprintln(os.Stderr, "Error:", apperrors.Message(err))
+	os.Exit(apperrors.ExitCode(err))
+}
+
+// SetupCLI - returns the client metrics are run with, the github api or the store when configured, the func closing
+// it, and the named RunConfig
+func SetupCLI(ctx context.Context, runCfgName string) (runners.Client, config.RunConfig, func() error, error) {
+	cfg, runCfg, err := setupConfig(runCfgName)
+	if err != nil {
+		return nil, config.RunConfig{}, nil, err
+	}
+
+	client, closeClient, err := newClient(ctx, cfg, runCfg.Profile)
+	if err != nil {
+		return nil, config.RunConfig{}, nil, err
+	}
+	return client, runCfg, closeClient, nil
+}
+
+// newClient - returns the store when configured, otherwise a github api client of the connection profile, the
+// selected profile when blank, and the func closing it
+func newClient(ctx context.Context, cfg *config.AppConfig, profile string) (runners.Client, func() error, error) {
+	if cfg.Store != "" {
+		logger.Debugf("running from store: %s", cfg.Store)
+		s, err := store.Open(cfg.Store)
+		if err != nil {
+			return nil, nil, err
+		}
+		return s, s.Close, nil
+	}
+	ghClient, err := newGithubClient(ctx, cfg, profile)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ghClient, noClose, nil
+}
+
+// noClose - closes a client holding nothing open
+func noClose() error {
+	return nil
+}
+
+// newGithubClient - returns a github api client of the connection profile, the selected profile when blank
+func newGithubClient(ctx context.Context, cfg *config.AppConfig, profile string) (*client.MetricsClient, error) {
+	api, err := cfg.APIConfig(profile)
+	if err != nil {
+		return nil, err
+	}
+	if profile != "" {
+		logger.Debugf("connecting with profile: %s", profile)
+	}
+	if api, err = credentials.Resolve(ctx, api); err != nil {
+		return nil, err
+	}
+	return client.New(ctx, api)
+}
+
+// newClients - returns the client of the runConfigs without a connection profile, nil when they all have one, and
+// the clients of their profiles, and the func closing them; the store for all of them when running from it
+func newClients(ctx context.Context, cfg *config.AppConfig, runConfigs config.RunConfigs) (runners.Client, runners.ProfileClients, func() error, error) {
+	if cfg.Store != "" {
+		metricsClient, closeClient, err := newClient(ctx, cfg, "")
+		return metricsClient, nil, closeClient, err
+	}
+
+	var metricsClient runners.Client
+	clients := make(runners.ProfileClients)
+	for _, runCfg := range runConfigs {
+		if runCfg.Profile == "" {
+			if metricsClient == nil {
+				ghClient, err := newGithubClient(ctx, cfg, "")
+				if err != nil {
+					return nil, nil, nil, err
+				}
+				metricsClient = ghClient
+			}
+			continue
+		}
+		if _, found := clients[runCfg.Profile]; found {
+			continue
+		}
+		ghClient, err := newGithubClient(ctx, cfg, runCfg.Profile)
+		if err != nil {
+			return nil, nil, nil, errors.Wrapf(err, "profile %s", runCfg.Profile)
+		}
+		clients[runCfg.Profile] = ghClient
+	}
+	return metricsClient, clients, noClose, nil
+}
+
+// setupGithubCLI - returns a github api client, regardless of the store setting, and the named RunConfig
+func setupGithubCLI(ctx context.Context, runCfgName string) (*client.MetricsClient, config.RunConfig, error) {
+	cfg, runCfg, err := setupConfig(runCfgName)
+	if err != nil {
+		return nil, config.RunConfig{}, err
+	}
+
+	client, err := newGithubClient(ctx, cfg, runCfg.Profile)
 	if err != nil {
 		return nil, config.RunConfig{}, err
 	}
+	return client, runCfg, nil
+}
 
-	client, err := client.New(ctx, cfg.API)
+// setupConfig - loads Config and returns the named RunConfig
+func setupConfig(runCfgName string) (*config.AppConfig, config.RunConfig, error) {
+	cfg, err := config.NewDefaultConfig()
 	if err != nil {
 		return nil, config.RunConfig{}, err
 	}
+	Config = cfg
 
 	runCfg, err := cfg.GetRunConfig(runCfgName)
 	if err != nil {
 		return nil, config.RunConfig{}, err
 	}
-	return client, runCfg, nil
+	return cfg, runCfg, nil
 }
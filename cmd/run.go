@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/3xcellent/github-metrics/apperrors"
+	"github.com/3xcellent/github-metrics/batch"
+	"github.com/3xcellent/github-metrics/config"
+	"github.com/3xcellent/github-metrics/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	runCmd = &cobra.Command{
+		Use:   "run [board_name...] --all",
+		Short: "run several metrics of several boards, writing each to a file",
+		Long:  "runs each of the metrics of each named board, or of every run config with --all, for the year and month and writes each result to a file named by the filename template; the metrics of a board share the data fetched for it. Prints a summary of the runs and exits non-zero when any failed, with code 6 when others succeeded",
+		RunE:  run,
+	}
+	runAll      bool
+	runMetrics  []string
+	runFormat   string
+	runFilename string
+	runDir      string
+)
+
+func init() {
+	runCmd.Flags().BoolVar(&runAll, "all", false, "run every run config of the config")
+	runCmd.Flags().StringSliceVar(&runMetrics, "metrics", []string{"issues", "columns", "quality"}, "metrics to run for each board")
+	runCmd.Flags().StringVar(&runFilename, "filename", config.DefaultFilename, "text/template of each file name, with .Board, .Metric, .Year, .Month, .Date and .Ext")
+	runCmd.Flags().StringVar(&runDir, "dir", "", "directory the files are written to (default the outpath, or the working directory)")
+	addFormatFlag(runCmd, &runFormat, dataFormats...)
+}
+
+func run(c *cobra.Command, args []string) error {
+	ctx := c.Context()
+
+	format, err := output.ParseFormat(runFormat, dataFormats...)
+	if err != nil {
+		return err
+	}
+	if runAll == (len(args) > 0) {
+		return apperrors.New(apperrors.Config, "name the boards to run, or use --all")
+	}
+
+	cfg, err := config.NewDefaultConfig()
+	if err != nil {
+		return err
+	}
+	Config = cfg
+	if runAll {
+		args = cfg.RunConfigs.SortedNames()
+	}
+
+	runConfigs := make(config.RunConfigs, 0, len(args))
+	for _, name := range args {
+		runCfg, err := cfg.GetRunConfig(name)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		runConfigs = append(runConfigs, runCfg)
+	}
+
+	dir := runDir
+	if dir == "" {
+		dir = cfg.OutputPath
+	}
+	metricsClient, profiles, closeClients, err := newClients(ctx, cfg, runConfigs)
+	if err != nil {
+		return err
+	}
+	defer closeClients()
+	b, err := batch.New(metricsClient, format, dir, runFilename)
+	if err != nil {
+		return err
+	}
+	b.Profiles = profiles
+	results := b.Run(ctx, runConfigs, runMetrics)
+
+	w := tabwriter.NewWriter(c.OutOrStdout(), 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "Board\tMetric\tStatus\tDuration\tFile")
+	for _, result := range results {
+		status, file := "ok", result.File
+		if result.Err != nil {
+			status, file = "failed", result.Err.Error()
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", result.Board, result.Metric, status, result.Duration.Round(time.Millisecond), file)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if failed := batch.Failed(results); len(failed) > 0 {
+		kind := apperrors.PartialData
+		if len(failed) == len(results) {
+			// nothing was written, the first failure tells why
+			kind = apperrors.KindOf(failed[0].Err)
+		}
+		return apperrors.Errorf(kind, "%d of %d runs failed", len(failed), len(results))
+	}
+	return nil
+}
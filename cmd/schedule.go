@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/3xcellent/github-metrics/apperrors"
+	"github.com/3xcellent/github-metrics/config"
+	"github.com/3xcellent/github-metrics/schedule"
+	"github.com/spf13/cobra"
+)
+
+var (
+	scheduleCmd = &cobra.Command{
+		Use:   "schedule",
+		Short: "run the metrics of the schedules of the config",
+		Long:  "runs the metrics of the RunConfigs of each schedule of the config when its cron expression is due, writing the results to its destination; failed runs are retried and every run is recorded in the schedule history",
+	}
+	scheduleListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "list the schedules with their last and next runs",
+		RunE:  scheduleList,
+	}
+	scheduleRunDueCmd = &cobra.Command{
+		Use:   "run-due",
+		Short: "run the schedules that are due once, e.g. from system cron",
+		Long:  "runs each schedule whose latest scheduled time is after its last recorded run; a schedule whose times were missed is run once. Exits non-zero when a run fails after its retries",
+		RunE:  scheduleRunDue,
+	}
+	scheduleDaemonCmd = &cobra.Command{
+		Use:   "daemon",
+		Short: "run the schedules in the foreground as they become due",
+		RunE:  scheduleDaemon,
+	}
+)
+
+func init() {
+	scheduleCmd.AddCommand(scheduleListCmd, scheduleRunDueCmd, scheduleDaemonCmd)
+}
+
+// setupScheduler - returns the scheduler of the schedules of the config and the func closing its clients
+func setupScheduler(c *cobra.Command) (*schedule.Scheduler, func() error, error) {
+	cfg, err := config.NewDefaultConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+	Config = cfg
+
+	historyPath := cfg.ScheduleHistory
+	if historyPath == "" {
+		historyPath = config.DefaultScheduleHistory
+	}
+	history, err := schedule.LoadHistory(historyPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	scheduler := schedule.New(nil, cfg, history)
+	if err := scheduler.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	runConfigs := make(config.RunConfigs, 0)
+	for _, sched := range cfg.Schedules {
+		for _, name := range sched.RunConfigs {
+			runCfg, err := cfg.GetRunConfig(name)
+			if err != nil {
+				return nil, nil, err
+			}
+			runConfigs = append(runConfigs, runCfg)
+		}
+	}
+	var closeClients func() error
+	scheduler.Client, scheduler.Profiles, closeClients, err = newClients(c.Context(), cfg, runConfigs)
+	if err != nil {
+		return nil, nil, err
+	}
+	return scheduler, closeClients, nil
+}
+
+func scheduleList(c *cobra.Command, args []string) error {
+	cfg, err := config.NewDefaultConfig()
+	if err != nil {
+		return err
+	}
+	historyPath := cfg.ScheduleHistory
+	if historyPath == "" {
+		historyPath = config.DefaultScheduleHistory
+	}
+	history, err := schedule.LoadHistory(historyPath)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	w := tabwriter.NewWriter(c.OutOrStdout(), 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "Schedule\tCron\tLast Run\tStatus\tNext Run")
+	for _, s := range cfg.Schedules {
+		cron, err := schedule.ParseCron(s.Cron)
+		if err != nil {
+			return err
+		}
+		lastRun, status := "never", ""
+		if last, found := history.Last(s.Name); found {
+			lastRun = last.ScheduledAt.Local().Format(explainTimeFormat)
+			status = "ok"
+			if last.Error != "" {
+				status = "failed: " + last.Error
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", s.Name, s.Cron, lastRun, status, cron.Next(now).Format(explainTimeFormat))
+	}
+	return w.Flush()
+}
+
+func scheduleRunDue(c *cobra.Command, args []string) error {
+	scheduler, closeClients, err := setupScheduler(c)
+	if err != nil {
+		return err
+	}
+	defer closeClients()
+	records, err := scheduler.RunDue(c.Context())
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, record := range records {
+		if record.Error != "" {
+			failed++
+			logger.Errorf("schedule %q failed after %d attempts: %s", record.Schedule, record.Attempts, record.Error)
+		}
+	}
+	logger.Infof("ran %d due schedules", len(records))
+	if failed > 0 && failed < len(records) {
+		return apperrors.Errorf(apperrors.PartialData, "%d of %d schedules failed", failed, len(records))
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d schedules failed", failed, len(records))
+	}
+	return nil
+}
+
+func scheduleDaemon(c *cobra.Command, args []string) error {
+	scheduler, closeClients, err := setupScheduler(c)
+	if err != nil {
+		return err
+	}
+	defer closeClients()
+	ctx, stop := interruptContext(c.Context())
+	defer stop()
+
+	logger.Infof("running %d schedules", len(scheduler.Config.Schedules))
+	return scheduler.Daemon(ctx)
+}
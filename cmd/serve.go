@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/3xcellent/github-metrics/client"
+	"github.com/3xcellent/github-metrics/config"
+	"github.com/3xcellent/github-metrics/metrics/runners"
+	"github.com/3xcellent/github-metrics/server"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	serveCmd = &cobra.Command{
+		Use:   "serve [board_name...]",
+		Short: "periodically run boards and serve their metrics for prometheus, and a json api to run metrics",
+		Long:  "runs the issues and columns metrics of each board (the serve runConfigs of the config, or all run configs, when none are named) over the last days every interval and serves column WIP, cycle time histograms, open pull requests and the server's own run and github api metrics on /metrics in the prometheus text format, along with a json api listing projects and run configs and running any metric of any run config on request (GET /projects, GET /runconfigs, POST /runs, GET /runs/{id}, GET /runs/{id}/result), and a grafana json datasource (/search, /query, /annotations) with columns.<board>.<column> daily time series and issues.<board> tables",
+		RunE:  serve,
+	}
+)
+
+func init() {
+	serveCmd.Flags().String("addr", "", "address to listen on (default "+config.DefaultServeAddress+")")
+	serveCmd.Flags().Duration("interval", 0, "how often boards are run (default "+config.DefaultServeInterval.String()+")")
+	serveCmd.Flags().Int("days", 0, "number of days, up to and including today, each run covers (default 30)")
+	viper.BindPFlag("serve.address", serveCmd.Flags().Lookup("addr"))
+	viper.BindPFlag("serve.interval", serveCmd.Flags().Lookup("interval"))
+	viper.BindPFlag("serve.days", serveCmd.Flags().Lookup("days"))
+}
+
+func serve(c *cobra.Command, args []string) error {
+	ctx, stop := interruptContext(c.Context())
+	defer stop()
+
+	cfg, err := config.NewDefaultConfig()
+	if err != nil {
+		return err
+	}
+	Config = cfg
+	serveCfg := cfg.Serve.WithDefaults()
+	if len(args) > 0 {
+		serveCfg.RunConfigs = args
+	}
+	if len(serveCfg.RunConfigs) == 0 {
+		serveCfg.RunConfigs = cfg.RunConfigs.SortedNames()
+	}
+
+	runConfigs := make(config.RunConfigs, 0, len(serveCfg.RunConfigs))
+	for _, name := range serveCfg.RunConfigs {
+		runCfg, err := cfg.GetRunConfig(name)
+		if err != nil {
+			return err
+		}
+		runConfigs = append(runConfigs, runCfg)
+	}
+
+	apiRunConfigs := make(config.RunConfigs, 0, len(cfg.RunConfigs))
+	for _, name := range cfg.RunConfigs.SortedNames() {
+		runCfg, err := cfg.GetRunConfig(name)
+		if err != nil {
+			return err
+		}
+		apiRunConfigs = append(apiRunConfigs, runCfg)
+	}
+	metricsClient, profiles, closeClients, err := newClients(ctx, cfg, apiRunConfigs)
+	if err != nil {
+		return err
+	}
+	defer closeClients()
+	if metricsClient == nil {
+		// every board has a profile, /projects lists with the selected one
+		ghClient, err := newGithubClient(ctx, cfg, "")
+		if err != nil {
+			return err
+		}
+		metricsClient = ghClient
+	}
+
+	srv := server.New(metricsClient, runConfigs, serveCfg.Days)
+	srv.Profiles = profiles
+	srv.API = server.NewAPI(metricsClient, cfg.ProfileOwner(""), apiRunConfigs)
+	srv.API.Profiles = profiles
+	srv.API.Context = ctx
+	srv.Grafana = server.NewGrafana(metricsClient, apiRunConfigs, serveCfg.Interval)
+	srv.Grafana.Profiles = profiles
+	srv.Grafana.Context = ctx
+	if ghClient, ok := metricsClient.(*client.MetricsClient); ok {
+		srv.APIStats = profileAPIStats(cfg.Profile, ghClient, profiles)
+	}
+
+	// on an interrupt the server shuts down and in-flight runs are cancelled before returning
+	stopped := make(chan struct{})
+	go func() {
+		srv.Run(ctx, serveCfg.Interval)
+		close(stopped)
+	}()
+
+	httpServer := &http.Server{Addr: serveCfg.Address, Handler: srv.Handler()}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		httpServer.Shutdown(shutdownCtx)
+	}()
+
+	logger.Infof("serving %d boards on %s every %s", len(runConfigs), serveCfg.Address, serveCfg.Interval)
+	if err := httpServer.ListenAndServe(); err != http.ErrServerClosed {
+		return err
+	}
+	<-stopped
+	// runs requested through the api and grafana are cancelled too, and read the clients until they return
+	srv.API.Wait()
+	srv.Grafana.Wait()
+	return nil
+}
+
+// profileAPIStats - returns the api stats of the client of the selected profile and of each profile client by profile,
+// adding up the calls and keeping the lowest rate limit remaining of clients of the same profile
+func profileAPIStats(selected string, ghClient *client.MetricsClient, profiles runners.ProfileClients) func() map[string]client.APIStats {
+	return func() map[string]client.APIStats {
+		stats := map[string]client.APIStats{selected: ghClient.APIStats()}
+		for profile, profileClient := range profiles {
+			profileGhClient, ok := profileClient.(*client.MetricsClient)
+			if !ok {
+				continue
+			}
+			profileStats := profileGhClient.APIStats()
+			if prev, found := stats[profile]; found {
+				profileStats.Calls += prev.Calls
+				if profileStats.RateLimitRemaining < 0 || (prev.RateLimitRemaining >= 0 && prev.RateLimitRemaining < profileStats.RateLimitRemaining) {
+					profileStats.RateLimitRemaining = prev.RateLimitRemaining
+				}
+			}
+			stats[profile] = profileStats
+		}
+		return stats
+	}
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/3xcellent/github-metrics/client"
+	"github.com/3xcellent/github-metrics/config"
+	"github.com/3xcellent/github-metrics/store"
+	"github.com/spf13/cobra"
+)
+
+var (
+	syncCmd = &cobra.Command{
+		Use:   "sync [board_name...] --store [path]",
+		Short: "incrementally sync boards from github into the local store",
+		Long:  "saves the columns of each board (all run configs when none are named) and the issues, events and pull requests of the repos of its end column into the sqlite store; only issues updated since the previous sync of a repo are fetched, and issues deleted or transferred out of a repo are removed. Other commands run from the store when --store (or store: in the config) is set",
+		RunE:  sync,
+	}
+	syncPrune bool
+)
+
+func init() {
+	syncCmd.Flags().BoolVar(&syncPrune, "prune", true, "list every issue of each repo to remove those deleted or transferred")
+}
+
+func sync(c *cobra.Command, args []string) error {
+	ctx := c.Context()
+
+	cfg, err := config.NewDefaultConfig()
+	if err != nil {
+		return err
+	}
+	Config = cfg
+	if cfg.Store == "" {
+		return errors.New("--store path required")
+	}
+	if len(args) == 0 {
+		args = cfg.RunConfigs.SortedNames()
+	}
+
+	s, err := store.Open(cfg.Store)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	// github clients by connection profile
+	clients := make(map[string]*client.MetricsClient)
+	w := tabwriter.NewWriter(c.OutOrStdout(), 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "Board\tRepo\tSince\tUpdated\tDeleted\tPull Requests")
+	for _, name := range args {
+		runCfg, err := cfg.GetRunConfig(name)
+		if err != nil {
+			return err
+		}
+		ghClient, found := clients[runCfg.Profile]
+		if !found {
+			if ghClient, err = newGithubClient(ctx, cfg, runCfg.Profile); err != nil {
+				return err
+			}
+			clients[runCfg.Profile] = ghClient
+		}
+		results, err := s.Sync(ctx, ghClient, runCfg, syncPrune)
+		if err != nil {
+			return err
+		}
+		for _, result := range results {
+			since := "never"
+			if !result.Since.IsZero() {
+				since = result.Since.Local().Format(explainTimeFormat)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%d\n", name, result.Repo, since, result.Updated, result.Deleted, result.PullRequests)
+		}
+	}
+	return w.Flush()
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/3xcellent/github-metrics/apperrors"
+	"github.com/3xcellent/github-metrics/output"
+	"github.com/3xcellent/github-metrics/trend"
+	"github.com/spf13/cobra"
+)
+
+var (
+	trendCmd = &cobra.Command{
+		Use:   "trend [board_name]",
+		Short: "compare the metrics of a github board over consecutive months",
+		Long:  "runs the issues and columns metrics of a github board for --windows consecutive months, ending with the year and month provided (default is current year and month), and outputs a table of throughput, p50 and p85 cycle time, blocked days and average WIP of each column per month, with the change of the last month from the month before; changes unlikely to be chance are flagged significant, except for WIP, which is not tested",
+		RunE:  runTrend,
+		Args:  cobra.ExactArgs(1),
+	}
+	trendFormat  string
+	trendWindows int
+)
+
+func init() {
+	addFormatFlag(trendCmd, &trendFormat, output.Text, output.CSV, output.JSON, output.NDJSON)
+	trendCmd.Flags().IntVar(&trendWindows, "windows", 3, "number of months to compare")
+}
+
+func runTrend(c *cobra.Command, args []string) error {
+	ctx := c.Context()
+
+	format, err := output.ParseFormat(trendFormat, output.Text, output.CSV, output.JSON, output.NDJSON)
+	if err != nil {
+		return err
+	}
+	if trendWindows < 2 {
+		return apperrors.New(apperrors.Config, "--windows must be at least 2")
+	}
+
+	client, runCfg, closeClient, err := SetupCLI(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	defer closeClient()
+
+	samples, err := trend.Run(ctx, client, runCfg, trend.Months(runCfg.StartDate, runCfg.EndDate, trendWindows))
+	if err != nil {
+		return err
+	}
+	t := trend.Compare(samples)
+	return writeResult(c, runCfg.CreateFile, fmt.Sprintf("%s_trend_%d-%02d.csv", strings.Replace(runCfg.Name, " ", "_", -1), runCfg.StartDate.Year(), runCfg.StartDate.Month()), format, output.Result{
+		Rows:    t.Values(),
+		Records: t,
+	})
+}
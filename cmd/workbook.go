@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"context"
+	"io"
+
+	"github.com/3xcellent/github-metrics/client"
+	"github.com/3xcellent/github-metrics/config"
+	"github.com/3xcellent/github-metrics/metrics"
+	"github.com/3xcellent/github-metrics/metrics/runners"
+	"github.com/3xcellent/github-metrics/output"
+	"github.com/3xcellent/github-metrics/report"
+	"github.com/spf13/cobra"
+)
+
+// writeWorkbook - writes the issues, columns, pull requests and summary of a run to a single xlsx workbook;
+// the issues or columns runner not provided is run first
+func writeWorkbook(c *cobra.Command, metricsClient runners.Client, runCfg config.RunConfig, issuesRunner *runners.IssuesRunner, columnsRunner *runners.ColumnsRunner) error {
+	ctx := c.Context()
+	if issuesRunner == nil {
+		runCfg.MetricName = "issues"
+		issuesRunner = runners.NewIssuesRunner(runCfg, metricsClient)
+		if err := issuesRunner.Run(ctx); err != nil {
+			return err
+		}
+	}
+	if columnsRunner == nil {
+		runCfg.MetricName = "columns"
+		columnsRunner = runners.NewColumnsRunner(runCfg, metricsClient)
+		if err := columnsRunner.Run(ctx); err != nil {
+			return err
+		}
+	}
+
+	prs, err := closedPullRequests(ctx, metricsClient, issuesRunner.Runner)
+	if err != nil {
+		return err
+	}
+	wb := report.NewWorkbook(runCfg, issuesRunner, columnsRunner, prs)
+
+	workbookRunner := *issuesRunner.Runner
+	workbookRunner.MetricName = "metrics"
+	return writeOutput(c, runCfg.CreateFile, withExtension(workbookRunner.RunName(), output.XLSX.Ext()), func(w io.Writer) error {
+		return wb.Write(w)
+	})
+}
+
+// closedPullRequests - returns the pull requests of the board's repos closed within the run dates
+func closedPullRequests(ctx context.Context, metricsClient runners.Client, r *runners.Runner) ([]metrics.PullRequestResult, error) {
+	repos, err := metricsClient.GetReposFromProjectColumn(ctx, r.EndColumnID)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]metrics.PullRequestResult, 0)
+	for _, repo := range repos {
+		prs, err := metricsClient.GetPullRequests(ctx, r.Owner, repo.Name)
+		if err != nil {
+			return nil, err
+		}
+		for _, pr := range prs {
+			if pr.ClosedAt.Before(r.StartDate) || !pr.ClosedAt.Before(r.EndDate) {
+				continue
+			}
+			_, prRepoName, issueNumber, err := client.ParseIssueURL(pr.IssueURL)
+			if err != nil {
+				return nil, err
+			}
+			group := ""
+			if Config != nil {
+				group = Config.CreatedByGroup(pr.CreatedByUser)
+			}
+			results = append(results, metrics.NewPullRequestResult(pr, prRepoName, issueNumber, group))
+		}
+	}
+	return results, nil
+}
@@ -1,9 +1,102 @@
 package config
 
+import (
+	"os"
+
+	"github.com/3xcellent/github-metrics/apperrors"
+)
+
 // APIConfig - connection settings for github servers
 type APIConfig struct {
 	Token     string
 	Owner     string
 	BaseURL   string
 	UploadURL string
+
+	// Credentials - sources the token is read from when not set, tried in order: keyring, command (TokenCommand),
+	// file (TokenFile) and git (credential helpers); command and file when blank and their settings are set
+	Credentials  []string
+	TokenCommand string
+	TokenFile    string
+}
+
+// Profile - named connection settings, e.g. for github.com and an enterprise server; a RunConfig's Profile is the
+// one its metrics are run with
+type Profile struct {
+	Name string
+	// Token - the api token, or read from the environment variable (or .env key) TokenEnv when blank
+	Token     string
+	TokenEnv  string
+	Owner     string
+	BaseURL   string
+	UploadURL string
+
+	// Credentials, TokenCommand and TokenFile - sources of the token when not set, see APIConfig
+	Credentials  []string
+	TokenCommand string
+	TokenFile    string
+}
+
+// GetProfile - finds Profile by its name, or err if not found
+func (c *AppConfig) GetProfile(name string) (Profile, error) {
+	for _, profile := range c.Profiles {
+		if profile.Name == name {
+			return profile, nil
+		}
+	}
+	return Profile{}, apperrors.Errorf(apperrors.Config, "no connection profile named %q", name)
+}
+
+// ProfileNames - returns the names of the profiles, in the order of the config
+func (c *AppConfig) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for _, profile := range c.Profiles {
+		names = append(names, profile.Name)
+	}
+	return names
+}
+
+// APIConfig - returns the connection settings of the named profile, of the selected Profile when blank, and API
+// when no profile is named or selected
+func (c *AppConfig) APIConfig(profileName string) (APIConfig, error) {
+	if profileName == "" {
+		profileName = c.Profile
+	}
+	if profileName == "" {
+		return c.API, nil
+	}
+	profile, err := c.GetProfile(profileName)
+	if err != nil {
+		return APIConfig{}, err
+	}
+
+	api := APIConfig{
+		Token:     profile.Token,
+		Owner:     profile.Owner,
+		BaseURL:   profile.BaseURL,
+		UploadURL: profile.UploadURL,
+
+		Credentials:  profile.Credentials,
+		TokenCommand: profile.TokenCommand,
+		TokenFile:    profile.TokenFile,
+	}
+	if api.Token == "" && profile.TokenEnv != "" {
+		api.Token = os.Getenv(profile.TokenEnv)
+	}
+	return api, nil
+}
+
+// ProfileOwner - returns the Owner of the named profile, of the selected Profile when blank, or the Owner of the
+// config when the profile has none
+func (c *AppConfig) ProfileOwner(profileName string) string {
+	if profileName == "" {
+		profileName = c.Profile
+	}
+	if profileName == "" {
+		return c.Owner
+	}
+	if profile, err := c.GetProfile(profileName); err == nil && profile.Owner != "" {
+		return profile.Owner
+	}
+	return c.Owner
 }
@@ -0,0 +1,75 @@
+package config_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/3xcellent/github-metrics/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppConfig_APIConfig(t *testing.T) {
+	cfg, err := config.Parse([]byte(`
+API:
+  token: public-token
+  owner: me
+Owner: me
+Profiles:
+  - name: enterprise
+    tokenEnv: TEST_ENTERPRISE_TOKEN
+    owner: corp
+    baseURL: https://github.example.com/api/v3/
+RunConfigs:
+  - name: Public
+    projectID: 1
+  - name: Internal
+    profile: enterprise
+    projectID: 2
+`))
+	require.NoError(t, err)
+	os.Setenv("TEST_ENTERPRISE_TOKEN", "enterprise-token")
+	defer os.Unsetenv("TEST_ENTERPRISE_TOKEN")
+
+	t.Run("returns API when no profile is named or selected", func(t *testing.T) {
+		api, err := cfg.APIConfig("")
+		require.NoError(t, err)
+		assert.Equal(t, config.APIConfig{Token: "public-token", Owner: "me"}, api)
+	})
+
+	t.Run("returns the named profile with its token read from its environment variable", func(t *testing.T) {
+		api, err := cfg.APIConfig("enterprise")
+		require.NoError(t, err)
+		assert.Equal(t, config.APIConfig{Token: "enterprise-token", Owner: "corp", BaseURL: "https://github.example.com/api/v3/"}, api)
+	})
+
+	t.Run("errors for an unknown profile", func(t *testing.T) {
+		_, err := cfg.APIConfig("missing")
+		assert.Error(t, err)
+	})
+
+	t.Run("run configs default to the owner of their profile", func(t *testing.T) {
+		runCfg, err := cfg.GetRunConfig("Internal")
+		require.NoError(t, err)
+		assert.Equal(t, "enterprise", runCfg.Profile)
+		assert.Equal(t, "corp", runCfg.Owner)
+
+		runCfg, err = cfg.GetRunConfig("Public")
+		require.NoError(t, err)
+		assert.Equal(t, "", runCfg.Profile)
+		assert.Equal(t, "me", runCfg.Owner)
+	})
+
+	t.Run("the selected profile applies to run configs without one", func(t *testing.T) {
+		selected := *cfg
+		selected.Profile = "enterprise"
+		api, err := selected.APIConfig("")
+		require.NoError(t, err)
+		assert.Equal(t, "corp", api.Owner)
+
+		runCfg, err := selected.GetRunConfig("Public")
+		require.NoError(t, err)
+		assert.Equal(t, "enterprise", runCfg.Profile)
+		assert.Equal(t, "corp", runCfg.Owner)
+	})
+}
@@ -8,12 +8,16 @@ import (
 	"strings"
 	"time"
 
+	"github.com/3xcellent/github-metrics/apperrors"
+	"github.com/3xcellent/github-metrics/logging"
 	"github.com/joho/godotenv"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 )
 
+var logger = logging.For("config")
+
 // AppConfig - the Config used by the CLI and GUI apps
 type AppConfig struct {
 	API         APIConfig
@@ -32,9 +36,34 @@ type AppConfig struct {
 	StartDate   time.Time
 	EndDate     time.Time
 	Verbose     bool
-	Timezone    *time.Location
-	LoginNames  []string
-	GroupName   string
+	// LogFormat - text or json; LogLevel - the level of every package optionally followed by package=level pairs,
+	// e.g. info,client=debug
+	LogFormat  string
+	LogLevel   string
+	Timezone   *time.Location
+	LoginNames []string
+	GroupName  string
+
+	// ReportTemplate - default RunConfig.ReportTemplate
+	ReportTemplate string
+
+	// Store - path of the sqlite store kept up to date by the sync command; when set, metrics are run from it
+	Store string
+
+	// Serve - settings of the serve command
+	Serve ServeConfig
+
+	// Schedules - runs of the schedule command; ScheduleHistory is the path of the history of scheduled runs
+	Schedules       []Schedule
+	ScheduleHistory string
+
+	// Profiles - named connection settings used instead of API; Profile selects the one used by commands and
+	// RunConfigs without a profile, set with --profile
+	Profiles []Profile
+	Profile  string
+
+	// SLOs - rules checked by the check command
+	SLOs []SLO
 }
 
 func (c *AppConfig) CreatedByGroup(name string) string {
@@ -55,7 +84,7 @@ func newConfigFromEnv() (*AppConfig, error) {
 			return nil, errors.Wrap(err, "Error loading .env file")
 		}
 	}
-	logrus.Debugf(".env file loaded: %v", loadedEnvFile)
+	logger.Debugf(".env file loaded: %v", loadedEnvFile)
 
 	viper.SetEnvPrefix("gh_metrics")
 	viper.BindEnv("api.token")
@@ -71,7 +100,7 @@ func newConfigFromEnv() (*AppConfig, error) {
 	if err = viper.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
 			// Config file not found; ignore error if desired
-			logrus.Debug("no config file found")
+			logger.Debug("no config file found")
 		} else {
 			// Config file was found but another error was produced
 			return nil, errors.Wrap(err, "error loading config file")
@@ -85,6 +114,16 @@ func newConfigFromEnv() (*AppConfig, error) {
 	return &cfg, nil
 }
 
+// Token - returns the api token of the --token flag or GH_METRICS_API_TOKEN, read from the environment or .env
+func Token() (string, error) {
+	if err := godotenv.Load(); err != nil && !os.IsNotExist(err) {
+		return "", errors.Wrap(err, "Error loading .env file")
+	}
+	viper.SetEnvPrefix("gh_metrics")
+	viper.BindEnv("api.token")
+	return viper.GetString("api.token"), nil
+}
+
 // NewStaticConfig - returns a config defined with the provided yaml ([]byte)
 func NewStaticConfig(config []byte) (*AppConfig, error) {
 	var cfg AppConfig
@@ -103,21 +142,37 @@ func NewStaticConfig(config []byte) (*AppConfig, error) {
 	return &cfg, err
 }
 
+// Parse - returns the config defined by the yaml, without the environment, flags or run dates
+func Parse(data []byte) (*AppConfig, error) {
+	v := viper.New()
+	v.SetConfigType("yaml")
+	if err := v.ReadConfig(bytes.NewBuffer(data)); err != nil {
+		return nil, errors.Wrap(err, "error loading config")
+	}
+	var cfg AppConfig
+	if err := v.Unmarshal(&cfg); err != nil {
+		return nil, errors.Wrap(err, "unable to decode into struct")
+	}
+	return &cfg, nil
+}
+
 // NewDefaultConfig - returns a config from current environment settings
 func NewDefaultConfig() (*AppConfig, error) {
 	cfg, err := newConfigFromEnv()
 	if err != nil {
-		return nil, errors.Wrap(err, "error initializing default config")
+		return nil, apperrors.Wrap(apperrors.Config, err, "error initializing default config")
 	}
 	err = cfg.init()
 	return cfg, err
 }
 
 func (c *AppConfig) init() error {
+	level := c.LogLevel
 	if c.Verbose {
-		logrus.SetLevel(logrus.DebugLevel)
-	} else {
-		logrus.SetLevel(logrus.InfoLevel)
+		level += ",debug"
+	}
+	if err := logging.Setup(logging.Options{Format: c.LogFormat, Level: level}); err != nil {
+		return apperrors.Wrap(apperrors.Config, err, "")
 	}
 
 	year := viper.GetInt("year")
@@ -152,15 +207,22 @@ func (c *AppConfig) init() error {
 	c.StartDate = time.Date(year, time.Month(month), 1, 0, 0, 0, 0, c.Timezone)
 	c.EndDate = c.StartDate.AddDate(0, 1, 0)
 	if c.StartDate.After(time.Now()) {
-		panic("begin date cannot be in the future")
+		return apperrors.Errorf(apperrors.Config, "begin date %s cannot be in the future", c.StartDate.Format("2006-01"))
 	}
 	if c.EndDate.After(time.Now()) {
 		now := time.Now()
 		c.EndDate = time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, c.Timezone)
 	}
 
-	logrus.Debugf("Retrieving cards in range: %s - %s", c.StartDate.String(), c.EndDate.String())
-	logrus.Debugf("Github Metrics Config:\n%#v\n", c)
+	logger.WithFields(logrus.Fields{
+		"start":      c.StartDate,
+		"end":        c.EndDate,
+		"owner":      c.Owner,
+		"profile":    c.Profile,
+		"runConfigs": c.RunConfigs.SortedNames(),
+		"store":      c.Store,
+		"baseURL":    c.API.BaseURL,
+	}).Debug("config loaded")
 
 	return nil
 }
@@ -168,14 +230,17 @@ func (c *AppConfig) init() error {
 // GetRunConfig - finds RunConfig by its name, or err if not found
 func (c *AppConfig) GetRunConfig(name string) (RunConfig, error) {
 	if len(c.RunConfigs) == 0 {
-		return RunConfig{}, errors.New("no project run configs configured")
+		return RunConfig{}, apperrors.New(apperrors.Config, "no project run configs configured")
 	}
 
 	for _, runConfig := range c.RunConfigs {
 		if runConfig.Name == name {
 			rc := runConfig
+			if rc.Profile == "" {
+				rc.Profile = c.Profile
+			}
 			if rc.Owner == "" {
-				rc.Owner = c.Owner
+				rc.Owner = c.ProfileOwner(rc.Profile)
 			}
 			if rc.StartColumn == "" {
 				rc.StartColumn = c.StartColumn
@@ -183,6 +248,9 @@ func (c *AppConfig) GetRunConfig(name string) (RunConfig, error) {
 			if rc.EndColumn == "" {
 				rc.EndColumn = c.EndColumn
 			}
+			if rc.ReportTemplate == "" {
+				rc.ReportTemplate = c.ReportTemplate
+			}
 
 			rc.RepoName = c.RepoName
 			rc.IssueNumber = c.IssueNumber
@@ -195,6 +263,6 @@ func (c *AppConfig) GetRunConfig(name string) (RunConfig, error) {
 		}
 	}
 
-	return RunConfig{}, errors.New("no run configs found with that name")
+	return RunConfig{}, apperrors.Errorf(apperrors.Config, "no run configs found with that name: %q, run configs are: %s", name, strings.Join(c.RunConfigs.SortedNames(), ", "))
 
 }
@@ -19,6 +19,17 @@ type RunConfig struct {
 	StartDate   time.Time
 	EndColumn   string
 	EndDate     time.Time
+	Annotations []Annotation
+
+	// ColumnAliases and Stages map board column names to the logical columns reported
+	ColumnAliases []ColumnAlias
+	Stages        []Stage
+
+	// ReportTemplate - path to an html/template file used instead of the default html report
+	ReportTemplate string
+
+	// Profile - name of the connection profile the metrics are run with, the selected profile of the config when blank
+	Profile string
 }
 
 // RunConfigs - provides access to getting a RunCofnig by ID or Name
@@ -33,3 +44,21 @@ func (runConfigs RunConfigs) SortedNames() []string {
 	sort.Strings(names)
 	return names
 }
+
+// Annotation - a labeled date marked on charts, Date is formatted as 2006-01-02
+type Annotation struct {
+	Date  string
+	Label string
+}
+
+// ColumnAlias - previous names of the column Name, e.g. after a column is renamed
+type ColumnAlias struct {
+	Name    string
+	Aliases []string
+}
+
+// Stage - a logical column Name that several board Columns are counted in
+type Stage struct {
+	Name    string
+	Columns []string
+}
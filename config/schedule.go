@@ -0,0 +1,38 @@
+package config
+
+import "time"
+
+// schedule windows
+const (
+	// WindowPreviousMonth - the calendar month before the scheduled run, the default
+	WindowPreviousMonth = "previousMonth"
+	// WindowMonthToDate - the calendar month of the scheduled run, up to and including its day
+	WindowMonthToDate = "monthToDate"
+)
+
+// DefaultFilename - file name template of the outputs of the run and schedule commands, matching the names of --create-file
+const DefaultFilename = "{{.Board}}_{{.Metric}}_{{.Year}}-{{.Month}}.{{.Ext}}"
+
+// DefaultScheduleHistory - path of the history of scheduled runs
+const DefaultScheduleHistory = "schedule_history.json"
+
+// Schedule - metrics of RunConfigs to run, and write to Destination, when Cron is due
+type Schedule struct {
+	Name string
+	// Cron - 5 field cron expression (minute hour day-of-month month day-of-week) or @daily, @weekly, @monthly
+	Cron       string
+	RunConfigs []string
+	// Metrics - issues, columns or quality
+	Metrics []string
+	// Format - csv (default), json or ndjson
+	Format string
+	// Window - the dates each run covers: previousMonth (default) or monthToDate
+	Window string
+	// Destination - directory the files are written to, the working directory when blank
+	Destination string
+	// Filename - text/template of each file name, with .Board, .Metric, .Year, .Month, .Date and .Ext
+	Filename string
+	// Retries - number of times a failed run is retried
+	Retries    int
+	RetryDelay time.Duration
+}
@@ -0,0 +1,36 @@
+package config
+
+import "time"
+
+// serve defaults
+const (
+	DefaultServeAddress  = ":9090"
+	DefaultServeInterval = 15 * time.Minute
+	DefaultServeDays     = 30
+)
+
+// ServeConfig - settings of the serve command
+type ServeConfig struct {
+	// Address - host:port to listen on, defaults to :9090
+	Address string
+	// Interval - how often the RunConfigs are run, e.g. 15m
+	Interval time.Duration
+	// Days - number of days, up to and including today, each run covers
+	Days int
+	// RunConfigs - names of the RunConfigs to serve, all when empty
+	RunConfigs []string
+}
+
+// WithDefaults - returns the ServeConfig with defaults set for blank settings
+func (c ServeConfig) WithDefaults() ServeConfig {
+	if c.Address == "" {
+		c.Address = DefaultServeAddress
+	}
+	if c.Interval <= 0 {
+		c.Interval = DefaultServeInterval
+	}
+	if c.Days <= 0 {
+		c.Days = DefaultServeDays
+	}
+	return c
+}
@@ -0,0 +1,43 @@
+package config
+
+// SLO measures
+const (
+	// MeasureCycleTime - days of the completed issues from the start to the end column
+	MeasureCycleTime = "cycleTime"
+	// MeasureLeadTime - days of the completed issues from their creation to the end column
+	MeasureLeadTime = "leadTime"
+	// MeasureTimeToStart - days of the completed issues from their creation to the start column
+	MeasureTimeToStart = "timeToStart"
+	// MeasureBlockedDays - days the completed issues were blocked
+	MeasureBlockedDays = "blockedDays"
+	// MeasureThroughput - number of issues completed
+	MeasureThroughput = "throughput"
+	// MeasureWIP - number of issues in Column each day
+	MeasureWIP = "wip"
+	// MeasurePROpen - number of pull requests of the board's repos open at the end of the window for more than
+	// OlderThanDays
+	MeasurePROpen = "prsOpen"
+)
+
+// SLO - a rule a measure of the metrics of RunConfigs must meet, e.g. the p85 cycleTime of Bugs < 5 business days,
+// checked by the check command
+type SLO struct {
+	Name string
+	// RunConfigs - the boards the rule is checked for, every board checked when blank
+	RunConfigs []string
+	// Measure - cycleTime, leadTime, timeToStart, blockedDays, throughput, wip or prsOpen
+	Measure string
+	// Stat - of the values of the measure: count, mean, min, p50, p85, p95 or max; default p85, and max for wip
+	Stat string
+	// Type - only issues of the type, e.g. Bug, Tech Debt or Enhancement; every issue when blank
+	Type string
+	// Column - of the wip measure
+	Column string
+	// OlderThanDays - of the prsOpen measure
+	OlderThanDays float64
+	// BusinessDays - days of the measure count weekdays only
+	BusinessDays bool
+	// Op - <, <=, >, >=, == or !=
+	Op    string
+	Value float64
+}
@@ -0,0 +1,189 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// flagKeys - settings read from the config by flag name rather than into AppConfig
+var flagKeys = map[string]bool{"year": true, "month": true, "askfordate": true}
+
+// Problem - an error in a config, at the path of the setting, e.g. RunConfigs[1].startColumn
+type Problem struct {
+	Path    string
+	Message string
+}
+
+func (p Problem) String() string {
+	if p.Path == "" {
+		return p.Message
+	}
+	return p.Path + ": " + p.Message
+}
+
+// Validate - returns the problems of the config yaml: invalid yaml, keys that are not settings, values of the
+// wrong type, profiles and run configs without a name or with a duplicate name, run configs without a projectID, an
+// owner or a start and end column, and profiles that are not defined
+func Validate(data []byte) []Problem {
+	var raw interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return []Problem{{Message: err.Error()}}
+	}
+
+	problems := unknownKeys("", raw, reflect.TypeOf(AppConfig{}))
+	cfg, err := Parse(data)
+	if err != nil {
+		return append(problems, Problem{Message: err.Error()})
+	}
+
+	profiles := make(map[string]int, len(cfg.Profiles))
+	for idx, profile := range cfg.Profiles {
+		path := fmt.Sprintf("Profiles[%d].name", idx)
+		if profile.Name == "" {
+			problems = append(problems, Problem{Path: path, Message: "required"})
+		} else if first, found := profiles[profile.Name]; found {
+			problems = append(problems, Problem{Path: path, Message: fmt.Sprintf("%q is also the name of Profiles[%d]", profile.Name, first)})
+		} else {
+			profiles[profile.Name] = idx
+		}
+	}
+	if _, found := profiles[cfg.Profile]; cfg.Profile != "" && !found {
+		problems = append(problems, Problem{Path: "Profile", Message: fmt.Sprintf("no profile named %q", cfg.Profile)})
+	}
+
+	names := make(map[string]int, len(cfg.RunConfigs))
+	for idx, runCfg := range cfg.RunConfigs {
+		path := fmt.Sprintf("RunConfigs[%d]", idx)
+		if runCfg.Name == "" {
+			problems = append(problems, Problem{Path: path + ".name", Message: "required"})
+		} else if first, found := names[runCfg.Name]; found {
+			problems = append(problems, Problem{Path: path + ".name", Message: fmt.Sprintf("%q is also the name of RunConfigs[%d]", runCfg.Name, first)})
+		} else {
+			names[runCfg.Name] = idx
+		}
+		if runCfg.ProjectID == 0 {
+			problems = append(problems, Problem{Path: path + ".projectID", Message: "required"})
+		}
+		if _, found := profiles[runCfg.Profile]; runCfg.Profile != "" && !found {
+			problems = append(problems, Problem{Path: path + ".profile", Message: fmt.Sprintf("no profile named %q", runCfg.Profile)})
+		}
+		if runCfg.Owner == "" && cfg.ProfileOwner(runCfg.Profile) == "" {
+			problems = append(problems, Problem{Path: path + ".owner", Message: "required, or set the owner of its profile or the top level Owner"})
+		}
+		if runCfg.StartColumn == "" && cfg.StartColumn == "" {
+			problems = append(problems, Problem{Path: path + ".startColumn", Message: "required, or set the top level StartColumn"})
+		}
+		if runCfg.EndColumn == "" && cfg.EndColumn == "" {
+			problems = append(problems, Problem{Path: path + ".endColumn", Message: "required, or set the top level EndColumn"})
+		}
+	}
+	return problems
+}
+
+// unknownKeys - returns a problem for each key of value that is not a field of t, matched case insensitively
+// like the config is decoded
+func unknownKeys(path string, value interface{}, t reflect.Type) []Problem {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	problems := make([]Problem, 0)
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		if t.Kind() == reflect.Map {
+			for key, item := range v {
+				problems = append(problems, unknownKeys(joinPath(path, fmt.Sprint(key)), item, t.Elem())...)
+			}
+			break
+		}
+		if t.Kind() != reflect.Struct || t == reflect.TypeOf(time.Time{}) {
+			break
+		}
+		fields := make(map[string]reflect.StructField, t.NumField())
+		names := make([]string, 0, t.NumField())
+		for idx := 0; idx < t.NumField(); idx++ {
+			field := t.Field(idx)
+			fields[strings.ToLower(field.Name)] = field
+			names = append(names, field.Name)
+		}
+
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, fmt.Sprint(key))
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			field, found := fields[strings.ToLower(key)]
+			if !found {
+				if path == "" && flagKeys[strings.ToLower(key)] {
+					continue
+				}
+				problems = append(problems, Problem{Path: joinPath(path, key), Message: "unknown setting" + suggestion(key, names)})
+				continue
+			}
+			problems = append(problems, unknownKeys(joinPath(path, key), v[key], field.Type)...)
+		}
+	case []interface{}:
+		if t.Kind() != reflect.Slice {
+			break
+		}
+		for idx, item := range v {
+			problems = append(problems, unknownKeys(fmt.Sprintf("%s[%d]", path, idx), item, t.Elem())...)
+		}
+	}
+	return problems
+}
+
+// suggestion - returns the setting the key was likely meant to be, differing by case, a character, or a plural
+func suggestion(key string, names []string) string {
+	lower := strings.ToLower(key)
+	for _, name := range names {
+		candidate := strings.ToLower(name)
+		if strings.TrimSuffix(lower, "s") == strings.TrimSuffix(candidate, "s") || editDistance(lower, candidate) == 1 {
+			return fmt.Sprintf(", did you mean %s?", name)
+		}
+	}
+	return ""
+}
+
+// editDistance - returns the number of single character edits between a and b
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
+
+func min(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,115 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/3xcellent/github-metrics/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	t.Run("accepts a valid config", func(t *testing.T) {
+		problems := config.Validate([]byte(`
+Owner: me
+year: 2020
+RunConfigs:
+  - name: Board
+    projectID: 1
+    startColumn: Doing
+    endColumn: Done
+    columnAliases:
+      - name: Doing
+        aliases: [In progress]
+Serve:
+  interval: 5m
+`))
+		assert.Empty(t, problems)
+	})
+
+	t.Run("reports unknown settings with suggestions", func(t *testing.T) {
+		problems := config.Validate([]byte(`
+Owner: me
+IncludeHeaders: true
+RunConfigs:
+  - name: Board
+    projectID: 1
+    startColum: Doing
+    endColumn: Done
+`))
+		assert.Equal(t, []config.Problem{
+			{Path: "IncludeHeaders", Message: "unknown setting"},
+			{Path: "RunConfigs[0].startColum", Message: "unknown setting, did you mean StartColumn?"},
+			{Path: "RunConfigs[0].startColumn", Message: "required, or set the top level StartColumn"},
+		}, problems)
+	})
+
+	t.Run("reports missing and duplicate run config settings", func(t *testing.T) {
+		problems := config.Validate([]byte(`
+StartColumn: Doing
+EndColumn: Done
+RunConfigs:
+  - name: Board
+    owner: me
+    projectID: 1
+  - name: Board
+    owner: me
+    projectID: 2
+  - owner: me
+    projectID: 3
+  - name: Other
+`))
+		assert.Equal(t, []config.Problem{
+			{Path: "RunConfigs[1].name", Message: `"Board" is also the name of RunConfigs[0]`},
+			{Path: "RunConfigs[2].name", Message: "required"},
+			{Path: "RunConfigs[3].projectID", Message: "required"},
+			{Path: "RunConfigs[3].owner", Message: "required, or set the owner of its profile or the top level Owner"},
+		}, problems)
+	})
+
+	t.Run("reports undefined and duplicate profiles", func(t *testing.T) {
+		problems := config.Validate([]byte(`
+Profile: public
+StartColumn: Doing
+EndColumn: Done
+Profiles:
+  - name: enterprise
+    owner: corp
+    baseURL: https://github.example.com/api/v3/
+  - name: enterprise
+RunConfigs:
+  - name: Internal
+    profile: enterprise
+    projectID: 1
+  - name: Other
+    profile: missing
+    projectID: 2
+`))
+		assert.Equal(t, []config.Problem{
+			{Path: "Profiles[1].name", Message: `"enterprise" is also the name of Profiles[0]`},
+			{Path: "Profile", Message: `no profile named "public"`},
+			{Path: "RunConfigs[1].profile", Message: `no profile named "missing"`},
+			{Path: "RunConfigs[1].owner", Message: "required, or set the owner of its profile or the top level Owner"},
+		}, problems)
+	})
+
+	t.Run("reports run configs without a start or end column", func(t *testing.T) {
+		problems := config.Validate([]byte(`
+Owner: me
+EndColumn: Done
+RunConfigs:
+  - name: Board
+    projectID: 1
+  - name: Started
+    projectID: 2
+    startColumn: Doing
+`))
+		assert.Equal(t, []config.Problem{
+			{Path: "RunConfigs[0].startColumn", Message: "required, or set the top level StartColumn"},
+		}, problems)
+	})
+
+	t.Run("reports invalid yaml and values of the wrong type", func(t *testing.T) {
+		assert.Len(t, config.Validate([]byte("RunConfigs: [")), 1)
+		assert.Len(t, config.Validate([]byte("StartColumn: Doing\nEndColumn: Done\nRunConfigs:\n  - name: Board\n    owner: me\n    projectID: abc\n")), 1)
+	})
+}
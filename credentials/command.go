@@ -0,0 +1,37 @@
+package credentials
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// CommandProvider - a Provider returning the first line of the output of a shell command, e.g. pass show github/metrics
+type CommandProvider string
+
+// Token - runs the command and returns its first line; the host is set in GH_METRICS_HOST
+func (c CommandProvider) Token(ctx context.Context, host string) (string, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", string(c))
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", string(c))
+	}
+	cmd.Env = append(os.Environ(), "GH_METRICS_HOST="+host)
+	stderr := new(bytes.Buffer)
+	cmd.Stderr = stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("%s: %v %s", c, err, strings.TrimSpace(stderr.String()))
+	}
+	token := strings.TrimSpace(strings.SplitN(string(out), "\n", 2)[0])
+	if token == "" {
+		return "", ErrNotFound
+	}
+	return token, nil
+}
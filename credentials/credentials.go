@@ -0,0 +1,128 @@
+// Package credentials reads the api token from the sources configured, tried in order: the os keyring, the output of
+// a command, a file only its owner can read, and git's credential helpers
+package credentials
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/3xcellent/github-metrics/apperrors"
+	"github.com/3xcellent/github-metrics/config"
+	"github.com/3xcellent/github-metrics/logging"
+)
+
+var logger = logging.For("credentials")
+
+// names of the providers, as listed in the Credentials of the api settings
+const (
+	Keyring = "keyring"
+	Command = "command"
+	File    = "file"
+	Git     = "git"
+)
+
+// ErrNotFound - returned by a Provider without a token for the host
+var ErrNotFound = errors.New("no token found")
+
+// Provider - a source of api tokens
+type Provider interface {
+	// Token - returns the token of the api host, ErrNotFound when it has none
+	Token(ctx context.Context, host string) (string, error)
+}
+
+// ProviderFunc - a func that is a Provider
+type ProviderFunc func(ctx context.Context, host string) (string, error)
+
+// Token - returns f(ctx, host)
+func (f ProviderFunc) Token(ctx context.Context, host string) (string, error) {
+	return f(ctx, host)
+}
+
+// Named - a Provider of the Credentials of the api settings
+type Named struct {
+	Name string
+	Provider
+}
+
+// Host - returns the host of the api at baseURL, github.com when blank
+func Host(baseURL string) string {
+	if baseURL == "" {
+		return "github.com"
+	}
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Host == "" {
+		return baseURL
+	}
+	return u.Hostname()
+}
+
+// Providers - returns the providers of the api's Credentials, in order; when none are listed, the command and file
+// providers of its TokenCommand and TokenFile when set
+func Providers(api config.APIConfig) ([]Named, error) {
+	names := api.Credentials
+	if len(names) == 0 {
+		if api.TokenCommand != "" {
+			names = append(names, Command)
+		}
+		if api.TokenFile != "" {
+			names = append(names, File)
+		}
+	}
+
+	providers := make([]Named, 0, len(names))
+	for _, name := range names {
+		var provider Provider
+		switch strings.ToLower(name) {
+		case Keyring:
+			provider = ProviderFunc(KeyringToken)
+		case Command:
+			if api.TokenCommand == "" {
+				return nil, apperrors.New(apperrors.Config, "command credentials listed without a tokenCommand")
+			}
+			provider = CommandProvider(api.TokenCommand)
+		case File:
+			if api.TokenFile == "" {
+				return nil, apperrors.New(apperrors.Config, "file credentials listed without a tokenFile")
+			}
+			provider = FileProvider(api.TokenFile)
+		case Git:
+			provider = ProviderFunc(GitToken)
+		default:
+			return nil, apperrors.Errorf(apperrors.Config, "unknown credentials %q, use %s, %s, %s or %s", name, Keyring, Command, File, Git)
+		}
+		providers = append(providers, Named{Name: strings.ToLower(name), Provider: provider})
+	}
+	return providers, nil
+}
+
+// Resolve - returns api with the token of the first of its providers that has one, unless it already has a token
+// from the --token flag, the environment or the config
+func Resolve(ctx context.Context, api config.APIConfig) (config.APIConfig, error) {
+	if api.Token != "" {
+		return api, nil
+	}
+	providers, err := Providers(api)
+	if err != nil || len(providers) == 0 {
+		return api, err
+	}
+
+	host := Host(api.BaseURL)
+	tried := make([]string, 0, len(providers))
+	for _, provider := range providers {
+		token, err := provider.Token(ctx, host)
+		if err == ErrNotFound {
+			logger.Debugf("no token for %s in %s", host, provider.Name)
+			tried = append(tried, provider.Name)
+			continue
+		}
+		if err != nil {
+			return api, apperrors.Errorf(apperrors.Auth, "reading token from %s: %w", provider.Name, err)
+		}
+		logger.Debugf("using token for %s from %s", host, provider.Name)
+		api.Token = token
+		return api, nil
+	}
+	return api, apperrors.Errorf(apperrors.Auth, "github access token not set, and none found for %s in %s", host, strings.Join(tried, ", "))
+}
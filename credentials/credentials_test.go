@@ -0,0 +1,93 @@
+package credentials_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/3xcellent/github-metrics/config"
+	"github.com/3xcellent/github-metrics/credentials"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zalando/go-keyring"
+)
+
+func TestResolve(t *testing.T) {
+	ctx := context.Background()
+	keyring.MockInit()
+	dir, err := ioutil.TempDir("", "credentials")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	t.Run("keeps a token already set", func(t *testing.T) {
+		api, err := credentials.Resolve(ctx, config.APIConfig{Token: "flag", TokenCommand: "echo command"})
+		require.NoError(t, err)
+		assert.Equal(t, "flag", api.Token)
+	})
+
+	t.Run("reads the first line of the token command", func(t *testing.T) {
+		api, err := credentials.Resolve(ctx, config.APIConfig{TokenCommand: "printf 'command\\nsecond line'"})
+		require.NoError(t, err)
+		assert.Equal(t, "command", api.Token)
+	})
+
+	t.Run("reads the token file only when other users cannot read it", func(t *testing.T) {
+		path := filepath.Join(dir, "token")
+		require.NoError(t, ioutil.WriteFile(path, []byte("file\n"), 0644))
+		_, err := credentials.Resolve(ctx, config.APIConfig{TokenFile: path})
+		assert.Error(t, err)
+
+		require.NoError(t, os.Chmod(path, 0600))
+		api, err := credentials.Resolve(ctx, config.APIConfig{TokenFile: path})
+		require.NoError(t, err)
+		assert.Equal(t, "file", api.Token)
+	})
+
+	t.Run("tries the credentials in order", func(t *testing.T) {
+		require.NoError(t, credentials.StoreToken("github.example.com", "keyring"))
+		api := config.APIConfig{
+			BaseURL:      "https://github.example.com/api/v3/",
+			Credentials:  []string{"file", "keyring", "command"},
+			TokenFile:    filepath.Join(dir, "missing"),
+			TokenCommand: "echo command",
+		}
+		resolved, err := credentials.Resolve(ctx, api)
+		require.NoError(t, err)
+		assert.Equal(t, "keyring", resolved.Token)
+
+		require.NoError(t, credentials.DeleteToken("github.example.com"))
+		resolved, err = credentials.Resolve(ctx, api)
+		require.NoError(t, err)
+		assert.Equal(t, "command", resolved.Token)
+	})
+
+	t.Run("asks git's credential helpers for the host", func(t *testing.T) {
+		git := filepath.Join(dir, "git")
+		require.NoError(t, ioutil.WriteFile(git, []byte("#!/bin/sh\ncat > /dev/null\necho protocol=https\necho host=github.com\necho password=git\n"), 0700))
+		defer func(command string) { credentials.GitCommand = command }(credentials.GitCommand)
+		credentials.GitCommand = git
+
+		api, err := credentials.Resolve(ctx, config.APIConfig{Credentials: []string{"git"}})
+		require.NoError(t, err)
+		assert.Equal(t, "git", api.Token)
+	})
+
+	t.Run("errors when no source has a token", func(t *testing.T) {
+		_, err := credentials.Resolve(ctx, config.APIConfig{Credentials: []string{"keyring"}})
+		assert.EqualError(t, err, "github access token not set, and none found for github.com in keyring")
+	})
+
+	t.Run("errors for unknown credentials", func(t *testing.T) {
+		_, err := credentials.Resolve(ctx, config.APIConfig{Credentials: []string{"vault"}})
+		assert.Error(t, err)
+		_, err = credentials.Resolve(ctx, config.APIConfig{Credentials: []string{"command"}})
+		assert.Error(t, err)
+	})
+}
+
+func TestHost(t *testing.T) {
+	assert.Equal(t, "github.com", credentials.Host(""))
+	assert.Equal(t, "github.example.com", credentials.Host("https://github.example.com/api/v3/"))
+}
@@ -0,0 +1,48 @@
+package credentials
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// FileProvider - a Provider returning the contents of a file, which must not be readable by other users
+type FileProvider string
+
+// Token - returns the trimmed contents of the file; a leading ~ is the home directory
+func (f FileProvider) Token(ctx context.Context, host string) (string, error) {
+	path := string(f)
+	if strings.HasPrefix(path, "~"+string(filepath.Separator)) || path == "~" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		path = filepath.Join(home, path[1:])
+	}
+
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	// windows has no permission bits to check
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0077 != 0 {
+		return "", fmt.Errorf("%s can be read by other users (mode %s), run chmod 600 %s", path, info.Mode().Perm(), path)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", ErrNotFound
+	}
+	return token, nil
+}
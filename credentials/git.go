@@ -0,0 +1,49 @@
+package credentials
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// GitCommand - the git executable the credential helpers are asked through
+var GitCommand = "git"
+
+// GitToken - returns the password git's credential helpers have for https://host, without prompting; see
+// git help credential
+func GitToken(ctx context.Context, host string) (string, error) {
+	cmd := exec.CommandContext(ctx, GitCommand, "credential", "fill")
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GIT_ASKPASS=", "SSH_ASKPASS=")
+	cmd.Stdin = strings.NewReader(fmt.Sprintf("protocol=https\nhost=%s\n\n", host))
+	stderr := new(bytes.Buffer)
+	cmd.Stderr = stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		// git fails when no helper has the host and it may not prompt
+		logger.Debugf("git credential fill: %v %s", err, strings.TrimSpace(stderr.String()))
+		return "", ErrNotFound
+	}
+	return parseCredential(out)
+}
+
+// parseCredential - returns the password of the key=value lines of git credential fill
+func parseCredential(out []byte) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "password=") {
+			if password := strings.TrimPrefix(line, "password="); password != "" {
+				return password, nil
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", ErrNotFound
+}
@@ -0,0 +1,34 @@
+package credentials
+
+import (
+	"context"
+
+	"github.com/zalando/go-keyring"
+)
+
+// KeyringService - the service tokens are kept under in the os keyring, by api host
+const KeyringService = "github-metrics"
+
+// KeyringToken - returns the token of the host kept in the os keyring: the Secret Service on linux, the keychain on
+// macOS and the credential manager on windows
+func KeyringToken(ctx context.Context, host string) (string, error) {
+	token, err := keyring.Get(KeyringService, host)
+	if err == keyring.ErrNotFound {
+		return "", ErrNotFound
+	}
+	return token, err
+}
+
+// StoreToken - keeps the token of the host in the os keyring
+func StoreToken(host, token string) error {
+	return keyring.Set(KeyringService, host, token)
+}
+
+// DeleteToken - removes the token of the host from the os keyring
+func DeleteToken(host string) error {
+	err := keyring.Delete(KeyringService, host)
+	if err == keyring.ErrNotFound {
+		return nil
+	}
+	return err
+}
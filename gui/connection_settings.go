@@ -5,8 +5,6 @@ import (
 	"gioui.org/widget"
 	"gioui.org/widget/material"
 	"git.sr.ht/~whereswaldon/materials"
-	"github.com/3xcellent/github-metrics/config"
-	"github.com/sirupsen/logrus"
 )
 
 var (
@@ -15,43 +13,102 @@ var (
 	ownerInput                   materials.TextField
 	baseURLInput                 materials.TextField
 	uploadURLInput               materials.TextField
+	profilesEnum                 widget.Enum
+	keyringCheckbox              widget.Bool
 )
 
+// defaultProfile - the key of the API settings of the config among the profiles
+const defaultProfile = "(default)"
+
+func profileKey(profile string) string {
+	if profile == "" {
+		return defaultProfile
+	}
+	return profile
+}
+
+func profileOptions(th *material.Theme, input *widget.Enum, names []string) []layout.FlexChild {
+	options := make([]layout.FlexChild, 0, len(names)+1)
+	options = append(options, layout.Rigid(func(gtx C) D {
+		return material.RadioButton(th, input, defaultProfile, "Default").Layout(gtx)
+	}))
+	for _, name := range names {
+		n := name
+		options = append(options,
+			layout.Rigid(func(gtx C) D {
+				return material.RadioButton(th, input, n, n).Layout(gtx)
+			}))
+	}
+	return options
+}
+
+// setProfileInputs - fills the form with the settings of the profile chosen
+func setProfileInputs(key string) {
+	profile := key
+	if key == defaultProfile {
+		profile = ""
+	}
+	api, err := State.ProfileAPIConfig(profile)
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+	tokenInput.SetText(api.Token)
+	ownerInput.SetText(api.Owner)
+	baseURLInput.SetText(api.BaseURL)
+	uploadURLInput.SetText(api.UploadURL)
+}
+
 // LayoutConnectionSettings - connection settings.
 func LayoutConnectionSettings(gtx C) D {
 	// set initial form values
+	if profilesEnum.Changed() {
+		setProfileInputs(profilesEnum.Value)
+	}
 
 	if connectionSettingsDoneButton.Clicked() {
-		logrus.Debugf("connectionSettingsDoneButton.Clicked()")
-
-		err := State.SetClient(config.APIConfig{
-			Token:     tokenInput.Text(),
-			Owner:     ownerInput.Text(),
-			BaseURL:   baseURLInput.Text(),
-			UploadURL: uploadURLInput.Text(),
-		})
-		if err != nil {
-			panic(err)
-		}
-		State.HasValidatedConnection = true
-
-		logrus.Debugf("updated connection settings")
-		if State.SelectedProjectID == 0 || State.SelectedProjectName == "" {
-			nav.SetNavDestination(ProjectsPage)
+		logger.Debugf("connectionSettingsDoneButton.Clicked()")
+		if err := applyConnectionSettings(); err != nil {
+			// the settings stay on the form to be corrected
+			State.SetError(err)
+		} else {
+			State.ClearError()
+			logger.Debugf("updated connection settings")
+			if State.SelectedProjectID == 0 || State.SelectedProjectName == "" {
+				nav.SetNavDestination(ProjectsPage)
+			}
+			nav.SetNavDestination(RunOptionsPage)
 		}
-		nav.SetNavDestination(RunOptionsPage)
 	}
 	return layout.Flex{
 		Alignment: layout.Middle,
 		Axis:      layout.Vertical,
 	}.Layout(
 		gtx,
+		layout.Rigid(func(gtx C) D {
+			if len(State.Config.Profiles) == 0 {
+				return D{}
+			}
+			return inset.Layout(gtx, func(gtx C) D {
+				return layout.Flex{
+					Axis: layout.Vertical,
+				}.Layout(
+					gtx,
+					append([]layout.FlexChild{
+						layout.Rigid(material.Body2(th, "Profile:").Layout),
+					}, profileOptions(th, &profilesEnum, State.Config.ProfileNames())...)...,
+				)
+			})
+		}),
 		layout.Rigid(func(gtx C) D {
 			tokenInput.Alignment = inputAlignment
 			return tokenInput.Layout(gtx, th, "Token")
 		}),
 		layout.Rigid(func(gtx C) D {
-			return inset.Layout(gtx, material.Body2(th, "Github Personal Access Token").Layout)
+			return inset.Layout(gtx, material.Body2(th, "Github Personal Access Token, blank to read it from the keyring or the credentials of the config").Layout)
+		}),
+		layout.Rigid(func(gtx C) D {
+			return inset.Layout(gtx, material.CheckBox(th, &keyringCheckbox, "Keep the token in the OS keyring").Layout)
 		}),
 		layout.Rigid(func(gtx C) D {
 			ownerInput.Alignment = inputAlignment
@@ -86,3 +143,38 @@ func LayoutConnectionSettings(gtx C) D {
 		}),
 	)
 }
+
+// applyConnectionSettings - sets the client with the settings of the form
+func applyConnectionSettings() error {
+	profile := profilesEnum.Value
+	if profile == defaultProfile {
+		profile = ""
+	}
+	// the credentials settings of the profile are kept, the form only has the token
+	api, err := State.ProfileAPIConfig(profile)
+	if err != nil {
+		return err
+	}
+	api.Token = tokenInput.Text()
+	api.Owner = ownerInput.Text()
+	api.BaseURL = baseURLInput.Text()
+	api.UploadURL = uploadURLInput.Text()
+
+	if keyringCheckbox.Value {
+		err = State.SetClientWithKeyring(api)
+		tokenInput.SetText("")
+	} else {
+		err = State.SetClient(api)
+	}
+	if err != nil {
+		return err
+	}
+	State.HasValidatedConnection = true
+
+	if profile != State.Profile {
+		// projects of the previous connection are listed again for the new one
+		State.Profile = profile
+		hasLoadedProjects = false
+	}
+	return nil
+}
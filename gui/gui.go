@@ -16,11 +16,14 @@ import (
 	"gioui.org/widget"
 	"gioui.org/widget/material"
 	"git.sr.ht/~whereswaldon/materials"
+	"github.com/3xcellent/github-metrics/apperrors"
 	"github.com/3xcellent/github-metrics/config"
+	"github.com/3xcellent/github-metrics/logging"
 	"github.com/3xcellent/github-metrics/models"
-	"github.com/sirupsen/logrus"
 )
 
+var logger = logging.For("gui")
+
 type (
 	// C is the layout.Context
 	C = layout.Context
@@ -119,6 +122,10 @@ var (
 	resultText        string
 	resultTextField   materials.TextField
 
+	// dismissErrorButton - hides the error shown
+	dismissErrorButton widget.Clickable
+	errorColor         = color.NRGBA{R: 0xb0, G: 0x00, B: 0x20, A: 0xff}
+
 	appPages = []Page{
 		{
 			NavItem: materials.NavItem{
@@ -159,32 +166,42 @@ var (
 func Start(ctx context.Context, cfg *config.AppConfig, args []string) error {
 	w := app.NewWindow()
 	// initialize state and set github client
-	State = NewState(ctx)
+	State = NewState(ctx, cfg)
+	State.invalidate = w.Invalidate
 
-	err := State.SetClient(cfg.API)
+	// errors of the run config or connection are shown, to be corrected in the settings, rather than closing the app
+	State.Profile = cfg.Profile
+	if len(args) > 0 {
+		if runConfig, err := cfg.GetRunConfig(args[0]); err != nil {
+			State.SetError(err)
+		} else {
+			State.Profile = runConfig.Profile
+			State.RunConfig = runConfig
+		}
+	}
+	api, err := State.ProfileAPIConfig(State.Profile)
 	if err != nil {
-		return err
+		State.SetError(err)
+	} else if err = State.SetClient(api); err != nil {
+		State.SetError(err)
 	}
 
-	if len(args) > 0 {
-		runConfig, err := cfg.GetRunConfig(args[0])
-		if err != nil {
-			return err
-		}
-		State.SelectedProjectID = runConfig.ProjectID
+	if State.RunConfig.ProjectID != 0 && State.Client != nil {
+		State.SelectedProjectID = State.RunConfig.ProjectID
 		project, err := State.Client.GetProject(ctx, State.SelectedProjectID)
 		if err != nil {
-			return err
+			State.SetError(err)
+		} else {
+			hasLoadedProjects = true
+			project.Owner = State.RunConfig.Owner
+			availableProjects = models.Projects{project}
+			State.SelectedProjectName = project.Name
 		}
-		hasLoadedProjects = true
-		project.Owner = runConfig.Owner
-		availableProjects = models.Projects{project}
-		State.SelectedProjectName = project.Name
-		State.RunConfig = runConfig
 	}
 
 	// initialize form fields
-	logrus.Info("initializing form fields")
+	logger.Info("initializing form fields")
+	profilesEnum.Value = profileKey(State.Profile)
 	tokenInput.SetText(State.APIConfig.Token)
 	ownerInput.SetText(State.APIConfig.Owner)
 	baseURLInput.SetText(State.APIConfig.BaseURL)
@@ -202,6 +219,10 @@ func Start(ctx context.Context, cfg *config.AppConfig, args []string) error {
 		nav.AddNavItem(page.NavItem)
 	}
 
+	if State.Client == nil {
+		nav.SetNavDestination(ConnectionSettingsPage)
+	}
+
 	// configure app bar initial state
 	page := appPages[nav.CurrentNavDestination().(int)]
 	bar.Title = page.Name
@@ -272,9 +293,36 @@ func layoutApp(gtx C, e system.FrameEvent) D {
 		bar := layout.Rigid(func(gtx layout.Context) layout.Dimensions {
 			return bar.Layout(gtx, th)
 		})
+		errorBanner := layout.Rigid(layoutError)
 		flex := layout.Flex{Axis: layout.Vertical}
-		flex.Layout(gtx, bar, content)
+		flex.Layout(gtx, bar, errorBanner, content)
 		modal.Layout(gtx, th)
 		return layout.Dimensions{Size: gtx.Constraints.Max}
 	})
 }
+
+// layoutError - the error shown, with what to do about it, until dismissed
+func layoutError(gtx C) D {
+	if dismissErrorButton.Clicked() {
+		State.ClearError()
+	}
+	err := State.Err()
+	if err == nil {
+		return D{}
+	}
+	return inset.Layout(gtx, func(gtx C) D {
+		return layout.Flex{
+			Alignment: layout.Middle,
+			Axis:      layout.Horizontal,
+		}.Layout(gtx,
+			layout.Flexed(1, func(gtx C) D {
+				label := material.Body1(th, apperrors.Message(err))
+				label.Color = errorColor
+				return inset.Layout(gtx, label.Layout)
+			}),
+			layout.Rigid(func(gtx C) D {
+				return material.Button(th, &dismissErrorButton, "Dismiss").Layout(gtx)
+			}),
+		)
+	})
+}
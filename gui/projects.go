@@ -11,7 +11,6 @@ import (
 	"gioui.org/widget/material"
 	"github.com/3xcellent/github-metrics/client"
 	"github.com/3xcellent/github-metrics/models"
-	"github.com/sirupsen/logrus"
 )
 
 var (
@@ -58,14 +57,20 @@ func LayoutProjectsPage(gtx C) D {
 		hasLoadedProjects = false
 	}
 
+	if State.Client == nil {
+		return inset.Layout(gtx, material.Body2(th, "Set the Github Connection Settings to load the projects").Layout)
+	}
+
 	if !hasLoadedProjects {
 		if !isLoadingProjects {
 			isLoadingProjects = true
 			go func(client *client.MetricsClient) {
-				logrus.Info("getting projects...")
+				logger.Info("getting projects...")
 				ghProjects, err := client.GetProjects(context.Background(), State.APIConfig.Owner)
 				if err != nil {
-					panic(err)
+					// shown instead of the projects, until they are loaded again
+					State.SetError(err)
+					ghProjects = nil
 				}
 				availableProjects = ghProjects
 				hasLoadedProjects = true
@@ -104,24 +109,16 @@ func LayoutProjectsPage(gtx C) D {
 	}
 
 	if projectsEnum.Changed() {
-		id, err := strconv.Atoi(projectsEnum.Value)
-		if err != nil {
-			panic(err)
-		}
-		State.SelectedProjectID = int64(id)
-
-		project, err := availableProjects.GetProject(State.SelectedProjectID)
-		if err != nil {
-			panic(err)
+		if err := selectProject(projectsEnum.Value); err != nil {
+			State.SetError(err)
+		} else {
+			nav.SetNavDestination(RunOptionsPage)
 		}
-		State.RunConfig.ProjectID = State.SelectedProjectID
-		State.SelectedProjectName = project.Name
-		nav.SetNavDestination(RunOptionsPage)
 		op.InvalidateOp{}.Add(gtx.Ops)
 	}
 
-	logrus.Debugf("SelectedProjectID : %d", State.SelectedProjectID)
-	logrus.Debugf("SelectedProjectName : %s", State.SelectedProjectName)
+	logger.Debugf("SelectedProjectID : %d", State.SelectedProjectID)
+	logger.Debugf("SelectedProjectName : %s", State.SelectedProjectName)
 	return layout.Flex{
 		Alignment: layout.Start,
 		Axis:      layout.Horizontal,
@@ -165,3 +162,19 @@ func LayoutProjectsPage(gtx C) D {
 		}),
 	)
 }
+
+// selectProject - selects the project of the id for the run options
+func selectProject(value string) error {
+	id, err := strconv.Atoi(value)
+	if err != nil {
+		return err
+	}
+	project, err := availableProjects.GetProject(int64(id))
+	if err != nil {
+		return err
+	}
+	State.SelectedProjectID = project.ID
+	State.RunConfig.ProjectID = project.ID
+	State.SelectedProjectName = project.Name
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 
 	"gioui.org/layout"
 	"gioui.org/widget/material"
+	"github.com/3xcellent/github-metrics/apperrors"
 	"github.com/3xcellent/github-metrics/metrics/runners"
 )
 
@@ -15,30 +16,11 @@ func LayoutResults(gtx C) D {
 	// set initial form values
 	if State.RunRequested && !State.RunStarted {
 		State.RunStarted = true
-		selectedProject, err := availableProjects.GetProject(State.SelectedProjectID)
-		if err != nil {
-			panic(err)
-		}
-		State.RunConfig.ProjectID = selectedProject.ID
-		State.RunConfig.Owner = selectedProject.Owner
-		State.RunConfig.StartDate = time.Date(selectedYear, time.Month(selectedMonth), 1, 0, 0, 0, 0, time.Now().Location())
-		State.RunConfig.EndDate = State.RunConfig.StartDate.AddDate(0, 1, 0)
-
-		runner, err := runners.New(State.RunConfig, State.Client)
-		if err != nil {
-			panic(err)
-		}
-
-		doAfter := func(rowValues [][]string) error {
-			State.RunValues = rowValues
-			State.RunCompleted = true
+		if err := startRun(); err != nil {
+			State.SetError(err)
 			State.RunStarted = false
 			State.RunRequested = false
-		
-			return nil
 		}
-		runner.After(doAfter)
-		go runner.Run(context.Background())
 	}
 
 	if State.RunRequested {
@@ -52,6 +34,44 @@ func LayoutResults(gtx C) D {
 	}
 }
 
+// startRun - runs the metric of the run options in the background, the error of the run is shown when it fails
+func startRun() error {
+	if State.Client == nil {
+		return apperrors.New(apperrors.Auth, "not connected to github, set the Github Connection Settings")
+	}
+	selectedProject, err := availableProjects.GetProject(State.SelectedProjectID)
+	if err != nil {
+		return err
+	}
+	State.RunConfig.ProjectID = selectedProject.ID
+	State.RunConfig.Owner = selectedProject.Owner
+	State.RunConfig.StartDate = time.Date(selectedYear, time.Month(selectedMonth), 1, 0, 0, 0, 0, time.Now().Location())
+	State.RunConfig.EndDate = State.RunConfig.StartDate.AddDate(0, 1, 0)
+
+	runner, err := runners.New(State.RunConfig, State.Client)
+	if err != nil {
+		return err
+	}
+
+	doAfter := func(rowValues [][]string) error {
+		State.RunValues = rowValues
+		State.RunCompleted = true
+		State.RunStarted = false
+		State.RunRequested = false
+
+		return nil
+	}
+	runner.After(doAfter)
+	go func() {
+		if err := runner.Run(context.Background()); err != nil {
+			State.RunStarted = false
+			State.RunRequested = false
+			State.SetError(err)
+		}
+	}()
+	return nil
+}
+
 type defaultValues [][]string
 
 func (vals defaultValues) Layout(gtx C) D {
@@ -11,7 +11,6 @@ import (
 	"gioui.org/widget/material"
 	"github.com/3xcellent/github-metrics/config"
 	"github.com/3xcellent/github-metrics/metrics"
-	"github.com/sirupsen/logrus"
 )
 
 func layoutRunOptionsPage(gtx C) D {
@@ -25,7 +24,7 @@ func layoutRunOptionsPage(gtx C) D {
 
 	if runButton.Clicked() {
 		State.RunRequested = true
-		logrus.Debugf("runButton.Clicked() - State.RunRequested:%t", State.RunRequested)
+		logger.Debugf("runButton.Clicked() - State.RunRequested:%t", State.RunRequested)
 		nav.SetNavDestination(ResultsPage)
 		op.InvalidateOp{}.Add(gtx.Ops)
 	}
@@ -50,9 +49,10 @@ func layoutRunOptions(gtx C) D {
 					if yearsEnum.Changed() {
 						intVal, err := strconv.Atoi(yearsEnum.Value)
 						if err != nil {
-							panic(err)
+							State.SetError(err)
+						} else {
+							selectedYear = intVal
 						}
-						selectedYear = intVal
 
 						op.InvalidateOp{}.Add(gtx.Ops)
 					}
@@ -82,9 +82,10 @@ func layoutRunOptions(gtx C) D {
 					if monthsEnum.Changed() {
 						intVal, err := strconv.Atoi(monthsEnum.Value)
 						if err != nil {
-							panic(err)
+							State.SetError(err)
+						} else {
+							selectedMonth = intVal
 						}
-						selectedMonth = intVal
 
 						op.InvalidateOp{}.Add(gtx.Ops)
 					}
@@ -113,7 +114,7 @@ func layoutRunOptions(gtx C) D {
 				layout.Rigid(func(gtx C) D {
 					if commandsEnum.Changed() {
 						State.RunConfig.MetricName = commandsEnum.Value
-						logrus.Debugf("Metric selected: %s", State.RunConfig.MetricName)
+						logger.Debugf("Metric selected: %s", State.RunConfig.MetricName)
 						op.InvalidateOp{}.Add(gtx.Ops)
 					}
 					return inset.Layout(
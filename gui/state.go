@@ -2,17 +2,21 @@ package gui
 
 import (
 	"context"
+	"sync"
 
 	"github.com/3xcellent/github-metrics/client"
 	"github.com/3xcellent/github-metrics/config"
+	"github.com/3xcellent/github-metrics/credentials"
 	"github.com/3xcellent/github-metrics/models"
-	"github.com/sirupsen/logrus"
 )
 
 // MetricsState object mainstans state for gui
 type MetricsState struct {
+	Config    *config.AppConfig
 	APIConfig config.APIConfig
 	Client    *client.MetricsClient
+	// Profile - name of the connection profile of APIConfig, blank for the API settings of the config
+	Profile string
 
 	HasUpdatedAPIConfig    bool
 	HasValidatedConnection bool
@@ -27,6 +31,12 @@ type MetricsState struct {
 
 	SelectedProjectID   int64
 	SelectedProjectName string
+
+	// err - the last error, shown above the pages until dismissed; set by the goroutines calling github as well
+	errMu sync.Mutex
+	err   error
+	// invalidate - redraws the window, so errors set outside of a frame are shown
+	invalidate func()
 }
 
 type Result struct {
@@ -42,29 +52,84 @@ type Repository struct {
 type Repositories []Repository
 
 // NewState - returns new state object
-func NewState(ctx context.Context) *MetricsState {
-	return &MetricsState{}
+func NewState(ctx context.Context, cfg *config.AppConfig) *MetricsState {
+	return &MetricsState{Config: cfg}
+}
+
+// SetClientWithKeyring - stores the token of the API Config in the os keyring, rather than keeping it in the state,
+// and sets the client reading it from there
+func (s *MetricsState) SetClientWithKeyring(cfg config.APIConfig) error {
+	if cfg.Token != "" {
+		if err := credentials.StoreToken(credentials.Host(cfg.BaseURL), cfg.Token); err != nil {
+			return err
+		}
+	}
+	cfg.Token = ""
+	sources := []string{credentials.Keyring}
+	for _, source := range cfg.Credentials {
+		if source != credentials.Keyring {
+			sources = append(sources, source)
+		}
+	}
+	cfg.Credentials = sources
+	return s.SetClient(cfg)
 }
 
-// SetClient - applies settings from the API Config and sets HasUpdateAPIConfig to true
+// ProfileAPIConfig - returns the connection settings of the named profile, the API settings of the config when blank
+func (s *MetricsState) ProfileAPIConfig(profile string) (config.APIConfig, error) {
+	if profile == "" {
+		return s.Config.API, nil
+	}
+	return s.Config.APIConfig(profile)
+}
+
+// SetClient - applies settings from the API Config and sets HasUpdateAPIConfig to true; a token read from its
+// credentials is only kept by the client
 func (s *MetricsState) SetClient(cfg config.APIConfig) error {
-	c := cfg
-	s.APIConfig.Token = c.Token
-	s.APIConfig.Owner = c.Owner
-	s.APIConfig.BaseURL = c.BaseURL
-	s.APIConfig.UploadURL = c.UploadURL
+	s.APIConfig = cfg
 	s.HasUpdatedAPIConfig = true
 	s.HasValidatedConnection = false
 
-	var err error
 	ctx := context.Background()
-	s.Client, err = client.New(ctx, s.APIConfig)
+	api, err := credentials.Resolve(ctx, s.APIConfig)
 	if err != nil {
 		return err
 	}
-	logrus.Info("initialized client")
+	s.Client, err = client.New(ctx, api)
+	if err != nil {
+		return err
+	}
+	logger.Info("initialized client")
 	s.HasUpdatedAPIConfig = false
 	s.HasValidatedConnection = true
 
 	return nil
 }
+
+// SetError - shows the error until it is dismissed, replacing the one shown
+func (s *MetricsState) SetError(err error) {
+	if err == nil {
+		return
+	}
+	logger.Error(err)
+	s.errMu.Lock()
+	s.err = err
+	s.errMu.Unlock()
+	if s.invalidate != nil {
+		s.invalidate()
+	}
+}
+
+// Err - returns the error shown, nil when none is
+func (s *MetricsState) Err() error {
+	s.errMu.Lock()
+	defer s.errMu.Unlock()
+	return s.err
+}
+
+// ClearError - dismisses the error shown
+func (s *MetricsState) ClearError() {
+	s.errMu.Lock()
+	s.err = nil
+	s.errMu.Unlock()
+}
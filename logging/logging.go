@@ -0,0 +1,130 @@
+// Package logging configures the logger of each package: its level, text or json output, and the redaction of
+// secrets (tokens, private keys and Authorization headers) from every message and field
+package logging
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// PackageField - the field naming the package an entry was logged by
+const PackageField = "pkg"
+
+// formats of Options.Format
+const (
+	Text = "text"
+	JSON = "json"
+)
+
+// Options - settings of the loggers
+type Options struct {
+	// Format - text (the default) or json, one object per line
+	Format string
+	// Level - the level of every package, optionally followed by the levels of some packages, e.g. info,client=debug
+	Level string
+	// Out - where entries are written, os.Stderr when nil
+	Out io.Writer
+}
+
+var (
+	mu           sync.Mutex
+	loggers                       = make(map[string]*logrus.Logger)
+	levels                        = make(map[string]logrus.Level)
+	defaultLevel                  = logrus.InfoLevel
+	formatter    logrus.Formatter = new(logrus.TextFormatter)
+	out          io.Writer        = os.Stderr
+)
+
+func init() {
+	// entries logged with logrus directly, e.g. by dependencies, are redacted too
+	logrus.AddHook(redactHook{})
+}
+
+// For - returns the logger of the package, its entries have the pkg field set to it
+func For(pkg string) *logrus.Entry {
+	mu.Lock()
+	defer mu.Unlock()
+	logger, found := loggers[pkg]
+	if !found {
+		logger = logrus.New()
+		logger.AddHook(redactHook{})
+		configure(pkg, logger)
+		loggers[pkg] = logger
+	}
+	return logger.WithField(PackageField, pkg)
+}
+
+// Setup - applies the options to the logger of every package, those already returned by For and those to come
+func Setup(opts Options) error {
+	level, pkgLevels, err := ParseLevels(opts.Level)
+	if err != nil {
+		return err
+	}
+	var f logrus.Formatter
+	switch strings.ToLower(opts.Format) {
+	case "", Text:
+		f = new(logrus.TextFormatter)
+	case JSON:
+		f = new(logrus.JSONFormatter)
+	default:
+		return fmt.Errorf("unknown log format %q, use %s or %s", opts.Format, Text, JSON)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	defaultLevel, levels, formatter = level, pkgLevels, f
+	out = opts.Out
+	if out == nil {
+		out = os.Stderr
+	}
+	for pkg, logger := range loggers {
+		configure(pkg, logger)
+	}
+	logrus.SetLevel(defaultLevel)
+	logrus.SetFormatter(formatter)
+	logrus.SetOutput(out)
+	return nil
+}
+
+// ParseLevels - returns the default level and the levels by package of a comma separated list of a level and
+// package=level pairs; the last level without a package is the default, info when there is none
+func ParseLevels(spec string) (logrus.Level, map[string]logrus.Level, error) {
+	level := logrus.InfoLevel
+	pkgLevels := make(map[string]logrus.Level)
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		pkg, name := "", item
+		if idx := strings.Index(item, "="); idx >= 0 {
+			pkg, name = strings.TrimSpace(item[:idx]), strings.TrimSpace(item[idx+1:])
+		}
+		parsed, err := logrus.ParseLevel(name)
+		if err != nil {
+			return level, nil, fmt.Errorf("log level %q: %v", item, err)
+		}
+		if pkg == "" {
+			level = parsed
+		} else {
+			pkgLevels[pkg] = parsed
+		}
+	}
+	return level, pkgLevels, nil
+}
+
+// configure - applies the current settings to the logger of the package
+func configure(pkg string, logger *logrus.Logger) {
+	level, found := levels[pkg]
+	if !found {
+		level = defaultLevel
+	}
+	logger.SetLevel(level)
+	logger.Formatter = formatter
+	logger.Out = out
+}
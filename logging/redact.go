@@ -0,0 +1,94 @@
+package logging
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Redacted - what secrets are replaced with
+const Redacted = "[REDACTED]"
+
+var (
+	privateKeyPattern  = regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?(-----END [A-Z ]*PRIVATE KEY-----|$)`)
+	githubTokenPattern = regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{20,}|github_pat_[A-Za-z0-9_]{20,})\b`)
+	// authorizationPattern - an Authorization header, also as printed in an http.Header, its scheme kept
+	authorizationPattern = regexp.MustCompile(`(?i)(authorization["']?\s*[:=]\s*\[?["']?(?:(?:bearer|token|basic)\s+)?)[^\s"',}\]]+`)
+	// secretValuePattern - the value of a token, password or secret setting, e.g. Token:"..." or API_TOKEN=...
+	secretValuePattern = regexp.MustCompile(`(?i)((?:token|password|secret|private_?key)["']?\s*[:=]\s*["']?)[^\s"',}\]]+`)
+	// secretFieldPattern - names of fields whose values are redacted entirely
+	secretFieldPattern = regexp.MustCompile(`(?i)(token|password|secret|authorization|private_?key)$`)
+
+	secretsMu sync.RWMutex
+	secrets   = make(map[string]bool)
+)
+
+// AddSecret - redacts the value wherever it appears in entries, e.g. the api token in use; values shorter than 4
+// characters are ignored
+func AddSecret(value string) {
+	if len(value) < 4 {
+		return
+	}
+	secretsMu.Lock()
+	secrets[value] = true
+	secretsMu.Unlock()
+}
+
+// Redact - returns s with its secrets replaced by Redacted: the values added with AddSecret, github tokens, private
+// keys, Authorization headers and the values of token, password and secret settings
+func Redact(s string) string {
+	secretsMu.RLock()
+	for secret := range secrets {
+		s = strings.Replace(s, secret, Redacted, -1)
+	}
+	secretsMu.RUnlock()
+
+	s = privateKeyPattern.ReplaceAllString(s, Redacted)
+	s = githubTokenPattern.ReplaceAllString(s, Redacted)
+	s = authorizationPattern.ReplaceAllString(s, "${1}"+Redacted)
+	return secretValuePattern.ReplaceAllString(s, "${1}"+Redacted)
+}
+
+// redactHook - redacts the message and fields of every entry
+type redactHook struct{}
+
+func (redactHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (redactHook) Fire(entry *logrus.Entry) error {
+	entry.Message = Redact(entry.Message)
+	if len(entry.Data) == 0 {
+		return nil
+	}
+	// the fields may be shared with other entries, so are replaced rather than changed
+	data := make(logrus.Fields, len(entry.Data))
+	for key, value := range entry.Data {
+		data[key] = redactField(key, value)
+	}
+	entry.Data = data
+	return nil
+}
+
+func redactField(key string, value interface{}) interface{} {
+	if secretFieldPattern.MatchString(key) {
+		return Redacted
+	}
+	switch v := value.(type) {
+	case nil, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, time.Time, time.Duration:
+		return v
+	case string:
+		return Redact(v)
+	case error:
+		return Redact(v.Error())
+	case fmt.Stringer:
+		return Redact(v.String())
+	default:
+		// anything else is formatted here, so its secrets are redacted before it is written
+		return Redact(fmt.Sprintf("%+v", v))
+	}
+}
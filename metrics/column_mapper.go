@@ -0,0 +1,121 @@
+package metrics
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/3xcellent/github-metrics/config"
+	"github.com/3xcellent/github-metrics/models"
+)
+
+// ColumnMapper - resolves column names found on the board and in events to the logical
+// column they are counted in, using the column aliases and stages of a RunConfig
+type ColumnMapper struct {
+	canonical map[string]string
+	logical   map[string]bool
+	unmapped  map[string]int
+}
+
+// NewColumnMapper - returns a ColumnMapper; aliases are resolved before stages so previous
+// names of a column are counted in the column's stage
+func NewColumnMapper(aliases []config.ColumnAlias, stages []config.Stage) *ColumnMapper {
+	m := &ColumnMapper{
+		canonical: map[string]string{},
+		logical:   map[string]bool{},
+		unmapped:  map[string]int{},
+	}
+	for _, alias := range aliases {
+		for _, name := range alias.Aliases {
+			m.canonical[strings.ToUpper(name)] = alias.Name
+		}
+	}
+	for _, stage := range stages {
+		for _, name := range stage.Columns {
+			m.canonical[strings.ToUpper(name)] = stage.Name
+		}
+		for alias, column := range m.canonical {
+			if stageHasColumn(stage, column) {
+				m.canonical[alias] = stage.Name
+			}
+		}
+	}
+	return m
+}
+
+func stageHasColumn(stage config.Stage, name string) bool {
+	for _, column := range stage.Columns {
+		if strings.EqualFold(column, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolve - returns the logical column name for name, or name when it is not mapped
+func (m *ColumnMapper) Resolve(name string) string {
+	if m == nil {
+		return name
+	}
+	if canonical, found := m.canonical[strings.ToUpper(name)]; found {
+		return canonical
+	}
+	return name
+}
+
+// LogicalColumns - returns the project columns with mapped columns merged into their logical
+// column, in board order; the ID of a merged column is that of its last physical column
+func (m *ColumnMapper) LogicalColumns(projectColumns models.ProjectColumns) models.ProjectColumns {
+	columns := make(models.ProjectColumns, 0, len(projectColumns))
+	indexes := map[string]int{}
+	for _, col := range projectColumns {
+		name := m.Resolve(col.Name)
+		if idx, found := indexes[strings.ToUpper(name)]; found {
+			columns[idx].ID = col.ID
+			continue
+		}
+		indexes[strings.ToUpper(name)] = len(columns)
+		columns = append(columns, models.ProjectColumn{Name: name, ID: col.ID, Index: len(columns)})
+	}
+	if m != nil {
+		m.logical = map[string]bool{}
+		for _, col := range columns {
+			m.logical[strings.ToUpper(col.Name)] = true
+		}
+	}
+	return columns
+}
+
+// Lookup - returns the logical column name for a column name found in an event; names that are
+// not on the board after mapping are recorded as unmapped and returned with false
+func (m *ColumnMapper) Lookup(name string) (string, bool) {
+	resolved := m.Resolve(name)
+	if m == nil {
+		return resolved, true
+	}
+	if !m.logical[strings.ToUpper(resolved)] {
+		m.unmapped[name]++
+		return resolved, false
+	}
+	return resolved, true
+}
+
+// Unmapped - returns the sorted column names found in events that are not on the board
+func (m *ColumnMapper) Unmapped() []string {
+	if m == nil {
+		return nil
+	}
+	names := make([]string, 0, len(m.unmapped))
+	for name := range m.unmapped {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// UnmappedCount - returns the number of events found referencing the unmapped column name
+func (m *ColumnMapper) UnmappedCount(name string) int {
+	if m == nil {
+		return 0
+	}
+	return m.unmapped[name]
+}
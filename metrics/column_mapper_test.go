@@ -0,0 +1,57 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/3xcellent/github-metrics/config"
+	"github.com/3xcellent/github-metrics/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestColumnMapper(t *testing.T) {
+	mapper := NewColumnMapper(
+		[]config.ColumnAlias{{Name: "In Review", Aliases: []string{"Code Review"}}},
+		[]config.Stage{{Name: "Done", Columns: []string{"In Review", "Merged"}}},
+	)
+	projectColumns := models.ProjectColumns{
+		{Name: "Todo", ID: 1, Index: 0},
+		{Name: "In Progress", ID: 2, Index: 1},
+		{Name: "In Review", ID: 3, Index: 2},
+		{Name: "Merged", ID: 4, Index: 3},
+	}
+
+	t.Run("merges stage columns in board order", func(t *testing.T) {
+		expected := models.ProjectColumns{
+			{Name: "Todo", ID: 1, Index: 0},
+			{Name: "In Progress", ID: 2, Index: 1},
+			{Name: "Done", ID: 4, Index: 2},
+		}
+		assert.Equal(t, expected, mapper.LogicalColumns(projectColumns))
+	})
+
+	t.Run("resolves aliases and stages ignoring case", func(t *testing.T) {
+		assert.Equal(t, "Done", mapper.Resolve("code review"))
+		assert.Equal(t, "Done", mapper.Resolve("Merged"))
+		assert.Equal(t, "Todo", mapper.Resolve("Todo"))
+	})
+
+	t.Run("reports unmapped columns", func(t *testing.T) {
+		name, found := mapper.Lookup("Code Review")
+		assert.True(t, found)
+		assert.Equal(t, "Done", name)
+
+		_, found = mapper.Lookup("QA")
+		assert.False(t, found)
+		mapper.Lookup("QA")
+		assert.Equal(t, []string{"QA"}, mapper.Unmapped())
+		assert.Equal(t, 2, mapper.UnmappedCount("QA"))
+	})
+
+	t.Run("nil mapper does not map", func(t *testing.T) {
+		var nilMapper *ColumnMapper
+		name, found := nilMapper.Lookup("QA")
+		assert.True(t, found)
+		assert.Equal(t, "QA", name)
+		assert.Empty(t, nilMapper.Unmapped())
+	})
+}
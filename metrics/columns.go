@@ -4,14 +4,17 @@ import "time"
 
 var ColumnNameMap = map[string]int{}
 
+// ColumnAmount - the number of issues in a column
 type ColumnAmount struct {
-	Name   string
-	Amount int
+	Name   string `json:"column"`
+	Amount int    `json:"count"`
 }
 
+// ColumnAmounts - slice of ColumnAmount
 type ColumnAmounts []ColumnAmount
 
+// ColumnsMetric - typed result of the columns metric for a single date, see docs/output.md
 type ColumnsMetric struct {
-	Date          time.Time
-	ColumnAmounts ColumnAmounts
+	Date          time.Time     `json:"date"`
+	ColumnAmounts ColumnAmounts `json:"columns"`
 }
@@ -0,0 +1,140 @@
+package metrics
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/3xcellent/github-metrics/models"
+)
+
+// Decision actions recorded while setting an issue's column dates
+const (
+	DecisionAccepted  = "accepted"
+	DecisionIgnored   = "ignored"
+	DecisionInferred  = "inferred"
+	DecisionRemoved   = "removed"
+	DecisionBlocked   = "blocked"
+	DecisionUnblocked = "unblocked"
+)
+
+// Decision - how an event, or a missing column date, was used when calculating an issue's metrics
+type Decision struct {
+	At     time.Time `json:"at"`
+	Event  string    `json:"event,omitempty"`
+	Column string    `json:"column,omitempty"`
+	Action string    `json:"action"`
+	Reason string    `json:"reason,omitempty"`
+}
+
+// BlockedInterval - a period the issue was labeled blocked after entering the start column
+type BlockedInterval struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// Days - returns the length of the interval in days
+func (b BlockedInterval) Days() float64 {
+	return days(b.To.Sub(b.From))
+}
+
+func (i *Issue) decide(event models.IssueEvent, column, action, reason string) {
+	i.Decisions = append(i.Decisions, Decision{
+		At:     event.CreatedAt,
+		Event:  string(event.Type),
+		Column: column,
+		Action: action,
+		Reason: reason,
+	})
+}
+
+func (i *Issue) infer(dateIdx int, from string) {
+	i.Decisions = append(i.Decisions, Decision{
+		At:     i.ColumnDates[dateIdx].Date,
+		Column: i.ColumnDates[dateIdx].Name,
+		Action: DecisionInferred,
+		Reason: "set to " + from,
+	})
+}
+
+// Explanation - the events, decisions and resulting values of an issue's metrics
+type Explanation struct {
+	Issue            string            `json:"issue"`
+	Title            string            `json:"title"`
+	ProjectID        int64             `json:"projectId"`
+	Created          time.Time         `json:"created"`
+	Events           []ExplainedEvent  `json:"events"`
+	Decisions        []Decision        `json:"decisions"`
+	BlockedIntervals []BlockedInterval `json:"blockedIntervals"`
+	ColumnDates      []ExplainedColumn `json:"columnDates"`
+	Values           ExplanationValues `json:"values"`
+	Anomalies        []Anomaly         `json:"anomalies"`
+}
+
+// ExplainedEvent - an event of the issue's timeline
+type ExplainedEvent struct {
+	At             time.Time `json:"at"`
+	Type           string    `json:"type"`
+	ProjectID      int64     `json:"projectId,omitempty"`
+	Column         string    `json:"column,omitempty"`
+	PreviousColumn string    `json:"previousColumn,omitempty"`
+	Label          string    `json:"label,omitempty"`
+}
+
+// ExplainedColumn - the final date of a start to end column
+type ExplainedColumn struct {
+	Column   string    `json:"column"`
+	Date     time.Time `json:"date"`
+	Inferred bool      `json:"inferred"`
+}
+
+// ExplanationValues - the final computed values, as reported by the issues metric
+type ExplanationValues struct {
+	CycleTimeDays   float64 `json:"cycleTimeDays"`
+	LeadTimeDays    float64 `json:"leadTimeDays"`
+	TimeToStartDays float64 `json:"timeToStartDays"`
+	BlockedDays     float64 `json:"blockedDays"`
+	OnProject       bool    `json:"onProject"`
+}
+
+// Explain - returns the Explanation of a processed issue's metrics
+func (i *Issue) Explain() Explanation {
+	explanation := Explanation{
+		Issue:            fmt.Sprintf("%s/%s#%d", i.Owner, i.RepoName, i.Number),
+		Title:            i.Title,
+		ProjectID:        i.ProjectID,
+		Created:          i.CreatedDate(),
+		Events:           make([]ExplainedEvent, 0, len(i.Events)),
+		Decisions:        i.Decisions,
+		BlockedIntervals: i.BlockedIntervals,
+		ColumnDates:      make([]ExplainedColumn, 0),
+		Anomalies:        i.Anomalies(),
+		Values: ExplanationValues{
+			BlockedDays: days(i.TotalTimeBlocked),
+			OnProject:   i.OnProject,
+		},
+	}
+	for _, event := range i.Events {
+		explanation.Events = append(explanation.Events, ExplainedEvent{
+			At:             event.CreatedAt,
+			Type:           string(event.Type),
+			ProjectID:      event.ProjectID,
+			Column:         event.ColumnName,
+			PreviousColumn: event.PreviousColumnName,
+			Label:          event.Label,
+		})
+	}
+	if len(i.ColumnDates) <= i.EndColumnIndex {
+		return explanation
+	}
+	for idx := i.StartColumnIndex; idx <= i.EndColumnIndex; idx++ {
+		explanation.ColumnDates = append(explanation.ColumnDates, ExplainedColumn{
+			Column:   i.ColumnDates[idx].Name,
+			Date:     i.ColumnDates[idx].Date,
+			Inferred: i.ColumnDates[idx].Inferred,
+		})
+	}
+	explanation.Values.CycleTimeDays = i.CalcDays()
+	explanation.Values.LeadTimeDays = i.LeadTimeDays()
+	explanation.Values.TimeToStartDays = i.TimeToStartDays()
+	return explanation
+}
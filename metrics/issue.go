@@ -9,7 +9,6 @@ import (
 	"time"
 
 	"github.com/3xcellent/github-metrics/models"
-	"github.com/sirupsen/logrus"
 )
 
 // IssuesDateColumns - slice of IssuesDateColumn
@@ -19,6 +18,8 @@ type IssuesDateColumns []IssuesDateColumn
 type IssuesDateColumn struct {
 	*models.ProjectColumn
 	Date time.Time
+	// Inferred is set when Date was not observed in an event but filled in from another column or event
+	Inferred bool
 }
 
 //ColumnNames - returns the slice of column names
@@ -39,6 +40,22 @@ type Issue struct {
 	ProjectID        int64
 	StartColumnIndex int
 	EndColumnIndex   int
+	ColumnMapper     *ColumnMapper
+
+	// set while processing events for ProjectID
+	OnProject            bool
+	ProjectCardID        int64
+	RemovedFromProjectAt time.Time
+	ClosedAt             time.Time
+
+	// kept for reporting data quality anomalies
+	firstColumnIndex int
+	movedToColumn    bool
+	unknownColumns   []string
+
+	// Decisions and BlockedIntervals record how the column dates and blocked time were set
+	Decisions        []Decision
+	BlockedIntervals []BlockedInterval
 
 	Type      string
 	IsFeature bool
@@ -50,9 +67,46 @@ type Issue struct {
 	DevTime          time.Duration
 }
 
+// CompletedAt - returns the date the issue entered the end column
+func (i *Issue) CompletedAt() time.Time {
+	return i.ColumnDates[i.EndColumnIndex].Date
+}
+
 func (i *Issue) CalcDays() float64 {
-	// logrus.Debugf("\t %s/%s/%d - calcuting: %s - %s", i.Owner, i.RepoName, i.Number, i.ColumnDates[i.EndColumnIndex].Date.String(), i.ColumnDates[i.StartColumnIndex].Date.String())
-	return float64(i.ColumnDates[i.EndColumnIndex].Date.Sub(i.ColumnDates[i.StartColumnIndex].Date)) / float64(time.Hour) / 24
+	// logger.Debugf("\t %s/%s/%d - calcuting: %s - %s", i.Owner, i.RepoName, i.Number, i.ColumnDates[i.EndColumnIndex].Date.String(), i.ColumnDates[i.StartColumnIndex].Date.String())
+	return days(i.ColumnDates[i.EndColumnIndex].Date.Sub(i.ColumnDates[i.StartColumnIndex].Date))
+}
+
+// CreatedDate - returns when the issue was created.  Issues transferred from another repo
+// keep their events but are re-created in the new repo, so the earliest event is used when
+// it is before the issue's CreatedAt.
+func (i *Issue) CreatedDate() time.Time {
+	created := i.CreatedAt
+	for _, event := range i.Events {
+		if created.IsZero() || event.CreatedAt.Before(created) {
+			created = event.CreatedAt
+		}
+	}
+	return created
+}
+
+// LeadTimeDays - returns the days from issue creation until it entered the end column,
+// including any time before the issue was added to the project
+func (i *Issue) LeadTimeDays() float64 {
+	return days(i.CompletedAt().Sub(i.CreatedDate()))
+}
+
+// TimeToStartDays - returns the days from issue creation until it entered the start column
+func (i *Issue) TimeToStartDays() float64 {
+	started := i.ColumnDates[i.StartColumnIndex].Date
+	if started.Before(i.CreatedDate()) {
+		return 0
+	}
+	return days(started.Sub(i.CreatedDate()))
+}
+
+func days(d time.Duration) float64 {
+	return float64(d) / float64(time.Hour) / 24
 }
 
 //CSVHeaders - returns list of colun headers
@@ -72,7 +126,10 @@ func (i *Issue) CSVHeaders() []string {
 		"Development Days",
 		"Feature?",
 		"Blocked?",
-		"Blocked Days")
+		"Blocked Days",
+		"Created",
+		"Lead Time Days",
+		"Time To Start Days")
 }
 
 // Values - returns a row of csv values for a single issue
@@ -92,6 +149,9 @@ func (i *Issue) Values() []string {
 		strconv.FormatBool(i.IsFeature),
 		fmt.Sprintf("%t", math.Ceil(float64(i.TotalTimeBlocked/time.Hour/24)) > 0), // was blocked over 24 hours?
 		FmtDays(i.TotalTimeBlocked),                                                // time blocked over 24 hours
+		i.CreatedDate().Format("01/02/06"),
+		fmt.Sprintf("%.1f", i.LeadTimeDays()),
+		fmt.Sprintf("%.1f", i.TimeToStartDays()),
 	)
 }
 
@@ -138,7 +198,7 @@ func HasFeatureLabel(labels []string) bool {
 
 // ProcessIssueEvents sets column dates based on its events
 func (i *Issue) ProcessIssueEvents() {
-	logrus.Debugf("Events: %s/%s/%d - %s", i.Owner, i.RepoName, i.Number, i.Title)
+	logger.Debugf("Events: %s/%s/%d - %s", i.Owner, i.RepoName, i.Number, i.Title)
 	if len(i.Events) == 0 {
 		return
 	}
@@ -151,83 +211,124 @@ func (i *Issue) setColumnDates() {
 	// var startColumn = i.ColumnDates[i.StartColumnIndex]
 	initTime := time.Time{}
 
+	// column the card is in, used as the previous column when an event does not provide one
+	var currentColumn *IssuesDateColumn
+
 	for idx, event := range i.Events {
 		eventNum := idx
 		logPrefix := fmt.Sprintf("  [%d]@%s | %s", eventNum, event.CreatedAt.String(), event.Type)
+		if !i.isProjectCardEvent(event) {
+			logger.Debugf("%s: ignoring event for project %d card %d", logPrefix, event.ProjectID, event.ProjectCardID)
+			i.decide(event, event.ColumnName, DecisionIgnored, fmt.Sprintf("event for project %d card %d", event.ProjectID, event.ProjectCardID))
+			continue
+		}
 		switch event.Type {
 		case models.AddedToProject:
-			logrus.Debugf("%s: %d", logPrefix, event.ProjectID)
-			i.ProjectID = event.ProjectID
-			logrus.Debugf("\t * added to projectID: %d", event.ProjectID)
+			logger.Debugf("%s: %d", logPrefix, event.ProjectID)
+			i.ProjectCardID = event.ProjectCardID
+			i.RemovedFromProjectAt = time.Time{}
+			// a card is added to a column, not moved from one
+			currentColumn = nil
+			logger.Debugf("\t * added to projectID: %d", event.ProjectID)
 			fallthrough // Must fallthrough to MovedColumns for handling of case where card is dropped into column, and has not moved; expecting GetColumnName to be set
 		case models.MovedColumns:
-			logrus.Debugf("%s - moved columns: %q -> %q\n", logPrefix, event.PreviousColumnName, event.ColumnName)
+			logger.Debugf("%s - moved columns: %q -> %q\n", logPrefix, event.PreviousColumnName, event.ColumnName)
+			i.OnProject = true
 
 			movedToColumn, err := i.getColumn(event.ColumnName)
 			if err != nil {
-				logrus.Warnf("error getting column: %s\n", err.Error())
+				logger.Warnf("error getting column: %s\n", err.Error())
+				i.unknownColumns = append(i.unknownColumns, event.ColumnName)
+				i.decide(event, event.ColumnName, DecisionIgnored, "unknown column")
 				continue
 			}
+			if !i.movedToColumn {
+				i.movedToColumn = true
+				i.firstColumnIndex = movedToColumn.Index
+			}
 
+			movedFromColumn := currentColumn
+			currentColumn = movedToColumn
 			if event.PreviousColumnName != "" {
-				movedFromColumn, err := i.getColumn(event.PreviousColumnName)
+				movedFromColumn, err = i.getColumn(event.PreviousColumnName)
 				if err != nil {
-					logrus.Warnf("error getting previous column: %s\n", err.Error())
-					continue
-				}
-				if movedFromColumn.Index > movedToColumn.Index {
-					logrus.Debugf("\t * card moved back %d columns, leaving date: %s", movedFromColumn.Index-movedToColumn.Index, i.ColumnDates[movedToColumn.Index].Date.String())
+					logger.Warnf("error getting previous column: %s\n", err.Error())
+					i.unknownColumns = append(i.unknownColumns, event.PreviousColumnName)
+					i.decide(event, event.ColumnName, DecisionIgnored, fmt.Sprintf("unknown previous column %q", event.PreviousColumnName))
 					continue
 				}
 			}
+			if movedFromColumn != nil && movedFromColumn.Index > movedToColumn.Index {
+				logger.Debugf("\t * card moved back %d columns, leaving date: %s", movedFromColumn.Index-movedToColumn.Index, i.ColumnDates[movedToColumn.Index].Date.String())
+				i.decide(event, movedToColumn.Name, DecisionIgnored, fmt.Sprintf("backward move from %q", movedFromColumn.Name))
+				continue
+			}
 
-			logrus.Debugf("%s - setting column %q date - %s\n", logPrefix, movedToColumn.Name, event.CreatedAt)
+			logger.Debugf("%s - setting column %q date - %s\n", logPrefix, movedToColumn.Name, event.CreatedAt)
 			i.ColumnDates[movedToColumn.Index].Date = event.CreatedAt
-			logrus.Debugf("---- verifying i.DateColumns[%d].Date: %s", movedToColumn.Index, i.ColumnDates[movedToColumn.Index].Date.String())
+			i.decide(event, movedToColumn.Name, DecisionAccepted, "")
+			logger.Debugf("---- verifying i.DateColumns[%d].Date: %s", movedToColumn.Index, i.ColumnDates[movedToColumn.Index].Date.String())
+
+		case models.RemovedFromProject:
+			logger.Debugf("%s: %d", logPrefix, event.ProjectID)
+			i.OnProject = false
+			i.RemovedFromProjectAt = event.CreatedAt
+			// a card re-added later starts from the column it is added to, not the one it was removed from
+			currentColumn = nil
+			i.decide(event, "", DecisionRemoved, "")
+			logger.Debugf("\t * removed from projectID: %d", event.ProjectID)
 
 		case models.Labeled:
-			logrus.Debugf("%s: %q", logPrefix, event.Label)
+			logger.Debugf("%s: %q", logPrefix, event.Label)
 			cardStatus := ToIssueLabel(event.Label)
 			switch cardStatus {
 			case Blocked:
-				logrus.Debugf("%s: %q", logPrefix, event.Label)
+				logger.Debugf("%s: %q", logPrefix, event.Label)
 				if len(i.ColumnDates) < i.StartColumnIndex+1 {
-					logrus.Warnf("issue: %#v\n", i)
-					logrus.Fatalf("i.IssuesDateColumns does not contain item at index %d\n", i.StartColumnIndex)
-				}
-				if i.ColumnDates[i.StartColumnIndex].Date != initTime {
+					// the dates of the issue were not set up for the columns of the board
+					logger.Warnf("issue %d: no column date at start column index %d, blocked label ignored", i.Number, i.StartColumnIndex)
+					i.decide(event, "", DecisionIgnored, "start column not found")
+				} else if i.ColumnDates[i.StartColumnIndex].Date != initTime {
 					blockedAt = event.CreatedAt
-					logrus.Debug("\t * blocked")
+					logger.Debug("\t * blocked")
+					i.decide(event, "", DecisionBlocked, "")
 				} else {
-					logrus.Debug("\t * blocked but not in develop yet")
+					logger.Debug("\t * blocked but not in develop yet")
+					i.decide(event, "", DecisionIgnored, "blocked before start column")
 				}
 			default:
 			}
 		case models.Unlabeled:
-			logrus.Debugf("%s: removed %q", logPrefix, event.Label)
+			logger.Debugf("%s: removed %q", logPrefix, event.Label)
 			issueStatus := ToIssueLabel(event.Label)
 			switch issueStatus {
 			case Blocked:
 				if blockedAt.After(i.ColumnDates[i.StartColumnIndex].Date) {
-					logrus.Debugf("\t * unblocked")
+					logger.Debugf("\t * unblocked")
 					i.TotalTimeBlocked += event.CreatedAt.Sub(blockedAt)
+					i.BlockedIntervals = append(i.BlockedIntervals, BlockedInterval{From: blockedAt, To: event.CreatedAt})
+					i.decide(event, "", DecisionUnblocked, FmtDays(event.CreatedAt.Sub(blockedAt))+" days")
 				} else {
-					logrus.Debug("\t * unblocked, ignoring because card was blocked before in development")
+					logger.Debug("\t * unblocked, ignoring because card was blocked before in development")
+					i.decide(event, "", DecisionIgnored, "blocked before start column")
 				}
 
 				blockedAt = time.Time{}
 			default:
 			}
 		case models.Assigned:
-			logrus.Debugf("%s: %q", logPrefix, event.Assignee)
+			logger.Debugf("%s: %q", logPrefix, event.Assignee)
 		case models.Unassigned:
-			logrus.Debugf("%s: %q", logPrefix, event.Assignee)
+			logger.Debugf("%s: %q", logPrefix, event.Assignee)
 		case models.Mentioned:
-			logrus.Debugf("%s: %q - %q", logPrefix, event.LoginName, event.Note)
+			logger.Debugf("%s: %q - %q", logPrefix, event.LoginName, event.Note)
 		case models.Closed:
-			logrus.Debugf("%s", logPrefix)
+			logger.Debugf("%s", logPrefix)
+			i.ClosedAt = event.CreatedAt
+		case models.Transferred:
+			logger.Debugf("%s: created %s", logPrefix, i.CreatedAt.String())
 		default:
-			logrus.Debugf("%s: unrecognized event", logPrefix)
+			logger.Debugf("%s: unrecognized event", logPrefix)
 		}
 	}
 }
@@ -240,32 +341,63 @@ func (i *Issue) setEmptyColumnDates() {
 	// adjusting missing dates to previous column if not set
 	for dateIdx := len(i.ColumnDates) - 1; dateIdx >= 0; dateIdx-- {
 		// if date was never set, set to next date we know something happened
-		logrus.Debugf("idx %d - column: %s - Date: %s", dateIdx, i.ColumnDates[dateIdx].Name, i.ColumnDates[dateIdx].Date.String())
+		logger.Debugf("idx %d - column: %s - Date: %s", dateIdx, i.ColumnDates[dateIdx].Name, i.ColumnDates[dateIdx].Date.String())
 		if i.ColumnDates[dateIdx].Date.IsZero() {
-			logrus.Debugf("\t\tDate.IsZero()")
+			logger.Debugf("\t\tDate.IsZero()")
+			i.ColumnDates[dateIdx].Inferred = dateIdx >= i.StartColumnIndex && dateIdx <= i.EndColumnIndex
 			// if last
 			if dateIdx == i.EndColumnIndex {
 				i.ColumnDates[dateIdx].Date = i.Events[len(i.Events)-1].CreatedAt // get date from last event
-				logrus.Debugf("\t\tsetting to last event date: %s", i.Events[len(i.Events)-1].CreatedAt.String())
+				logger.Debugf("\t\tsetting to last event date: %s", i.Events[len(i.Events)-1].CreatedAt.String())
+				i.infer(dateIdx, "last event date")
 			} else if dateIdx < i.EndColumnIndex && dateIdx > i.StartColumnIndex {
 				i.ColumnDates[dateIdx].Date = i.ColumnDates[dateIdx+1].Date // get date from date column just set
-				logrus.Debugf("\t\tsetting to next column date: %s", i.ColumnDates[dateIdx+1].Date.String())
+				logger.Debugf("\t\tsetting to next column date: %s", i.ColumnDates[dateIdx+1].Date.String())
+				i.infer(dateIdx, fmt.Sprintf("date of next column %q", i.ColumnDates[dateIdx+1].Name))
 			} else if dateIdx == i.StartColumnIndex {
 				i.ColumnDates[dateIdx].Date = i.Events[0].CreatedAt // get date from date column just set
-				logrus.Debugf("\t\tsetting to first event date: %s", i.Events[0].CreatedAt.String())
+				logger.Debugf("\t\tsetting to first event date: %s", i.Events[0].CreatedAt.String())
+				i.infer(dateIdx, "first event date")
 			}
 		}
 	}
 	for _, date := range i.ColumnDates {
-		logrus.Debugf("\t\t\t%s - %s", date.Name, date.Date.String())
+		logger.Debugf("\t\t\t%s - %s", date.Name, date.Date.String())
 	}
 }
 
+// isProjectCardEvent - returns false for project events on other projects, or on a previous card of this project
+func (i *Issue) isProjectCardEvent(event models.IssueEvent) bool {
+	return IsProjectCardEvent(event, i.ProjectID, i.ProjectCardID)
+}
+
+// IsProjectCardEvent - returns false for card events, added, moved or removed, of a project other than projectID,
+// or of a card other than cardID, the card last added; events without a project or card id, or before a card was
+// added, are assumed to belong to them. The issues and columns runners both use it so their dates agree
+func IsProjectCardEvent(event models.IssueEvent, projectID, cardID int64) bool {
+	switch event.Type {
+	case models.AddedToProject, models.MovedColumns, models.RemovedFromProject:
+	default:
+		return true
+	}
+	if event.ProjectID != 0 && event.ProjectID != projectID {
+		return false
+	}
+	if event.Type != models.AddedToProject && event.ProjectCardID != 0 && cardID != 0 {
+		return event.ProjectCardID == cardID
+	}
+	return true
+}
+
 func (i *Issue) getColumn(name string) (*IssuesDateColumn, error) {
 	if len(i.ColumnDates) == 0 {
 		return nil, errors.New("ColumnDates is empty")
 	}
-	lookingFor := strings.ToUpper(name)
+	resolved, found := i.ColumnMapper.Lookup(name)
+	if !found {
+		return nil, errors.New("column not mapped: " + name)
+	}
+	lookingFor := strings.ToUpper(resolved)
 	for _, col := range i.ColumnDates {
 		if strings.ToUpper(col.Name) == lookingFor {
 			return &col, nil
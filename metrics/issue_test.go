@@ -2,7 +2,9 @@ package metrics
 
 import (
 	"testing"
+	"time"
 
+	"github.com/3xcellent/github-metrics/config"
 	"github.com/3xcellent/github-metrics/models"
 	"github.com/3xcellent/github-metrics/tools/testhelpers"
 	"github.com/stretchr/testify/assert"
@@ -131,6 +133,200 @@ func TestIssueMetric_setColumnDates(t *testing.T) {
 		})
 	})
 }
+func TestIssueMetric_setColumnDates_MultipleProjects(t *testing.T) {
+	const otherProjectID = int64(99)
+	dates := testhelpers.NewDates(8)
+	cols := testhelpers.NewProjectColumns(3)
+	newIssue := func(events models.IssueEvents) Issue {
+		issue := Issue{
+			Issue:            testhelpers.NewIssue(),
+			ProjectID:        42,
+			StartColumnIndex: 0,
+			EndColumnIndex:   2,
+			ColumnDates: IssuesDateColumns{
+				{ProjectColumn: &cols[0]},
+				{ProjectColumn: &cols[1]},
+				{ProjectColumn: &cols[2]},
+			},
+		}
+		issue.Events = events
+		issue.setColumnDates()
+		return issue
+	}
+
+	t.Run("card on two boards with the same column names", func(t *testing.T) {
+		issue := newIssue(models.IssueEvents{
+			{Type: models.AddedToProject, CreatedAt: dates[0], ProjectID: 42, ProjectCardID: 1, ColumnName: cols[0].Name},
+			{Type: models.AddedToProject, CreatedAt: dates[1], ProjectID: otherProjectID, ProjectCardID: 2, ColumnName: cols[0].Name},
+			{Type: models.MovedColumns, CreatedAt: dates[2], ProjectID: otherProjectID, ProjectCardID: 2, ColumnName: cols[2].Name, PreviousColumnName: cols[0].Name},
+			{Type: models.MovedColumns, CreatedAt: dates[3], ProjectID: 42, ProjectCardID: 1, ColumnName: cols[1].Name, PreviousColumnName: cols[0].Name},
+			{Type: models.MovedColumns, CreatedAt: dates[4], ProjectID: 42, ProjectCardID: 1, ColumnName: cols[2].Name, PreviousColumnName: cols[1].Name},
+		})
+
+		t.Run("only assigns dates from events on the run's project", func(t *testing.T) {
+			assertColumnDates(t, IssuesDateColumns{
+				{ProjectColumn: &cols[0], Date: dates[0]},
+				{ProjectColumn: &cols[1], Date: dates[3]},
+				{ProjectColumn: &cols[2], Date: dates[4]},
+			}, issue.ColumnDates)
+		})
+
+		t.Run("keeps the run's project and card", func(t *testing.T) {
+			assert.Equal(t, int64(42), issue.ProjectID)
+			assert.Equal(t, int64(1), issue.ProjectCardID)
+			assert.True(t, issue.OnProject)
+		})
+	})
+
+	t.Run("card only on another board", func(t *testing.T) {
+		issue := newIssue(models.IssueEvents{
+			{Type: models.AddedToProject, CreatedAt: dates[0], ProjectID: otherProjectID, ColumnName: cols[0].Name},
+			{Type: models.MovedColumns, CreatedAt: dates[1], ProjectID: otherProjectID, ColumnName: cols[2].Name},
+		})
+
+		t.Run("is not on the project", func(t *testing.T) {
+			assert.False(t, issue.OnProject)
+			assertColumnDates(t, IssuesDateColumns{
+				{ProjectColumn: &cols[0]},
+				{ProjectColumn: &cols[1]},
+				{ProjectColumn: &cols[2]},
+			}, issue.ColumnDates)
+		})
+	})
+
+	t.Run("card removed from the project", func(t *testing.T) {
+		issue := newIssue(models.IssueEvents{
+			{Type: models.AddedToProject, CreatedAt: dates[0], ProjectID: 42, ProjectCardID: 1, ColumnName: cols[0].Name},
+			{Type: models.MovedColumns, CreatedAt: dates[1], ProjectID: 42, ProjectCardID: 1, ColumnName: cols[1].Name},
+			{Type: models.RemovedFromProject, CreatedAt: dates[2], ProjectID: 42, ProjectCardID: 1},
+		})
+
+		t.Run("is not on the project", func(t *testing.T) {
+			assert.False(t, issue.OnProject)
+			assert.Equal(t, dates[2], issue.RemovedFromProjectAt)
+		})
+	})
+
+	t.Run("card removed from and re-added to the project", func(t *testing.T) {
+		issue := newIssue(models.IssueEvents{
+			{Type: models.AddedToProject, CreatedAt: dates[0], ProjectID: 42, ProjectCardID: 1, ColumnName: cols[0].Name},
+			{Type: models.MovedColumns, CreatedAt: dates[1], ProjectID: 42, ProjectCardID: 1, ColumnName: cols[1].Name},
+			{Type: models.RemovedFromProject, CreatedAt: dates[2], ProjectID: 42, ProjectCardID: 1},
+			{Type: models.MovedColumns, CreatedAt: dates[3], ProjectID: 42, ProjectCardID: 1, ColumnName: cols[2].Name},
+			{Type: models.AddedToProject, CreatedAt: dates[4], ProjectID: 42, ProjectCardID: 3, ColumnName: cols[0].Name},
+			{Type: models.MovedColumns, CreatedAt: dates[5], ProjectID: 42, ProjectCardID: 3, ColumnName: cols[1].Name},
+			{Type: models.MovedColumns, CreatedAt: dates[6], ProjectID: 42, ProjectCardID: 3, ColumnName: cols[2].Name},
+		})
+
+		t.Run("is on the project with the new card", func(t *testing.T) {
+			assert.True(t, issue.OnProject)
+			assert.True(t, issue.RemovedFromProjectAt.IsZero())
+			assert.Equal(t, int64(3), issue.ProjectCardID)
+		})
+
+		t.Run("dates the columns the new card enters", func(t *testing.T) {
+			assertColumnDates(t, IssuesDateColumns{
+				{ProjectColumn: &cols[0], Date: dates[4]},
+				{ProjectColumn: &cols[1], Date: dates[5]},
+				{ProjectColumn: &cols[2], Date: dates[6]},
+			}, issue.ColumnDates)
+		})
+	})
+}
+
+func TestIsProjectCardEvent(t *testing.T) {
+	t.Run("accepts events of the project and card, or without them", func(t *testing.T) {
+		assert.True(t, IsProjectCardEvent(models.IssueEvent{Type: models.MovedColumns, ProjectID: 42, ProjectCardID: 1}, 42, 1))
+		assert.True(t, IsProjectCardEvent(models.IssueEvent{Type: models.MovedColumns}, 42, 1))
+		assert.True(t, IsProjectCardEvent(models.IssueEvent{Type: models.MovedColumns, ProjectID: 42, ProjectCardID: 2}, 42, 0))
+		assert.True(t, IsProjectCardEvent(models.IssueEvent{Type: models.AddedToProject, ProjectID: 42, ProjectCardID: 2}, 42, 1))
+		assert.True(t, IsProjectCardEvent(models.IssueEvent{Type: models.Labeled, ProjectID: 99}, 42, 1))
+	})
+
+	t.Run("rejects events of other projects or cards", func(t *testing.T) {
+		assert.False(t, IsProjectCardEvent(models.IssueEvent{Type: models.AddedToProject, ProjectID: 99}, 42, 0))
+		assert.False(t, IsProjectCardEvent(models.IssueEvent{Type: models.RemovedFromProject, ProjectID: 42, ProjectCardID: 2}, 42, 1))
+	})
+}
+
+func TestIssueMetric_setColumnDates_ReAdded(t *testing.T) {
+	dates := testhelpers.NewDates(6)
+	cols := testhelpers.NewProjectColumns(3)
+	issue := Issue{
+		Issue:            testhelpers.NewIssue(),
+		ProjectID:        42,
+		StartColumnIndex: 1,
+		EndColumnIndex:   2,
+		ColumnDates: IssuesDateColumns{
+			{ProjectColumn: &cols[0]},
+			{ProjectColumn: &cols[1]},
+			{ProjectColumn: &cols[2]},
+		},
+	}
+	issue.Events = models.IssueEvents{
+		{Type: models.AddedToProject, CreatedAt: dates[0], ProjectID: 42, ProjectCardID: 1, ColumnName: cols[0].Name},
+		{Type: models.MovedColumns, CreatedAt: dates[1], ProjectID: 42, ProjectCardID: 1, ColumnName: cols[2].Name, PreviousColumnName: cols[0].Name},
+		{Type: models.RemovedFromProject, CreatedAt: dates[2], ProjectID: 42, ProjectCardID: 1},
+		{Type: models.AddedToProject, CreatedAt: dates[3], ProjectID: 42, ProjectCardID: 2, ColumnName: cols[0].Name},
+		{Type: models.MovedColumns, CreatedAt: dates[4], ProjectID: 42, ProjectCardID: 2, ColumnName: cols[1].Name, PreviousColumnName: cols[0].Name},
+		{Type: models.MovedColumns, CreatedAt: dates[5], ProjectID: 42, ProjectCardID: 2, ColumnName: cols[2].Name, PreviousColumnName: cols[1].Name},
+	}
+	issue.setColumnDates()
+
+	t.Run("re-added from the end column to an earlier column", func(t *testing.T) {
+		t.Run("is not a backward move", func(t *testing.T) {
+			assert.Equal(t, Decision{At: dates[3], Event: string(models.AddedToProject), Column: cols[0].Name, Action: DecisionAccepted}, issue.Decisions[3])
+		})
+
+		t.Run("dates the columns from the re-added card", func(t *testing.T) {
+			assertColumnDates(t, IssuesDateColumns{
+				{ProjectColumn: &cols[0], Date: dates[3]},
+				{ProjectColumn: &cols[1], Date: dates[4]},
+				{ProjectColumn: &cols[2], Date: dates[5]},
+			}, issue.ColumnDates)
+		})
+	})
+}
+
+func TestIssueMetric_setColumnDates_ColumnAliases(t *testing.T) {
+	dates := testhelpers.NewDates(3)
+	cols := testhelpers.NewProjectColumns(3)
+	mapper := NewColumnMapper([]config.ColumnAlias{{Name: cols[1].Name, Aliases: []string{"Code Review"}}}, nil)
+	mapper.LogicalColumns(cols)
+
+	issue := Issue{
+		Issue:            testhelpers.NewIssue(),
+		ProjectID:        42,
+		StartColumnIndex: 0,
+		EndColumnIndex:   2,
+		ColumnMapper:     mapper,
+		ColumnDates: IssuesDateColumns{
+			{ProjectColumn: &cols[0]},
+			{ProjectColumn: &cols[1]},
+			{ProjectColumn: &cols[2]},
+		},
+	}
+	issue.Events = models.IssueEvents{
+		{Type: models.MovedColumns, CreatedAt: dates[0], ColumnName: cols[0].Name},
+		{Type: models.MovedColumns, CreatedAt: dates[1], ColumnName: "Code Review"},
+		{Type: models.MovedColumns, CreatedAt: dates[2], ColumnName: "QA"},
+	}
+	issue.setColumnDates()
+
+	t.Run("sets the date of the aliased column", func(t *testing.T) {
+		expected := IssuesDateColumns{
+			{ProjectColumn: &cols[0], Date: dates[0]},
+			{ProjectColumn: &cols[1], Date: dates[1]},
+			{ProjectColumn: &cols[2]},
+		}
+		assertColumnDates(t, expected, issue.ColumnDates)
+	})
+
+	t.Run("records unmapped columns", func(t *testing.T) {
+		assert.Equal(t, []string{"QA"}, mapper.Unmapped())
+	})
+}
+
 func TestIssueMetric_setEmptyColumnDates(t *testing.T) {
 	t.Run("ColumnDates with empty dates", func(t *testing.T) {
 		dates := testhelpers.NewDates(4)
@@ -147,14 +343,14 @@ func TestIssueMetric_setEmptyColumnDates(t *testing.T) {
 				{ProjectColumn: &cols[3], Date: dates[3]},
 			},
 		}
-		t.Run("get assigned next column date", func(t *testing.T) {
+		t.Run("get assigned next column date and marked inferred", func(t *testing.T) {
 			issue.setEmptyColumnDates()
 
 			expectedIssue := Issue{
 				ColumnDates: IssuesDateColumns{
 					{ProjectColumn: &cols[0], Date: dates[0]},
 					{ProjectColumn: &cols[1], Date: dates[1]},
-					{ProjectColumn: &cols[2], Date: dates[3]},
+					{ProjectColumn: &cols[2], Date: dates[3], Inferred: true},
 					{ProjectColumn: &cols[3], Date: dates[3]},
 				},
 			}
@@ -179,7 +375,7 @@ func TestIssueMetric_setEmptyColumnDates(t *testing.T) {
 				{ProjectColumn: &cols[3]},
 			},
 		}
-		t.Run("gets assigned last event date", func(t *testing.T) {
+		t.Run("gets assigned last event date and marked inferred", func(t *testing.T) {
 			events := models.IssueEvents{
 				{Type: "test event", CreatedAt: dates[0], ColumnName: cols[0].Name},
 				{Type: "test event", CreatedAt: dates[1], ColumnName: cols[1].Name},
@@ -194,7 +390,7 @@ func TestIssueMetric_setEmptyColumnDates(t *testing.T) {
 					{ProjectColumn: &cols[0], Date: dates[0]},
 					{ProjectColumn: &cols[1], Date: dates[1]},
 					{ProjectColumn: &cols[2], Date: dates[2]},
-					{ProjectColumn: &cols[3], Date: dates[3]},
+					{ProjectColumn: &cols[3], Date: dates[3], Inferred: true},
 				},
 			}
 
@@ -204,6 +400,62 @@ func TestIssueMetric_setEmptyColumnDates(t *testing.T) {
 
 }
 
+func TestIssueMetric_LeadTime(t *testing.T) {
+	dates := testhelpers.NewDates(6)
+	cols := testhelpers.NewProjectColumns(3)
+	newIssue := func(createdAt time.Time, events models.IssueEvents) Issue {
+		issue := Issue{
+			Issue:            &models.Issue{CreatedAt: createdAt, Events: events},
+			StartColumnIndex: 1,
+			EndColumnIndex:   2,
+			ColumnDates: IssuesDateColumns{
+				{ProjectColumn: &cols[0], Date: dates[3]},
+				{ProjectColumn: &cols[1], Date: dates[4]},
+				{ProjectColumn: &cols[2], Date: dates[5]},
+			},
+		}
+		return issue
+	}
+
+	t.Run("issue created before being added to the project", func(t *testing.T) {
+		issue := newIssue(dates[0], models.IssueEvents{
+			{Type: models.AddedToProject, CreatedAt: dates[3], ColumnName: cols[0].Name},
+		})
+
+		t.Run("lead time is measured from creation", func(t *testing.T) {
+			assert.InDelta(t, 5.0, issue.LeadTimeDays(), 0.001)
+		})
+		t.Run("time to start is measured from creation", func(t *testing.T) {
+			assert.InDelta(t, 4.0, issue.TimeToStartDays(), 0.001)
+		})
+		t.Run("cycle time is unchanged", func(t *testing.T) {
+			assert.InDelta(t, 1.0, issue.CalcDays(), 0.001)
+		})
+	})
+
+	t.Run("issue transferred from another repo", func(t *testing.T) {
+		issue := newIssue(dates[4], models.IssueEvents{
+			{Type: models.AddedToProject, CreatedAt: dates[1], ColumnName: cols[0].Name},
+			{Type: models.Transferred, CreatedAt: dates[4]},
+		})
+
+		t.Run("created date is the earliest event", func(t *testing.T) {
+			assert.Equal(t, dates[1], issue.CreatedDate())
+		})
+		t.Run("lead time is measured from the earliest event", func(t *testing.T) {
+			assert.InDelta(t, 4.0, issue.LeadTimeDays(), 0.001)
+		})
+	})
+
+	t.Run("start column date before creation", func(t *testing.T) {
+		issue := newIssue(dates[5], nil)
+
+		t.Run("time to start is 0", func(t *testing.T) {
+			assert.Equal(t, 0.0, issue.TimeToStartDays())
+		})
+	})
+}
+
 func assertColumnDates(t *testing.T, expected, actual IssuesDateColumns) {
 	for idx, expectedColumnDate := range expected {
 		assert.Equal(t, expectedColumnDate, actual[idx], "columnDate[%d] column: %s | was: %s - expected %s", idx, actual[idx].Date.String(), expectedColumnDate.Name, expectedColumnDate.Date.String())
@@ -4,8 +4,12 @@ import (
 	"fmt"
 	"math"
 	"time"
+
+	"github.com/3xcellent/github-metrics/logging"
 )
 
+var logger = logging.For("metrics")
+
 var AvailableMetrics = Metrics{
 	{Name: "columns", Description: "List of dates with Number of cards in each column for each date"},
 	{Name: "issues", Description: "List of issues and their development history and calculated dev and blocked time"},
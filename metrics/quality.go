@@ -0,0 +1,165 @@
+package metrics
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Anomaly kinds found when replaying an issue's events
+const (
+	DroppedIntoDone = "dropped into done"
+	ClosedNotMoved  = "closed but never moved"
+	SkippedColumns  = "skipped columns"
+	UnknownColumns  = "unknown columns"
+	InferredDates   = "inferred dates"
+)
+
+// AnomalyKinds - all anomaly kinds in the order they are reported
+var AnomalyKinds = []string{DroppedIntoDone, ClosedNotMoved, SkippedColumns, UnknownColumns, InferredDates}
+
+// Anomaly - a sign of board misuse that makes an issue's metrics unreliable
+type Anomaly struct {
+	Kind   string `json:"kind"`
+	Detail string `json:"detail"`
+}
+
+// observed - returns true if the column date was set from an event
+func (col IssuesDateColumn) observed() bool {
+	return !col.Date.IsZero() && !col.Inferred
+}
+
+// InferredColumns - returns the names of the start to end columns whose dates were filled
+// in by setEmptyColumnDates rather than observed in events
+func (i *Issue) InferredColumns() []string {
+	names := make([]string, 0)
+	for idx := i.StartColumnIndex; idx <= i.EndColumnIndex && idx < len(i.ColumnDates); idx++ {
+		if i.ColumnDates[idx].Inferred {
+			names = append(names, i.ColumnDates[idx].Name)
+		}
+	}
+	return names
+}
+
+// Anomalies - returns the data quality anomalies found while processing the issue's events
+func (i *Issue) Anomalies() []Anomaly {
+	anomalies := make([]Anomaly, 0)
+	if len(i.ColumnDates) <= i.EndColumnIndex {
+		return anomalies
+	}
+	endColumn := i.ColumnDates[i.EndColumnIndex]
+
+	if i.movedToColumn && i.firstColumnIndex >= i.EndColumnIndex {
+		anomalies = append(anomalies, Anomaly{
+			Kind:   DroppedIntoDone,
+			Detail: fmt.Sprintf("added to %q", i.ColumnDates[i.firstColumnIndex].Name),
+		})
+	} else if skipped := i.skippedColumns(); len(skipped) > 0 {
+		anomalies = append(anomalies, Anomaly{Kind: SkippedColumns, Detail: strings.Join(skipped, ", ")})
+	}
+
+	if !i.ClosedAt.IsZero() && !endColumn.observed() {
+		anomalies = append(anomalies, Anomaly{
+			Kind:   ClosedNotMoved,
+			Detail: fmt.Sprintf("closed %s without entering %q", i.ClosedAt.Format("01/02/06"), endColumn.Name),
+		})
+	}
+
+	if len(i.unknownColumns) > 0 {
+		anomalies = append(anomalies, Anomaly{Kind: UnknownColumns, Detail: strings.Join(distinct(i.unknownColumns), ", ")})
+	}
+
+	if inferred := i.InferredColumns(); len(inferred) > 0 {
+		anomalies = append(anomalies, Anomaly{Kind: InferredDates, Detail: strings.Join(inferred, ", ")})
+	}
+	return anomalies
+}
+
+// skippedColumns - returns the names of columns after the first column the card was in
+// that have no observed date although a later column does
+func (i *Issue) skippedColumns() []string {
+	skipped := make([]string, 0)
+	if !i.movedToColumn {
+		return skipped
+	}
+	lastObserved := -1
+	for idx := i.EndColumnIndex; idx >= i.StartColumnIndex; idx-- {
+		if i.ColumnDates[idx].observed() {
+			lastObserved = idx
+			break
+		}
+	}
+	for idx := i.StartColumnIndex; idx < lastObserved; idx++ {
+		if idx > i.firstColumnIndex && !i.ColumnDates[idx].observed() {
+			skipped = append(skipped, i.ColumnDates[idx].Name)
+		}
+	}
+	return skipped
+}
+
+func distinct(values []string) []string {
+	found := map[string]bool{}
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if !found[v] {
+			found[v] = true
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// QualityReport - board level summary of the anomalies found on a set of issues
+type QualityReport struct {
+	Issues              int            `json:"issues"`
+	IssuesWithAnomalies int            `json:"issuesWithAnomalies"`
+	ObservedDates       int            `json:"observedDates"`
+	TotalDates          int            `json:"totalDates"`
+	Counts              map[string]int `json:"counts"`
+}
+
+// Quality - returns the QualityReport of the issues
+func (issues Issues) Quality() QualityReport {
+	report := QualityReport{Counts: map[string]int{}}
+	for _, issue := range issues {
+		report.Issues++
+		anomalies := issue.Anomalies()
+		if len(anomalies) > 0 {
+			report.IssuesWithAnomalies++
+		}
+		for _, anomaly := range anomalies {
+			report.Counts[anomaly.Kind]++
+		}
+		for idx := issue.StartColumnIndex; idx <= issue.EndColumnIndex && idx < len(issue.ColumnDates); idx++ {
+			report.TotalDates++
+			if issue.ColumnDates[idx].observed() {
+				report.ObservedDates++
+			}
+		}
+	}
+	return report
+}
+
+// Confidence - returns the percentage (0-100) of start to end column dates that were observed
+// in events, rather than inferred or missing; 100 when there are no dates
+func (r QualityReport) Confidence() float64 {
+	if r.TotalDates == 0 {
+		return 100
+	}
+	return float64(r.ObservedDates) / float64(r.TotalDates) * 100
+}
+
+// Values - returns csv rows (with headers) of the report
+func (r QualityReport) Values() [][]string {
+	rows := [][]string{
+		{"Measure", "Value"},
+		{"Issues", fmt.Sprint(r.Issues)},
+		{"Issues With Anomalies", fmt.Sprint(r.IssuesWithAnomalies)},
+	}
+	for _, kind := range AnomalyKinds {
+		rows = append(rows, []string{strings.Title(kind), fmt.Sprint(r.Counts[kind])})
+	}
+	return append(rows,
+		[]string{"Observed Dates", fmt.Sprintf("%d/%d", r.ObservedDates, r.TotalDates)},
+		[]string{"Confidence", fmt.Sprintf("%.0f%%", r.Confidence())},
+	)
+}
@@ -0,0 +1,96 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/3xcellent/github-metrics/models"
+	"github.com/3xcellent/github-metrics/tools/testhelpers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIssueMetric_Anomalies(t *testing.T) {
+	dates := testhelpers.NewDates(4)
+	newIssue := func(events models.IssueEvents) Issue {
+		cols := testhelpers.NewProjectColumns(4)
+		issue := Issue{
+			Issue:            testhelpers.NewIssue(),
+			ProjectID:        42,
+			StartColumnIndex: 0,
+			EndColumnIndex:   3,
+			ColumnDates: IssuesDateColumns{
+				{ProjectColumn: &cols[0]},
+				{ProjectColumn: &cols[1]},
+				{ProjectColumn: &cols[2]},
+				{ProjectColumn: &cols[3]},
+			},
+		}
+		issue.Events = events
+		issue.ProcessIssueEvents()
+		return issue
+	}
+
+	t.Run("issue moved through every column has no anomalies", func(t *testing.T) {
+		issue := newIssue(models.IssueEvents{
+			{Type: models.AddedToProject, CreatedAt: dates[0], ColumnName: "col 0"},
+			{Type: models.MovedColumns, CreatedAt: dates[1], ColumnName: "col 1"},
+			{Type: models.MovedColumns, CreatedAt: dates[2], ColumnName: "col 2"},
+			{Type: models.MovedColumns, CreatedAt: dates[3], ColumnName: "col 3"},
+		})
+		assert.Empty(t, issue.Anomalies())
+	})
+
+	t.Run("card dropped into done", func(t *testing.T) {
+		issue := newIssue(models.IssueEvents{
+			{Type: models.AddedToProject, CreatedAt: dates[0], ColumnName: "col 3"},
+		})
+		assert.Equal(t, []Anomaly{
+			{Kind: DroppedIntoDone, Detail: `added to "col 3"`},
+			{Kind: InferredDates, Detail: "col 0, col 1, col 2"},
+		}, issue.Anomalies())
+	})
+
+	t.Run("columns skipped and unknown columns", func(t *testing.T) {
+		issue := newIssue(models.IssueEvents{
+			{Type: models.AddedToProject, CreatedAt: dates[0], ColumnName: "col 0"},
+			{Type: models.MovedColumns, CreatedAt: dates[1], ColumnName: "Old Column"},
+			{Type: models.MovedColumns, CreatedAt: dates[2], ColumnName: "col 3"},
+		})
+		assert.Equal(t, []Anomaly{
+			{Kind: SkippedColumns, Detail: "col 1, col 2"},
+			{Kind: UnknownColumns, Detail: "Old Column"},
+			{Kind: InferredDates, Detail: "col 1, col 2"},
+		}, issue.Anomalies())
+	})
+
+	t.Run("issue closed but never moved", func(t *testing.T) {
+		issue := newIssue(models.IssueEvents{
+			{Type: models.AddedToProject, CreatedAt: dates[0], ColumnName: "col 0"},
+			{Type: models.Closed, CreatedAt: dates[2]},
+		})
+		assert.Equal(t, []Anomaly{
+			{Kind: ClosedNotMoved, Detail: `closed 02/05/01 without entering "col 3"`},
+			{Kind: InferredDates, Detail: "col 1, col 2, col 3"},
+		}, issue.Anomalies())
+	})
+
+	t.Run("quality report of issues", func(t *testing.T) {
+		issues := Issues{
+			newIssue(models.IssueEvents{
+				{Type: models.AddedToProject, CreatedAt: dates[0], ColumnName: "col 0"},
+				{Type: models.MovedColumns, CreatedAt: dates[1], ColumnName: "col 1"},
+				{Type: models.MovedColumns, CreatedAt: dates[2], ColumnName: "col 2"},
+				{Type: models.MovedColumns, CreatedAt: dates[3], ColumnName: "col 3"},
+			}),
+			newIssue(models.IssueEvents{
+				{Type: models.AddedToProject, CreatedAt: dates[0], ColumnName: "col 3"},
+			}),
+		}
+		report := issues.Quality()
+		assert.Equal(t, 2, report.Issues)
+		assert.Equal(t, 1, report.IssuesWithAnomalies)
+		assert.Equal(t, 1, report.Counts[DroppedIntoDone])
+		assert.Equal(t, 5, report.ObservedDates)
+		assert.Equal(t, 8, report.TotalDates)
+		assert.Equal(t, 62.5, report.Confidence())
+	})
+}
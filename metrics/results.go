@@ -0,0 +1,104 @@
+package metrics
+
+import (
+	"math"
+	"time"
+
+	"github.com/3xcellent/github-metrics/models"
+)
+
+// IssueResult - typed result of the issues metric for a single issue, see docs/output.md
+type IssueResult struct {
+	Number          int                `json:"number"`
+	Repo            string             `json:"repo"`
+	Type            string             `json:"type"`
+	Title           string             `json:"title"`
+	ColumnDates     []ColumnDateResult `json:"columnDates"`
+	DevelopmentDays float64            `json:"developmentDays"`
+	Feature         bool               `json:"feature"`
+	Blocked         bool               `json:"blocked"`
+	BlockedDays     float64            `json:"blockedDays"`
+	Created         time.Time          `json:"created"`
+	LeadTimeDays    float64            `json:"leadTimeDays"`
+	TimeToStartDays float64            `json:"timeToStartDays"`
+}
+
+// ColumnDateResult - the date an issue entered a column
+type ColumnDateResult struct {
+	Column   string    `json:"column"`
+	Date     time.Time `json:"date"`
+	Inferred bool      `json:"inferred"`
+}
+
+// Result - returns the typed result for a single issue, matching the csv Values
+func (i *Issue) Result() IssueResult {
+	result := IssueResult{
+		Number:          i.Number,
+		Repo:            i.RepoName,
+		Type:            i.Type,
+		Title:           i.Title,
+		ColumnDates:     make([]ColumnDateResult, 0, i.EndColumnIndex-i.StartColumnIndex+1),
+		DevelopmentDays: i.CalcDays(),
+		Feature:         i.IsFeature,
+		Blocked:         math.Ceil(float64(i.TotalTimeBlocked/time.Hour/24)) > 0,
+		BlockedDays:     days(i.TotalTimeBlocked),
+		Created:         i.CreatedDate(),
+		LeadTimeDays:    i.LeadTimeDays(),
+		TimeToStartDays: i.TimeToStartDays(),
+	}
+	for idx := i.StartColumnIndex; idx <= i.EndColumnIndex; idx++ {
+		result.ColumnDates = append(result.ColumnDates, ColumnDateResult{
+			Column:   i.ColumnDates[idx].Name,
+			Date:     i.ColumnDates[idx].Date,
+			Inferred: i.ColumnDates[idx].Inferred,
+		})
+	}
+	return result
+}
+
+// Results - returns the typed results of the issues
+func (issues Issues) Results() []IssueResult {
+	results := make([]IssueResult, 0, len(issues))
+	for _, issue := range issues {
+		results = append(results, issue.Result())
+	}
+	return results
+}
+
+// AnomalyResult - typed result of the quality metric for a single anomaly of an issue
+type AnomalyResult struct {
+	Number int    `json:"number"`
+	Repo   string `json:"repo"`
+	Title  string `json:"title"`
+	Anomaly
+}
+
+// PullRequestResult - typed result of the pull_requests command for a single closed pull request
+type PullRequestResult struct {
+	Repo               string    `json:"repo"`
+	IssueNumber        int       `json:"issueNumber"`
+	CreatedAt          time.Time `json:"createdAt"`
+	CreatedBy          string    `json:"createdBy"`
+	Group              string    `json:"group"`
+	ClosedAt           time.Time `json:"closedAt"`
+	RequestedReviewers []string  `json:"requestedReviewers"`
+	DaysOpen           float64   `json:"daysOpen"`
+}
+
+// NewPullRequestResult - returns the typed result for a pull request
+func NewPullRequestResult(pr models.PullRequest, repoName string, issueNumber int, group string) PullRequestResult {
+	reviewers := pr.RequestedReviewers
+	if reviewers == nil {
+		reviewers = []string{}
+	}
+	return PullRequestResult{
+		Repo:               repoName,
+		IssueNumber:        issueNumber,
+		CreatedAt:          pr.CreatedAt,
+		CreatedBy:          pr.CreatedByUser,
+		Group:              group,
+		ClosedAt:           pr.ClosedAt,
+		RequestedReviewers: reviewers,
+		DaysOpen:           days(pr.ClosedAt.Sub(pr.CreatedAt)),
+	}
+}
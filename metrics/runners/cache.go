@@ -0,0 +1,136 @@
+package runners
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/3xcellent/github-metrics/models"
+)
+
+// CachingClient - a Client that keeps every response of Client, so runners of several metrics of the same board
+// fetch its data once; errors are not kept
+type CachingClient struct {
+	Client Client
+
+	mu        sync.Mutex
+	responses map[string]interface{}
+}
+
+var _ Client = new(CachingClient)
+
+// NewCachingClient - returns a CachingClient of client
+func NewCachingClient(client Client) *CachingClient {
+	return &CachingClient{Client: client, responses: make(map[string]interface{})}
+}
+
+// cached - returns the response kept for key, or the response of fetch, kept when it succeeds
+func (c *CachingClient) cached(key string, fetch func() (interface{}, error)) (interface{}, error) {
+	c.mu.Lock()
+	response, found := c.responses[key]
+	c.mu.Unlock()
+	if found {
+		return response, nil
+	}
+
+	response, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.responses[key] = response
+	c.mu.Unlock()
+	return response, nil
+}
+
+// GetIssue - returns the issue, fetched once
+func (c *CachingClient) GetIssue(ctx context.Context, repoOwner, repoName string, issueNumber int) (models.Issue, error) {
+	response, err := c.cached(fmt.Sprintf("issue/%s/%s/%d", repoOwner, repoName, issueNumber), func() (interface{}, error) {
+		return c.Client.GetIssue(ctx, repoOwner, repoName, issueNumber)
+	})
+	if err != nil {
+		return models.Issue{}, err
+	}
+	return response.(models.Issue), nil
+}
+
+// GetProject - returns the project, fetched once
+func (c *CachingClient) GetProject(ctx context.Context, projectID int64) (models.Project, error) {
+	response, err := c.cached(fmt.Sprintf("project/%d", projectID), func() (interface{}, error) {
+		return c.Client.GetProject(ctx, projectID)
+	})
+	if err != nil {
+		return models.Project{}, err
+	}
+	return response.(models.Project), nil
+}
+
+// GetProjects - returns the projects of the owner, fetched once
+func (c *CachingClient) GetProjects(ctx context.Context, owner string) (models.Projects, error) {
+	response, err := c.cached("projects/"+owner, func() (interface{}, error) {
+		return c.Client.GetProjects(ctx, owner)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response.(models.Projects), nil
+}
+
+// GetProjectColumns - returns the columns of the project, fetched once
+func (c *CachingClient) GetProjectColumns(ctx context.Context, projectID int64) (models.ProjectColumns, error) {
+	response, err := c.cached(fmt.Sprintf("columns/%d", projectID), func() (interface{}, error) {
+		return c.Client.GetProjectColumns(ctx, projectID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response.(models.ProjectColumns), nil
+}
+
+// GetPullRequests - returns the pull requests of the repo, fetched once
+func (c *CachingClient) GetPullRequests(ctx context.Context, repoOwner, repoName string) (models.PullRequests, error) {
+	response, err := c.cached(fmt.Sprintf("pulls/%s/%s", repoOwner, repoName), func() (interface{}, error) {
+		return c.Client.GetPullRequests(ctx, repoOwner, repoName)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response.(models.PullRequests), nil
+}
+
+// GetIssues - returns the issues of the repos between the dates, fetched once
+func (c *CachingClient) GetIssues(ctx context.Context, repoOwner string, reposNames []string, beginDate, endDate time.Time) (models.Issues, error) {
+	key := fmt.Sprintf("issues/%s/%s/%d/%d", repoOwner, strings.Join(reposNames, ","), beginDate.UnixNano(), endDate.UnixNano())
+	response, err := c.cached(key, func() (interface{}, error) {
+		return c.Client.GetIssues(ctx, repoOwner, reposNames, beginDate, endDate)
+	})
+	if err != nil {
+		return nil, err
+	}
+	// runners set the events of the issues returned, so each gets its own copy
+	return append(models.Issues{}, response.(models.Issues)...), nil
+}
+
+// GetIssueEvents - returns the events of the issue, fetched once
+func (c *CachingClient) GetIssueEvents(ctx context.Context, repoOwner, repoName string, issueNumber int) (models.IssueEvents, error) {
+	response, err := c.cached(fmt.Sprintf("events/%s/%s/%d", repoOwner, repoName, issueNumber), func() (interface{}, error) {
+		return c.Client.GetIssueEvents(ctx, repoOwner, repoName, issueNumber)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response.(models.IssueEvents), nil
+}
+
+// GetReposFromProjectColumn - returns the repos of the project column, fetched once
+func (c *CachingClient) GetReposFromProjectColumn(ctx context.Context, columnID int64) (models.Repositories, error) {
+	response, err := c.cached(fmt.Sprintf("repos/%d", columnID), func() (interface{}, error) {
+		return c.Client.GetReposFromProjectColumn(ctx, columnID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response.(models.Repositories), nil
+}
@@ -0,0 +1,66 @@
+package runners_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/3xcellent/github-metrics/metrics/runners"
+	"github.com/3xcellent/github-metrics/metrics/runners/runnersfakes"
+	"github.com/3xcellent/github-metrics/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCachingClient(t *testing.T) {
+	begin := time.Date(2001, 2, 1, 0, 0, 0, 0, time.Local)
+	end := begin.AddDate(0, 1, 0)
+
+	t.Run("fetches each response once", func(t *testing.T) {
+		fakeClient := new(runnersfakes.FakeClient)
+		fakeClient.GetIssuesReturns(models.Issues{{Number: 1}}, nil)
+		fakeClient.GetIssueEventsReturns(models.IssueEvents{{ColumnName: "col"}}, nil)
+		object := runners.NewCachingClient(fakeClient)
+
+		for i := 0; i < 2; i++ {
+			issues, err := object.GetIssues(testCtx, "owner", []string{"repo"}, begin, end)
+			require.NoError(t, err)
+			assert.Equal(t, models.Issues{{Number: 1}}, issues)
+			_, err = object.GetIssueEvents(testCtx, "owner", "repo", 1)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 1, fakeClient.GetIssuesCallCount())
+		assert.Equal(t, 1, fakeClient.GetIssueEventsCallCount())
+
+		_, err := object.GetIssues(testCtx, "owner", []string{"repo"}, begin, end.AddDate(0, 0, 1))
+		require.NoError(t, err)
+		assert.Equal(t, 2, fakeClient.GetIssuesCallCount())
+	})
+
+	t.Run("returns copies of issues", func(t *testing.T) {
+		fakeClient := new(runnersfakes.FakeClient)
+		fakeClient.GetIssuesReturns(models.Issues{{Number: 1}}, nil)
+		object := runners.NewCachingClient(fakeClient)
+
+		issues, err := object.GetIssues(testCtx, "owner", []string{"repo"}, begin, end)
+		require.NoError(t, err)
+		issues[0].Events = models.IssueEvents{{ColumnName: "col"}}
+
+		issues, err = object.GetIssues(testCtx, "owner", []string{"repo"}, begin, end)
+		require.NoError(t, err)
+		assert.Empty(t, issues[0].Events)
+	})
+
+	t.Run("does not keep errors", func(t *testing.T) {
+		fakeClient := new(runnersfakes.FakeClient)
+		fakeClient.GetProjectReturnsOnCall(0, models.Project{}, errors.New("rate limited"))
+		fakeClient.GetProjectReturnsOnCall(1, models.Project{Name: "Board"}, nil)
+		object := runners.NewCachingClient(fakeClient)
+
+		_, err := object.GetProject(testCtx, 1)
+		assert.Error(t, err)
+		project, err := object.GetProject(testCtx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, "Board", project.Name)
+	})
+}
@@ -8,7 +8,6 @@ import (
 	"github.com/3xcellent/github-metrics/config"
 	"github.com/3xcellent/github-metrics/metrics"
 	"github.com/3xcellent/github-metrics/models"
-	"github.com/sirupsen/logrus"
 )
 
 // ColumnsRunner - contains all data needed to run and maintain state for the Columns Metric
@@ -25,6 +24,7 @@ func NewColumnsRunner(metricsCfg config.RunConfig, client Client) *ColumnsRunner
 		Runner: NewBaseRunner(metricsCfg, client),
 		Cols:   metrics.NewDateColumnMap(metricsCfg.StartDate, metricsCfg.EndDate),
 	}
+	m.MetricName = "columns"
 
 	return &m
 }
@@ -42,16 +42,16 @@ func (r *ColumnsRunner) Values() [][]string {
 	rows := make([][]string, 0)
 
 	if !r.NoHeaders {
-		logrus.Debugf("option: headers")
+		logger.Debugf("option: headers")
 		rows = append(rows, r.Headers())
 	}
 
 	for currentDate := r.StartDate; currentDate.Before(r.EndDate); currentDate = currentDate.AddDate(0, 0, 1) {
-		logrus.Debugf("date: %s | %d -> %d", metrics.DateKey(currentDate), r.StartColumnIndex, r.EndColumnIndex)
+		logger.Debugf("date: %s | %d -> %d", metrics.DateKey(currentDate), r.StartColumnIndex, r.EndColumnIndex)
 		dateRow := []string{metrics.DateKey(currentDate)}
 		for i := r.StartColumnIndex; i <= r.EndColumnIndex; i++ {
 			appendVal := "0"
-			val, found := r.Cols.DateColumn(currentDate, r.ColumnNames[i-(r.EndColumnIndex-r.StartColumnIndex)])
+			val, found := r.Cols.DateColumn(currentDate, r.ColumnNames[i-r.StartColumnIndex])
 			if found {
 				appendVal = strconv.Itoa(val)
 			}
@@ -60,13 +60,32 @@ func (r *ColumnsRunner) Values() [][]string {
 		}
 		rows = append(rows, dateRow)
 	}
-	logrus.Debugf("\treturning RowValues: %s", rows)
+	logger.Debugf("\treturning RowValues: %s", rows)
 	return rows
 }
 
+// ColumnsMetrics - returns the typed number of issues in each column for each date
+func (r *ColumnsRunner) ColumnsMetrics() []metrics.ColumnsMetric {
+	results := make([]metrics.ColumnsMetric, 0)
+	for currentDate := r.StartDate; currentDate.Before(r.EndDate); currentDate = currentDate.AddDate(0, 0, 1) {
+		result := metrics.ColumnsMetric{Date: currentDate, ColumnAmounts: make(metrics.ColumnAmounts, 0, len(r.ColumnNames))}
+		for _, name := range r.ColumnNames {
+			amount, _ := r.Cols.DateColumn(currentDate, name)
+			result.ColumnAmounts = append(result.ColumnAmounts, metrics.ColumnAmount{Name: name, Amount: amount})
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// Results - returns the typed results of the run, see ColumnsMetrics
+func (r *ColumnsRunner) Results() interface{} {
+	return r.ColumnsMetrics()
+}
+
 // Run - Runs Columns Mwtric (gathers data from github and processes repos, issues, and events)
 func (r *ColumnsRunner) Run(ctx context.Context) error {
-	logrus.Debug("Starting ColumnsRunner")
+	logger.Debug("Starting ColumnsRunner")
 	r.Debug()
 	ghIssues, _, err := r.GetIssuesAndColumns(ctx)
 	if err != nil {
@@ -81,16 +100,12 @@ func (r *ColumnsRunner) Run(ctx context.Context) error {
 			IsFeature: metrics.HasFeatureLabel(ghIssue.Labels),
 		}
 
-		logrus.Debugf("getting events for issue: %s/%d", issue.RepoName, issue.Number)
-		events, err := r.Client.GetIssueEvents(ctx, issue.Owner, issue.RepoName, issue.Number)
-		logrus.Debugf("\tevents found: %d", len(events))
-		if err != nil {
-			return err
-		}
-
-		r.processIssueEvents(events)
+		logger.Debugf("processing %d events for issue: %s/%d", len(ghIssue.Events), issue.RepoName, issue.Number)
+		r.processIssueEvents(ghIssue.Events)
 		issues = append(issues, issue)
 	}
+	r.warnUnmappedColumns()
+
 	if r.after != nil {
 		err = r.after(r.Values())
 		if err != nil {
@@ -101,59 +116,77 @@ func (r *ColumnsRunner) Run(ctx context.Context) error {
 }
 
 func (r *ColumnsRunner) processIssueEvents(events models.IssueEvents) {
-	logrus.Debugf("ColumnsRunner.ProcessEvents: %d", len(events))
+	logger.Debugf("ColumnsRunner.ProcessEvents: %d", len(events))
 	shouldIncludeData := false
 	issueDateMap := metrics.DateColMap{}
 
 	var prevDate time.Time
 	var prevColumn string
+	var projectCardID int64
+	endColumn := r.ColumnMapper.Resolve(r.EndColumn)
 
-	for eventIdx, event := range events {
+	// fills in prevColumn from prevDate until the date provided
+	fillUntil := func(date time.Time) {
+		if prevDate.IsZero() {
+			return
+		}
+		for fillDate := prevDate; fillDate.Before(date); fillDate = fillDate.AddDate(0, 0, 1) {
+			issueDateMap[metrics.DateKey(fillDate)] = map[string]int{prevColumn: 1}
+		}
+	}
+
+	for _, event := range events {
 		createdAt := event.CreatedAt
+		eventDate := time.Date(createdAt.Year(), createdAt.Month(), createdAt.Day(), 0, 0, 0, 0, createdAt.Location())
+		if !metrics.IsProjectCardEvent(event, r.ProjectID, projectCardID) {
+			logger.Debugf("Event @ %s: ignoring %s for project %d card %d", event.CreatedAt.String(), event.Type, event.ProjectID, event.ProjectCardID)
+			continue
+		}
+
 		switch event.Type {
 		case models.AddedToProject:
-			shouldIncludeData = event.ProjectID == r.ProjectID
+			shouldIncludeData = true
+			projectCardID = event.ProjectCardID
 
 			if event.ColumnName == "" {
-				logrus.Warn("ProjectCard ColumnName not set.")
+				logger.Warn("ProjectCard ColumnName not set.")
 				continue
 			}
-			logrus.Debugf("Event @ %s: created %d - %s", event.CreatedAt.String(), event.ProjectID, event.ColumnName)
+			logger.Debugf("Event @ %s: created %d - %s", event.CreatedAt.String(), event.ProjectID, event.ColumnName)
 			fallthrough // Must fallthrough to MovedColumns for handling of case where card is dropped into column, and has not moved; expecting GetColumnName to be set
 		case models.MovedColumns:
-			eventDate := time.Date(createdAt.Year(), createdAt.Month(), createdAt.Day(), 0, 0, 0, 0, createdAt.Location())
-			logrus.Debugf("Event @ %s: setting column to \"%s\"", metrics.DateKey(eventDate), event.ColumnName)
+			columnName, found := r.ColumnMapper.Lookup(event.ColumnName)
+			if !found {
+				logger.Debugf("Event @ %s: ignoring unmapped column \"%s\"", metrics.DateKey(eventDate), event.ColumnName)
+				continue
+			}
+			logger.Debugf("Event @ %s: setting column to \"%s\"", metrics.DateKey(eventDate), columnName)
 
 			// if prevDate was set, fill in dates between the prevDate and this date.
-			if !prevDate.IsZero() && prevDate.Before(eventDate) {
-				fillDate := prevDate
-				for fillDate.Before(eventDate) {
-					issueDateMap[metrics.DateKey(fillDate)] = map[string]int{prevColumn: 1}
-					fillDate = fillDate.AddDate(0, 0, 1)
-				}
-			}
+			fillUntil(eventDate)
 
-			issueDateMap[metrics.DateKey(eventDate)] = map[string]int{event.ColumnName: 1}
+			issueDateMap[metrics.DateKey(eventDate)] = map[string]int{columnName: 1}
 
 			// set for next MovedColumns event to check
 			prevDate = eventDate
-			prevColumn = event.ColumnName
+			prevColumn = columnName
+		case models.RemovedFromProject:
+			logger.Debugf("Event @ %s: removed from project %d", event.CreatedAt.String(), event.ProjectID)
+			fillUntil(eventDate)
+			prevDate = time.Time{}
+			prevColumn = ""
 		case models.Labeled:
-			logrus.Debugf("Event @ %s: labeled %s", event.CreatedAt.String(), event.Label)
+			logger.Debugf("Event @ %s: labeled %s", event.CreatedAt.String(), event.Label)
 		case models.Unlabeled:
-			logrus.Debugf("Event @ %s: unlabeled %s", event.CreatedAt.String(), event.Label)
+			logger.Debugf("Event @ %s: unlabeled %s", event.CreatedAt.String(), event.Label)
 		default:
-			logrus.Debugf("Event @ %s: \"%s\"", event.CreatedAt.String(), event.Event)
+			logger.Debugf("Event @ %s: \"%s\"", event.CreatedAt.String(), event.Event)
 		}
+	}
 
-		// account for issues not done yet by 'filling-in' date ColumnsRunner until the endDate
-		if eventIdx == len(events)-1 && prevColumn != r.EndColumn && !prevDate.IsZero() {
-			fillDate := prevDate.AddDate(0, 0, 1)
-			for fillDate.Before(r.EndDate) {
-				issueDateMap[metrics.DateKey(fillDate)] = map[string]int{prevColumn: 1}
-				fillDate = fillDate.AddDate(0, 0, 1)
-			}
-		}
+	// account for issues not done yet by 'filling-in' date ColumnsRunner until the endDate
+	if prevColumn != endColumn {
+		fillUntil(r.EndDate)
 	}
 
 	if len(issueDateMap) == 0 {
@@ -166,7 +199,7 @@ func (r *ColumnsRunner) processIssueEvents(events models.IssueEvents) {
 					r.Cols[dateKey] = map[string]int{}
 				}
 				r.Cols[dateKey][colKey] += colVal
-				logrus.Debugf("dateMap updates : [%s][%s]: %d", dateKey, colKey, r.Cols[dateKey][colKey])
+				logger.Debugf("dateMap updates : [%s][%s]: %d", dateKey, colKey, r.Cols[dateKey][colKey])
 			}
 		}
 	}
@@ -7,11 +7,13 @@ import (
 	"time"
 
 	"github.com/3xcellent/github-metrics/config"
+	"github.com/3xcellent/github-metrics/metrics"
 	"github.com/3xcellent/github-metrics/metrics/runners"
 	"github.com/3xcellent/github-metrics/metrics/runners/runnersfakes"
 	"github.com/3xcellent/github-metrics/models"
 	"github.com/3xcellent/github-metrics/tools/testhelpers"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var (
@@ -108,5 +110,170 @@ func TestColumnsRunner_Headers(t *testing.T) {
 }
 
 func TestColumnsRunner_Values(t *testing.T) {
+	fakeClient := new(runnersfakes.FakeClient)
+	cols := testhelpers.NewProjectColumns(4)
+	runConfig := config.RunConfig{
+		ProjectID:   projectID,
+		StartDate:   startDate,
+		EndDate:     startDate.AddDate(0, 0, 3),
+		StartColumn: cols[1].Name,
+		EndColumn:   cols[3].Name,
+	}
+	object := runners.NewColumnsRunner(runConfig, fakeClient)
+	fakeClient.GetProjectReturns(project, nil)
+	fakeClient.GetProjectColumnsReturns(cols, nil)
+	fakeClient.GetReposFromProjectColumnReturns(repos, nil)
+	fakeClient.GetIssuesReturns(issues, nil)
+	fakeClient.GetIssueEventsReturns(models.IssueEvents{
+		{ProjectID: projectID, Type: models.AddedToProject, ColumnName: cols[1].Name, CreatedAt: startDate},
+		{ProjectID: projectID, Type: models.MovedColumns, ColumnName: cols[2].Name, CreatedAt: startDate.AddDate(0, 0, 1)},
+	}, nil)
+
+	err := object.Run(context.Background())
+	require.NoError(t, err)
+
+	t.Run("returns a row of column totals for each date when start column is not the first column", func(t *testing.T) {
+		expected := [][]string{
+			{"Date", cols[1].Name, cols[2].Name, cols[3].Name},
+			{"2001-02-03", "1", "0", "0"},
+			{"2001-02-04", "0", "1", "0"},
+			{"2001-02-05", "0", "1", "0"},
+		}
+		assert.Equal(t, expected, object.Values())
+	})
+}
+
+func TestColumnsRunner_Values_MultipleProjects(t *testing.T) {
+	const otherProjectID = int64(99)
+	fakeClient := new(runnersfakes.FakeClient)
+	cols := testhelpers.NewProjectColumns(3)
+	runConfig := config.RunConfig{
+		ProjectID: projectID,
+		StartDate: startDate,
+		EndDate:   startDate.AddDate(0, 0, 4),
+		EndColumn: cols[2].Name,
+	}
+	object := runners.NewColumnsRunner(runConfig, fakeClient)
+	fakeClient.GetProjectReturns(project, nil)
+	fakeClient.GetProjectColumnsReturns(cols, nil)
+	fakeClient.GetReposFromProjectColumnReturns(repos, nil)
+	fakeClient.GetIssuesReturns(models.Issues{
+		{Owner: repos[0].Owner, RepoName: repos[0].Name, Number: 1},
+		{Owner: repos[0].Owner, RepoName: repos[0].Name, Number: 2},
+		{Owner: repos[0].Owner, RepoName: repos[0].Name, Number: 3},
+		{Owner: repos[0].Owner, RepoName: repos[0].Name, Number: 4},
+	}, nil)
+	fakeClient.GetIssueEventsReturnsOnCall(0, models.IssueEvents{
+		// on both boards, done on the other board first
+		{ProjectID: projectID, ProjectCardID: 1, Type: models.AddedToProject, ColumnName: cols[0].Name, CreatedAt: startDate},
+		{ProjectID: otherProjectID, ProjectCardID: 2, Type: models.AddedToProject, ColumnName: cols[0].Name, CreatedAt: startDate},
+		{ProjectID: otherProjectID, ProjectCardID: 2, Type: models.MovedColumns, ColumnName: cols[2].Name, CreatedAt: startDate.AddDate(0, 0, 1)},
+		{ProjectID: projectID, ProjectCardID: 1, Type: models.MovedColumns, ColumnName: cols[1].Name, CreatedAt: startDate.AddDate(0, 0, 2)},
+	}, nil)
+	fakeClient.GetIssueEventsReturnsOnCall(1, models.IssueEvents{
+		// only on the other board
+		{ProjectID: otherProjectID, ProjectCardID: 3, Type: models.AddedToProject, ColumnName: cols[1].Name, CreatedAt: startDate},
+	}, nil)
+	fakeClient.GetIssueEventsReturnsOnCall(2, models.IssueEvents{
+		// removed from the board and re-added
+		{ProjectID: projectID, ProjectCardID: 4, Type: models.AddedToProject, ColumnName: cols[1].Name, CreatedAt: startDate},
+		{ProjectID: projectID, ProjectCardID: 4, Type: models.RemovedFromProject, CreatedAt: startDate.AddDate(0, 0, 1)},
+		{ProjectID: projectID, ProjectCardID: 5, Type: models.AddedToProject, ColumnName: cols[1].Name, CreatedAt: startDate.AddDate(0, 0, 3)},
+	}, nil)
+	fakeClient.GetIssueEventsReturnsOnCall(3, models.IssueEvents{
+		// without a project id, assumed to be on the board like the issues runner does
+		{Type: models.AddedToProject, ColumnName: cols[2].Name, CreatedAt: startDate.AddDate(0, 0, 2)},
+	}, nil)
+
+	err := object.Run(context.Background())
+	require.NoError(t, err)
+
+	t.Run("counts only events from the run's project, or without one, and the card while on the project", func(t *testing.T) {
+		expected := [][]string{
+			{"Date", cols[0].Name, cols[1].Name, cols[2].Name},
+			{"2001-02-03", "1", "1", "0"},
+			{"2001-02-04", "1", "0", "0"},
+			{"2001-02-05", "0", "1", "1"},
+			{"2001-02-06", "0", "2", "0"},
+		}
+		assert.Equal(t, expected, object.Values())
+	})
+}
 
+func TestColumnsRunner_Values_ColumnAliases(t *testing.T) {
+	fakeClient := new(runnersfakes.FakeClient)
+	cols := testhelpers.NewProjectColumns(4)
+	runConfig := config.RunConfig{
+		ProjectID: projectID,
+		StartDate: startDate,
+		EndDate:   startDate.AddDate(0, 0, 3),
+		EndColumn: cols[3].Name,
+		ColumnAliases: []config.ColumnAlias{
+			{Name: cols[1].Name, Aliases: []string{"Old Name"}},
+		},
+		Stages: []config.Stage{
+			{Name: "Finishing", Columns: []string{cols[2].Name, cols[3].Name}},
+		},
+	}
+	object := runners.NewColumnsRunner(runConfig, fakeClient)
+	fakeClient.GetProjectReturns(project, nil)
+	fakeClient.GetProjectColumnsReturns(cols, nil)
+	fakeClient.GetReposFromProjectColumnReturns(repos, nil)
+	fakeClient.GetIssuesReturns(models.Issues{
+		{Owner: repos[0].Owner, RepoName: repos[0].Name, Number: 1},
+	}, nil)
+	fakeClient.GetIssueEventsReturns(models.IssueEvents{
+		{ProjectID: projectID, Type: models.AddedToProject, ColumnName: "Old Name", CreatedAt: startDate},
+		{ProjectID: projectID, Type: models.MovedColumns, ColumnName: "Removed Column", CreatedAt: startDate.AddDate(0, 0, 1)},
+		{ProjectID: projectID, Type: models.MovedColumns, ColumnName: cols[2].Name, CreatedAt: startDate.AddDate(0, 0, 2)},
+	}, nil)
+
+	err := object.Run(context.Background())
+	require.NoError(t, err)
+
+	t.Run("counts aliased columns in their column and stage columns in their stage", func(t *testing.T) {
+		expected := [][]string{
+			{"Date", cols[0].Name, cols[1].Name, "Finishing"},
+			{"2001-02-03", "0", "1", "0"},
+			{"2001-02-04", "0", "1", "0"},
+			{"2001-02-05", "0", "0", "1"},
+		}
+		assert.Equal(t, expected, object.Values())
+	})
+
+	t.Run("uses the last column of the end stage to find repos", func(t *testing.T) {
+		require.Equal(t, 1, fakeClient.GetReposFromProjectColumnCallCount())
+		_, actColumnID := fakeClient.GetReposFromProjectColumnArgsForCall(0)
+		assert.Equal(t, cols[3].ID, actColumnID)
+	})
+
+	t.Run("reports column names not on the board", func(t *testing.T) {
+		assert.Equal(t, []string{"Removed Column"}, object.UnmappedColumns())
+	})
+}
+
+func TestColumnsRunner_Results(t *testing.T) {
+	fakeClient := new(runnersfakes.FakeClient)
+	cols := testhelpers.NewProjectColumns(2)
+	object := runners.NewColumnsRunner(config.RunConfig{
+		ProjectID: projectID,
+		StartDate: startDate,
+		EndDate:   startDate.AddDate(0, 0, 2),
+	}, fakeClient)
+	fakeClient.GetProjectReturns(project, nil)
+	fakeClient.GetProjectColumnsReturns(cols, nil)
+	fakeClient.GetReposFromProjectColumnReturns(repos, nil)
+	fakeClient.GetIssuesReturns(models.Issues{{Owner: repos[0].Owner, RepoName: repos[0].Name, Number: 1}}, nil)
+	fakeClient.GetIssueEventsReturns(models.IssueEvents{
+		{ProjectID: projectID, Type: models.AddedToProject, ColumnName: cols[1].Name, CreatedAt: startDate.AddDate(0, 0, 1)},
+	}, nil)
+	require.NoError(t, object.Run(context.Background()))
+
+	t.Run("returns typed column amounts for each date", func(t *testing.T) {
+		expected := []metrics.ColumnsMetric{
+			{Date: startDate, ColumnAmounts: metrics.ColumnAmounts{{Name: cols[0].Name, Amount: 0}, {Name: cols[1].Name, Amount: 0}}},
+			{Date: startDate.AddDate(0, 0, 1), ColumnAmounts: metrics.ColumnAmounts{{Name: cols[0].Name, Amount: 0}, {Name: cols[1].Name, Amount: 1}}},
+		}
+		assert.Equal(t, expected, object.Results())
+	})
 }
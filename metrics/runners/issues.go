@@ -4,10 +4,10 @@ import (
 	"context"
 	"errors"
 
+	"github.com/3xcellent/github-metrics/apperrors"
 	"github.com/3xcellent/github-metrics/config"
 	"github.com/3xcellent/github-metrics/metrics"
 	"github.com/3xcellent/github-metrics/models"
-	"github.com/sirupsen/logrus"
 )
 
 // IssuesRunner - contains all data needed to run and maintain state for the Issues Metric
@@ -21,7 +21,9 @@ type IssuesRunner struct {
 // NewIssuesRunner - returns metric runner for running the columns metric, requires a project id and client
 func NewIssuesRunner(metricsCfg config.RunConfig, client Client) *IssuesRunner {
 	m := IssuesRunner{
-		Runner: NewBaseRunner(metricsCfg, client),
+		Runner:      NewBaseRunner(metricsCfg, client),
+		IssueNumber: metricsCfg.IssueNumber,
+		RepoName:    metricsCfg.RepoName,
 	}
 
 	return &m
@@ -30,42 +32,71 @@ func NewIssuesRunner(metricsCfg config.RunConfig, client Client) *IssuesRunner {
 // Values - returns CSV data (rows and cols) as two-deimensional slice [][]string
 // * headers with be included unless ColumnsRunner.NoHeaders is true
 func (r *IssuesRunner) Values() [][]string {
-	// logrus.Debug("IssuesRunner.Values: %#v", r.Issues)
+	// logger.Debug("IssuesRunner.Values: %#v", r.Issues)
 
 	var rowColumns [][]string
 	if !r.NoHeaders && len(r.Issues) > 0 {
 		rowColumns = append(rowColumns, r.Issues[0].CSVHeaders())
 	}
+	for _, issue := range r.CompletedIssues() {
+		rowColumns = append(rowColumns, issue.Values())
+	}
+	return rowColumns
+}
+
+// Results - returns the typed results of the completed issues
+func (r *IssuesRunner) Results() interface{} {
+	return r.CompletedIssues().Results()
+}
+
+// CompletedIssues - returns the issues on the project that reached the end column within the run dates
+func (r *IssuesRunner) CompletedIssues() metrics.Issues {
+	completed := make(metrics.Issues, 0)
 	for _, issue := range r.Issues {
-		if issue.ProjectID == r.ProjectID &&
+		if issue.OnProject &&
 			issue.ColumnDates[r.EndColumnIndex].Date.After(r.StartDate) &&
 			issue.ColumnDates[r.EndColumnIndex].Date.Before(r.EndDate) {
 
 			if issue.CalcDays() > 0.01 {
-				issueValues := issue.Values()
-				rowColumns = append(rowColumns, issueValues)
+				completed = append(completed, issue)
 			}
 		}
 	}
-	return rowColumns
+	return completed
+}
+
+// Summary - returns the summary statistics of the completed issues
+func (r *IssuesRunner) Summary() metrics.IssuesSummary {
+	return r.CompletedIssues().Summary()
 }
 
 // Run - Runs Columns Mwtric (gathers data from github and processes repos, issues, and events)
 func (r *IssuesRunner) Run(ctx context.Context) error {
-	logrus.Debug("Starting IssuesRunner")
+	logger.Debug("Starting IssuesRunner")
 	r.Debug()
 
-	ghIssues, projectColumns, err := r.GetIssuesAndColumns(ctx)
+	err := r.processIssues(ctx)
 	if err != nil {
 		return err
 	}
 
-	dateCols := make(metrics.IssuesDateColumns, 0)
-	for _, col := range projectColumns {
-		dateCols = append(dateCols, metrics.IssuesDateColumn{ProjectColumn: &models.ProjectColumn{Name: col.Name, ID: col.ID}})
+	if r.after != nil {
+		err = r.after(r.Values())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// processIssues - gathers the project's issues and sets their column dates from their events
+func (r *IssuesRunner) processIssues(ctx context.Context) error {
+	ghIssues, projectColumns, err := r.GetIssuesAndColumns(ctx)
+	if err != nil {
+		return err
 	}
 
-	logrus.Debugf("dateCols: %#v", dateCols)
+	dateCols := newIssuesDateColumns(projectColumns)
 	for _, ghIssue := range ghIssues {
 		metricsIssue, err := r.newMetricsIssue(ctx, ghIssue, dateCols)
 		if err != nil {
@@ -74,14 +105,36 @@ func (r *IssuesRunner) Run(ctx context.Context) error {
 		metricsIssue.ProcessIssueEvents()
 		r.Issues = append(r.Issues, metricsIssue)
 	}
+	r.warnUnmappedColumns()
+	return nil
+}
 
-	if r.after != nil {
-		err = r.after(r.Values())
-		if err != nil {
-			return err
-		}
+// Explain - processes the events of the runner's RepoName and IssueNumber issue and returns how its metrics were calculated
+func (r *IssuesRunner) Explain(ctx context.Context) (metrics.Explanation, error) {
+	if r.RepoName == "" || r.IssueNumber == 0 {
+		return metrics.Explanation{}, apperrors.New(apperrors.Config, "repoName and issueNumber are required")
 	}
-	return nil
+	ghIssue, projectColumns, err := r.GetIssueAndColumns(ctx, r.RepoName, r.IssueNumber)
+	if err != nil {
+		return metrics.Explanation{}, err
+	}
+
+	issue, err := r.newMetricsIssue(ctx, ghIssue, newIssuesDateColumns(projectColumns))
+	if err != nil {
+		return metrics.Explanation{}, err
+	}
+	issue.ProcessIssueEvents()
+	r.Issues = metrics.Issues{issue}
+	return issue.Explain(), nil
+}
+
+func newIssuesDateColumns(projectColumns models.ProjectColumns) metrics.IssuesDateColumns {
+	dateCols := make(metrics.IssuesDateColumns, 0)
+	for _, col := range projectColumns {
+		dateCols = append(dateCols, metrics.IssuesDateColumn{ProjectColumn: &models.ProjectColumn{Name: col.Name, ID: col.ID, Index: col.Index}})
+	}
+	logger.Debugf("dateCols: %#v", dateCols)
+	return dateCols
 }
 
 func (r *IssuesRunner) newMetricsIssue(ctx context.Context, ghIssue models.Issue, dateColumns metrics.IssuesDateColumns) (metrics.Issue, error) {
@@ -90,6 +143,7 @@ func (r *IssuesRunner) newMetricsIssue(ctx context.Context, ghIssue models.Issue
 		Issue:            &ghIssue,
 		StartColumnIndex: r.StartColumnIndex,
 		EndColumnIndex:   r.EndColumnIndex,
+		ColumnMapper:     r.ColumnMapper,
 	}
 	issue.ProcessLabels(ghIssue.Labels)
 	dates, err := newDateColumns(dateColumns)
@@ -107,7 +161,7 @@ func newDateColumns(dateColumns metrics.IssuesDateColumns) (metrics.IssuesDateCo
 	}
 	newDateColumns := make(metrics.IssuesDateColumns, 0, len(dateColumns))
 	for _, dc := range dateColumns {
-		idc := metrics.IssuesDateColumn{ProjectColumn: &models.ProjectColumn{Name: dc.Name, ID: dc.ID}}
+		idc := metrics.IssuesDateColumn{ProjectColumn: &models.ProjectColumn{Name: dc.Name, ID: dc.ID, Index: dc.Index}}
 		newDateColumns = append(newDateColumns, idc)
 	}
 	return newDateColumns, nil
@@ -0,0 +1,89 @@
+package runners_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/3xcellent/github-metrics/config"
+	"github.com/3xcellent/github-metrics/metrics"
+	"github.com/3xcellent/github-metrics/metrics/runners"
+	"github.com/3xcellent/github-metrics/metrics/runners/runnersfakes"
+	"github.com/3xcellent/github-metrics/models"
+	"github.com/3xcellent/github-metrics/tools/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIssuesRunner_Explain(t *testing.T) {
+	t.Run("requires repo name and issue number", func(t *testing.T) {
+		object := runners.NewIssuesRunner(config.RunConfig{ProjectID: projectID}, new(runnersfakes.FakeClient))
+		_, err := object.Explain(context.Background())
+		assert.EqualError(t, err, "repoName and issueNumber are required")
+	})
+
+	fakeClient := new(runnersfakes.FakeClient)
+	cols := testhelpers.NewProjectColumns(4)
+	dates := testhelpers.NewDates(6)
+	object := runners.NewIssuesRunner(config.RunConfig{
+		ProjectID:   projectID,
+		Owner:       "owner",
+		RepoName:    repos[0].Name,
+		IssueNumber: 7,
+		StartColumn: cols[1].Name,
+		StartDate:   startDate,
+		EndDate:     startDate.AddDate(0, 0, 10),
+	}, fakeClient)
+
+	fakeClient.GetProjectReturns(project, nil)
+	fakeClient.GetProjectColumnsReturns(cols, nil)
+	fakeClient.GetIssueReturns(models.Issue{Owner: "owner", RepoName: repos[0].Name, Number: 7, Title: "disputed"}, nil)
+	fakeClient.GetIssueEventsReturns(models.IssueEvents{
+		{ProjectID: projectID, Type: models.AddedToProject, ColumnName: cols[0].Name, CreatedAt: dates[0]},
+		{ProjectID: projectID, Type: models.MovedColumns, ColumnName: cols[1].Name, CreatedAt: dates[1]},
+		{Type: models.Labeled, Label: "blocked", CreatedAt: dates[2]},
+		{Type: models.Unlabeled, Label: "blocked", CreatedAt: dates[3]},
+		{ProjectID: projectID, Type: models.MovedColumns, ColumnName: cols[0].Name, PreviousColumnName: cols[1].Name, CreatedAt: dates[4]},
+		{ProjectID: projectID, Type: models.MovedColumns, ColumnName: cols[3].Name, CreatedAt: dates[5]},
+	}, nil)
+
+	explanation, err := object.Explain(context.Background())
+	require.NoError(t, err)
+
+	t.Run("gets the issue from the flags", func(t *testing.T) {
+		require.Equal(t, 1, fakeClient.GetIssueCallCount())
+		_, actOwner, actRepo, actNumber := fakeClient.GetIssueArgsForCall(0)
+		assert.Equal(t, "owner", actOwner)
+		assert.Equal(t, repos[0].Name, actRepo)
+		assert.Equal(t, 7, actNumber)
+		assert.Equal(t, 0, fakeClient.GetIssuesCallCount())
+	})
+
+	t.Run("includes the event timeline", func(t *testing.T) {
+		assert.Equal(t, "owner/repo 1#7", explanation.Issue)
+		assert.Len(t, explanation.Events, 6)
+	})
+
+	t.Run("records each decision", func(t *testing.T) {
+		actions := make([]string, 0)
+		for _, d := range explanation.Decisions {
+			actions = append(actions, d.Action+" "+d.Column)
+		}
+		assert.Equal(t, []string{
+			"accepted col 0",
+			"accepted col 1",
+			"blocked ",
+			"unblocked ",
+			"ignored col 0",
+			"accepted col 3",
+			"inferred col 2",
+		}, actions)
+		assert.Equal(t, `backward move from "col 1"`, explanation.Decisions[4].Reason)
+	})
+
+	t.Run("includes blocked intervals and final values", func(t *testing.T) {
+		assert.Equal(t, []metrics.BlockedInterval{{From: dates[2], To: dates[3]}}, explanation.BlockedIntervals)
+		assert.Equal(t, 1.0, explanation.Values.BlockedDays)
+		assert.Equal(t, 4.0, explanation.Values.CycleTimeDays)
+		assert.Equal(t, metrics.ExplainedColumn{Column: cols[2].Name, Date: dates[5], Inferred: true}, explanation.ColumnDates[1])
+	})
+}
@@ -0,0 +1,92 @@
+package runners
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/3xcellent/github-metrics/config"
+	"github.com/3xcellent/github-metrics/metrics"
+)
+
+// QualityRunner - replays the events of a board's issues and reports data quality anomalies
+type QualityRunner struct {
+	*IssuesRunner
+}
+
+var _ MetricsRunner = new(QualityRunner)
+
+// NewQualityRunner - returns metric runner for linting the board data used by the issues and columns metrics
+func NewQualityRunner(metricsCfg config.RunConfig, client Client) *QualityRunner {
+	m := QualityRunner{
+		IssuesRunner: NewIssuesRunner(metricsCfg, client),
+	}
+	m.MetricName = "quality"
+
+	return &m
+}
+
+// Headers - returns list of headers column names
+func (r *QualityRunner) Headers() []string {
+	return []string{"Card #", "Team", "Description", "Anomaly", "Detail"}
+}
+
+// Values - returns a row for every anomaly of every issue on the project
+// * headers with be included unless QualityRunner.NoHeaders is true
+func (r *QualityRunner) Values() [][]string {
+	rows := make([][]string, 0)
+	if !r.NoHeaders {
+		rows = append(rows, r.Headers())
+	}
+	for _, issue := range r.ProjectIssues() {
+		for _, anomaly := range issue.Anomalies() {
+			rows = append(rows, []string{fmt.Sprint(issue.Number), issue.RepoName, issue.Title, anomaly.Kind, anomaly.Detail})
+		}
+	}
+	return rows
+}
+
+// Results - returns the typed anomalies of every issue on the project
+func (r *QualityRunner) Results() interface{} {
+	results := make([]metrics.AnomalyResult, 0)
+	for _, issue := range r.ProjectIssues() {
+		for _, anomaly := range issue.Anomalies() {
+			results = append(results, metrics.AnomalyResult{Number: issue.Number, Repo: issue.RepoName, Title: issue.Title, Anomaly: anomaly})
+		}
+	}
+	return results
+}
+
+// ProjectIssues - returns the issues on the project after processing their events
+func (r *QualityRunner) ProjectIssues() metrics.Issues {
+	issues := make(metrics.Issues, 0)
+	for _, issue := range r.Issues {
+		if issue.OnProject {
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+// Report - returns the board level quality report of the issues on the project
+func (r *QualityRunner) Report() metrics.QualityReport {
+	return r.ProjectIssues().Quality()
+}
+
+// Run - Runs Quality Metric (gathers data from github and replays issue events)
+func (r *QualityRunner) Run(ctx context.Context) error {
+	logger.Debug("Starting QualityRunner")
+	r.Debug()
+
+	err := r.processIssues(ctx)
+	if err != nil {
+		return err
+	}
+
+	if r.after != nil {
+		err = r.after(r.Values())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
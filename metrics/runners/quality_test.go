@@ -0,0 +1,61 @@
+package runners_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/3xcellent/github-metrics/config"
+	"github.com/3xcellent/github-metrics/metrics"
+	"github.com/3xcellent/github-metrics/metrics/runners"
+	"github.com/3xcellent/github-metrics/metrics/runners/runnersfakes"
+	"github.com/3xcellent/github-metrics/models"
+	"github.com/3xcellent/github-metrics/tools/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQualityRunner_Run(t *testing.T) {
+	fakeClient := new(runnersfakes.FakeClient)
+	cols := testhelpers.NewProjectColumns(3)
+	runConfig := config.RunConfig{
+		MetricName: "quality",
+		ProjectID:  projectID,
+		StartDate:  startDate,
+		EndDate:    startDate.AddDate(0, 0, 10),
+	}
+	runner, err := runners.New(runConfig, fakeClient)
+	require.NoError(t, err)
+	object := runner.(*runners.QualityRunner)
+
+	fakeClient.GetProjectReturns(project, nil)
+	fakeClient.GetProjectColumnsReturns(cols, nil)
+	fakeClient.GetReposFromProjectColumnReturns(repos, nil)
+	fakeClient.GetIssuesReturns(models.Issues{
+		{Owner: repos[0].Owner, RepoName: repos[0].Name, Number: 1, Title: "dropped"},
+		{Owner: repos[0].Owner, RepoName: repos[0].Name, Number: 2, Title: "not on board"},
+	}, nil)
+	fakeClient.GetIssueEventsReturnsOnCall(0, models.IssueEvents{
+		{ProjectID: projectID, Type: models.AddedToProject, ColumnName: cols[2].Name, CreatedAt: startDate.AddDate(0, 0, 1)},
+	}, nil)
+	fakeClient.GetIssueEventsReturnsOnCall(1, models.IssueEvents{
+		{Type: models.Closed, CreatedAt: startDate.AddDate(0, 0, 1)},
+	}, nil)
+
+	err = object.Run(context.Background())
+	require.NoError(t, err)
+
+	t.Run("lists anomalies of issues on the project", func(t *testing.T) {
+		expected := [][]string{
+			{"Card #", "Team", "Description", "Anomaly", "Detail"},
+			{"1", repos[0].Name, "dropped", metrics.DroppedIntoDone, `added to "col 2"`},
+			{"1", repos[0].Name, "dropped", metrics.InferredDates, "col 0, col 1"},
+		}
+		assert.Equal(t, expected, object.Values())
+	})
+
+	t.Run("reports board confidence", func(t *testing.T) {
+		report := object.Report()
+		assert.Equal(t, 1, report.Issues)
+		assert.InDelta(t, 33.3, report.Confidence(), 0.1)
+	})
+}
@@ -7,11 +7,16 @@ import (
 	"strings"
 	"time"
 
+	"github.com/3xcellent/github-metrics/apperrors"
 	"github.com/3xcellent/github-metrics/config"
+	"github.com/3xcellent/github-metrics/logging"
+	"github.com/3xcellent/github-metrics/metrics"
 	"github.com/3xcellent/github-metrics/models"
 	"github.com/sirupsen/logrus"
 )
 
+var logger = logging.For("runners")
+
 type afterFunc func([][]string) error
 
 // MetricsRunner is the main runner interface all runners must honor
@@ -19,6 +24,7 @@ type MetricsRunner interface {
 	Run(context.Context) error
 	RunName() string
 	Values() [][]string
+	Results() interface{}
 	Headers() []string
 	After(afterFunc)
 }
@@ -36,6 +42,17 @@ type Client interface {
 	GetReposFromProjectColumn(ctx context.Context, columnID int64) (models.Repositories, error)
 }
 
+// ProfileClients - the Client of each connection profile, by profile name
+type ProfileClients map[string]Client
+
+// Get - returns the Client of the profile, or client when the profile has none
+func (p ProfileClients) Get(profile string, client Client) Client {
+	if profileClient, found := p[profile]; found {
+		return profileClient
+	}
+	return client
+}
+
 // Runner - provides a metricsClient, and must honor the CSVRunner interface to allow
 // running metrics and running the afterFunc if set
 type Runner struct {
@@ -59,6 +76,7 @@ type Runner struct {
 	EndColumnIndex   int
 	EndColumnID      int64
 	ColumnNames      []string
+	ColumnMapper     *metrics.ColumnMapper
 }
 
 // After - sets the afterFunc to one provided
@@ -72,13 +90,21 @@ func New(metricsCfg config.RunConfig, client Client) (MetricsRunner, error) {
 		return NewColumnsRunner(metricsCfg, client), nil
 	case "issues":
 		return NewIssuesRunner(metricsCfg, client), nil
+	case "quality":
+		return NewQualityRunner(metricsCfg, client), nil
 	}
-	return nil, errors.New("runner name unkonwn")
+	return nil, apperrors.Errorf(apperrors.Config, "unknown metric %q, use columns, issues or quality", metricsCfg.MetricName)
 }
 
 // NewBaseRunner - creates the base runner from the config and set the client client
 func NewBaseRunner(metricsCfg config.RunConfig, client Client) *Runner {
-	logrus.Debugf("initializing new runner with %#v:", metricsCfg)
+	logger.WithFields(logrus.Fields{
+		"runConfig": metricsCfg.Name,
+		"metric":    metricsCfg.MetricName,
+		"projectID": metricsCfg.ProjectID,
+		"start":     metricsCfg.StartDate,
+		"end":       metricsCfg.EndDate,
+	}).Debug("initializing new runner")
 	return &Runner{
 		Client:      client,
 		MetricName:  metricsCfg.MetricName,
@@ -89,6 +115,8 @@ func NewBaseRunner(metricsCfg config.RunConfig, client Client) *Runner {
 		StartColumn: metricsCfg.StartColumn,
 		EndColumn:   metricsCfg.EndColumn,
 		NoHeaders:   metricsCfg.NoHeaders,
+
+		ColumnMapper: metrics.NewColumnMapper(metricsCfg.ColumnAliases, metricsCfg.Stages),
 	}
 }
 
@@ -97,35 +125,81 @@ var (
 	ErrEmptyProjectColumns = errors.New("cannot set indexes: ProjectColumns is empty")
 )
 
+// UnknownColumnError - a start or end column that is not a column, or stage, of the board
+type UnknownColumnError struct {
+	Column  string
+	Columns []string
+}
+
+func (e *UnknownColumnError) Error() string {
+	return fmt.Sprintf("column %q not found on the board, columns are: %s", e.Column, strings.Join(e.Columns, ", "))
+}
+
+// ErrorKind - unknown columns are errors of the config
+func (e *UnknownColumnError) ErrorKind() apperrors.Kind {
+	return apperrors.Config
+}
+
 // SetColumnParams - sets runner Start/EmdColumnIndec based ProjectColumns and runner.StartColumn/EndColumn values (from RunConfig)
-func (r *Runner) setColumnParams(projectColumns models.ProjectColumns) error {
+// returns the logical columns, after mapping column aliases and stages, the runner reports on
+func (r *Runner) setColumnParams(projectColumns models.ProjectColumns) (models.ProjectColumns, error) {
 	if len(projectColumns) == 0 {
-		return ErrEmptyProjectColumns
+		return nil, ErrEmptyProjectColumns
+	}
+	columns := r.ColumnMapper.LogicalColumns(projectColumns)
+	startColumn := r.ColumnMapper.Resolve(r.StartColumn)
+	endColumn := r.ColumnMapper.Resolve(r.EndColumn)
+	r.StartColumnIndex, r.EndColumnIndex = 0, len(columns)-1
+	startFound, endFound := r.StartColumn == "", r.EndColumn == ""
+	if startFound {
+		logger.Warnf("no start column set for %q, measuring from the first column %q", r.ProjectName, columns[0].Name)
+	}
+	if endFound {
+		logger.Warnf("no end column set for %q, measuring to the last column %q", r.ProjectName, columns[len(columns)-1].Name)
 	}
 	colNames := make([]string, 0)
-	for i, col := range projectColumns {
+	for i, col := range columns {
 		colNames = append(colNames, col.Name)
-		if col.Name == r.StartColumn {
+		if !startFound && col.Name == startColumn {
 			r.StartColumnIndex = i
-			logrus.Debugf("\t index of %q: %d", r.StartColumn, r.StartColumnIndex)
+			startFound = true
+			logger.Debugf("\t index of %q: %d", r.StartColumn, r.StartColumnIndex)
 		}
 
-		if col.Name == r.EndColumn {
+		if !endFound && col.Name == endColumn {
 			r.EndColumnIndex = i
-			logrus.Debugf("\t index of %q: %d", r.EndColumn, r.EndColumnIndex)
+			endFound = true
+			logger.Debugf("\t index of %q: %d", r.EndColumn, r.EndColumnIndex)
 		}
 	}
-	if r.EndColumnIndex == 0 {
-		r.EndColumnIndex = len(projectColumns) - 1
-		logrus.Debugf("\t setting end column: %d", r.EndColumnIndex)
+	if !startFound {
+		return nil, &UnknownColumnError{Column: r.StartColumn, Columns: colNames}
+	}
+	if !endFound {
+		return nil, &UnknownColumnError{Column: r.EndColumn, Columns: colNames}
 	}
-	r.EndColumnID = projectColumns[r.EndColumnIndex].ID
+	if r.StartColumnIndex > r.EndColumnIndex {
+		return nil, apperrors.Errorf(apperrors.Config, "start column %q is after end column %q on the board", r.StartColumn, r.EndColumn)
+	}
+	r.EndColumnID = columns[r.EndColumnIndex].ID
 	r.ColumnNames = colNames[r.StartColumnIndex : r.EndColumnIndex+1]
-	logrus.Debugf("\tcalculating for columns [%d:%d]: %s", r.StartColumnIndex, r.EndColumnIndex, strings.Join(r.ColumnNames, ","))
-	return nil
+	logger.Debugf("\tcalculating for columns [%d:%d]: %s", r.StartColumnIndex, r.EndColumnIndex, strings.Join(r.ColumnNames, ","))
+	return columns, nil
 }
 
-// GetIssuesAndColumns returns the issues and columns for a project
+// UnmappedColumns - returns the column names found in events that are not on the board
+// and not mapped by the run config's column aliases or stages
+func (r *Runner) UnmappedColumns() []string {
+	return r.ColumnMapper.Unmapped()
+}
+
+func (r *Runner) warnUnmappedColumns() {
+	for _, name := range r.UnmappedColumns() {
+		logger.Warnf("column %q not found on board %q (%d events ignored), add it to columnAliases or stages", name, r.ProjectName, r.ColumnMapper.UnmappedCount(name))
+	}
+}
+
+// GetIssuesAndColumns returns the issues and logical columns for a project
 func (r *Runner) GetIssuesAndColumns(ctx context.Context) (models.Issues, models.ProjectColumns, error) {
 	var issues models.Issues
 
@@ -140,27 +214,27 @@ func (r *Runner) GetIssuesAndColumns(ctx context.Context) (models.Issues, models
 		return nil, nil, err
 	}
 
-	err = r.setColumnParams(projectColumns)
+	projectColumns, err = r.setColumnParams(projectColumns)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	logrus.Debugf("getting repos: %#v", r)
+	logger.WithField("endColumnID", r.EndColumnID).Debug("getting repos of the end column")
 	repos, err := r.Client.GetReposFromProjectColumn(ctx, r.EndColumnID)
 	if err != nil {
 		return nil, nil, err
 	}
-	logrus.Debugf("\trepos found: %s", strings.Join(repos.Names(), ","))
+	logger.Debugf("\trepos found: %s", strings.Join(repos.Names(), ","))
 
 	repoIssues, err := r.Client.GetIssues(ctx, r.Owner, repos.Names(), r.StartDate, r.EndDate)
 	if err != nil {
 		return nil, nil, err
 	}
-	logrus.Debugf("\ttotal repo issues found: %d", len(repoIssues))
+	logger.Debugf("\ttotal repo issues found: %d", len(repoIssues))
 
 	for _, issue := range repoIssues {
 		issue.Events, err = r.Client.GetIssueEvents(ctx, issue.Owner, issue.RepoName, issue.Number)
-		logrus.Debugf("\t %d events for: %s/%d", len(issue.Events), issue.RepoName, issue.Number)
+		logger.Debugf("\t %d events for: %s/%d", len(issue.Events), issue.RepoName, issue.Number)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -170,6 +244,79 @@ func (r *Runner) GetIssuesAndColumns(ctx context.Context) (models.Issues, models
 	return issues, projectColumns, nil
 }
 
+// GetRepos returns the project's columns, unmapped, and the repos with cards in its end column
+func (r *Runner) GetRepos(ctx context.Context) (models.ProjectColumns, models.Repositories, error) {
+	projectColumns, err := r.Client.GetProjectColumns(ctx, r.ProjectID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if _, err = r.setColumnParams(projectColumns); err != nil {
+		return nil, nil, err
+	}
+
+	repos, err := r.Client.GetReposFromProjectColumn(ctx, r.EndColumnID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return projectColumns, repos, nil
+}
+
+// GetColumnNames returns the names of the logical columns, from the start to the end column, the runner reports on
+func (r *Runner) GetColumnNames(ctx context.Context) ([]string, error) {
+	projectColumns, err := r.Client.GetProjectColumns(ctx, r.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = r.setColumnParams(projectColumns); err != nil {
+		return nil, err
+	}
+	return r.ColumnNames, nil
+}
+
+// Validate - checks with the client that the runner's project exists and its start and end columns are columns of
+// the board, in order
+func (r *Runner) Validate(ctx context.Context) error {
+	project, err := r.Client.GetProject(ctx, r.ProjectID)
+	if err != nil {
+		return fmt.Errorf("project %d: %w", r.ProjectID, err)
+	}
+	r.ProjectName = project.Name
+	_, err = r.GetColumnNames(ctx)
+	return err
+}
+
+// GetIssueAndColumns returns a single issue, with its events, and the logical columns for a project
+func (r *Runner) GetIssueAndColumns(ctx context.Context, repoName string, issueNumber int) (models.Issue, models.ProjectColumns, error) {
+	project, err := r.Client.GetProject(ctx, r.ProjectID)
+	if err != nil {
+		return models.Issue{}, nil, err
+	}
+	r.ProjectName = project.Name
+
+	projectColumns, err := r.Client.GetProjectColumns(ctx, r.ProjectID)
+	if err != nil {
+		return models.Issue{}, nil, err
+	}
+
+	projectColumns, err = r.setColumnParams(projectColumns)
+	if err != nil {
+		return models.Issue{}, nil, err
+	}
+
+	issue, err := r.Client.GetIssue(ctx, r.Owner, repoName, issueNumber)
+	if err != nil {
+		return models.Issue{}, nil, err
+	}
+
+	issue.Events, err = r.Client.GetIssueEvents(ctx, issue.Owner, issue.RepoName, issue.Number)
+	logger.Debugf("\t %d events for: %s/%d", len(issue.Events), issue.RepoName, issue.Number)
+	if err != nil {
+		return models.Issue{}, nil, err
+	}
+	return issue, projectColumns, nil
+}
+
 // RunName - returns formatted filename including the .csv extension
 func (r *Runner) RunName() string {
 	return fmt.Sprintf("%s_%s_%d-%02d.csv",
@@ -181,15 +328,15 @@ func (r *Runner) RunName() string {
 }
 
 func (r *Runner) Debug() {
-	logrus.Debugf("\t MetricName: %q", r.MetricName)
-	logrus.Debugf("\t Owner: %q", r.Owner)
-	logrus.Debugf("\t ProjectName: %q", r.ProjectName)
-	logrus.Debugf("\t ProjectID: %d", r.ProjectID)
-	logrus.Debugf("\t StartColumn: %q", r.StartColumn)
-	logrus.Debugf("\t EndColumn: %q", r.EndColumn)
-	logrus.Debugf("\t StartDate: %q", r.StartDate.String())
-	logrus.Debugf("\t EndDate: %q", r.EndDate.String())
-	logrus.Debugf("\t StartColumnIndex: %d", r.StartColumnIndex)
-	logrus.Debugf("\t EndColumnIndex: %d", r.EndColumnIndex)
-	logrus.Debugf("\t EndColumnID: %d", r.EndColumnID)
+	logger.Debugf("\t MetricName: %q", r.MetricName)
+	logger.Debugf("\t Owner: %q", r.Owner)
+	logger.Debugf("\t ProjectName: %q", r.ProjectName)
+	logger.Debugf("\t ProjectID: %d", r.ProjectID)
+	logger.Debugf("\t StartColumn: %q", r.StartColumn)
+	logger.Debugf("\t EndColumn: %q", r.EndColumn)
+	logger.Debugf("\t StartDate: %q", r.StartDate.String())
+	logger.Debugf("\t EndDate: %q", r.EndDate.String())
+	logger.Debugf("\t StartColumnIndex: %d", r.StartColumnIndex)
+	logger.Debugf("\t EndColumnIndex: %d", r.EndColumnIndex)
+	logger.Debugf("\t EndColumnID: %d", r.EndColumnID)
 }
@@ -7,10 +7,10 @@ import (
 	"time"
 
 	"github.com/3xcellent/github-metrics/config"
+	"github.com/3xcellent/github-metrics/logging"
 	"github.com/3xcellent/github-metrics/metrics/runners"
 	"github.com/3xcellent/github-metrics/metrics/runners/runnersfakes"
 	"github.com/3xcellent/github-metrics/models"
-	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -184,7 +184,8 @@ func TestColumnsRunner_Run_Error(t *testing.T) {
 }
 
 func TestColumnsRunner_Run(t *testing.T) {
-	logrus.SetLevel(logrus.DebugLevel)
+	require.NoError(t, logging.Setup(logging.Options{Level: "debug"}))
+	defer logging.Setup(logging.Options{})
 	fakeClient := new(runnersfakes.FakeClient)
 	runConfig := config.RunConfig{
 		ProjectID: 42,
@@ -218,3 +219,39 @@ func TestColumnsRunner_Run(t *testing.T) {
 	})
 	testCtxCancelFunc()
 }
+
+func TestRunner_GetColumnNames(t *testing.T) {
+	fakeClient := new(runnersfakes.FakeClient)
+	fakeClient.GetProjectColumnsReturns(models.ProjectColumns{{ID: 1, Name: "Backlog"}, {ID: 2, Name: "Doing"}, {ID: 3, Name: "Done"}}, nil)
+
+	t.Run("returns the columns from the start to the end column", func(t *testing.T) {
+		object := runners.NewBaseRunner(config.RunConfig{StartColumn: "Doing", EndColumn: "Done"}, fakeClient)
+		names, err := object.GetColumnNames(testCtx)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Doing", "Done"}, names)
+		assert.Equal(t, int64(3), object.EndColumnID)
+	})
+
+	t.Run("defaults to the first and last columns", func(t *testing.T) {
+		names, err := runners.NewBaseRunner(config.RunConfig{}, fakeClient).GetColumnNames(testCtx)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Backlog", "Doing", "Done"}, names)
+	})
+
+	t.Run("refuses unknown columns", func(t *testing.T) {
+		for _, runConfig := range []config.RunConfig{
+			{StartColumn: "Doign", EndColumn: "Done"},
+			{StartColumn: "Doing", EndColumn: "Shipped"},
+		} {
+			_, err := runners.NewBaseRunner(runConfig, fakeClient).GetColumnNames(testCtx)
+			var unknown *runners.UnknownColumnError
+			require.True(t, errors.As(err, &unknown), "%v", err)
+			assert.Equal(t, []string{"Backlog", "Doing", "Done"}, unknown.Columns)
+		}
+	})
+
+	t.Run("refuses a start column after the end column", func(t *testing.T) {
+		_, err := runners.NewBaseRunner(config.RunConfig{StartColumn: "Done", EndColumn: "Doing"}, fakeClient).GetColumnNames(testCtx)
+		assert.EqualError(t, err, `start column "Done" is after end column "Doing" on the board`)
+	})
+}
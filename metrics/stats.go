@@ -0,0 +1,133 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Percentile - returns the pth percentile (0-100) of values using linear interpolation
+// between closest ranks; returns 0 when values is empty
+func Percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	if lower < 0 {
+		return sorted[0]
+	}
+	if upper >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
+
+// Stats - summary statistics for a set of values
+type Stats struct {
+	Count int     `json:"count"`
+	Mean  float64 `json:"mean"`
+	Min   float64 `json:"min"`
+	P50   float64 `json:"p50"`
+	P85   float64 `json:"p85"`
+	P95   float64 `json:"p95"`
+	Max   float64 `json:"max"`
+}
+
+// NewStats - returns the Stats for values; all fields are 0 when values is empty
+func NewStats(values []float64) Stats {
+	if len(values) == 0 {
+		return Stats{}
+	}
+	stats := Stats{
+		Count: len(values),
+		Min:   values[0],
+		Max:   values[0],
+		P50:   Percentile(values, 50),
+		P85:   Percentile(values, 85),
+		P95:   Percentile(values, 95),
+	}
+	total := 0.0
+	for _, v := range values {
+		total += v
+		stats.Min = math.Min(stats.Min, v)
+		stats.Max = math.Max(stats.Max, v)
+	}
+	stats.Mean = total / float64(len(values))
+	return stats
+}
+
+// StatsHeaders - returns the csv headers for Stats.Values
+func StatsHeaders() []string {
+	return []string{"Count", "Mean", "Min", "P50", "P85", "P95", "Max"}
+}
+
+// Values - returns a row of csv values for the stats
+func (s Stats) Values() []string {
+	return []string{
+		strconv.Itoa(s.Count),
+		fmt.Sprintf("%.1f", s.Mean),
+		fmt.Sprintf("%.1f", s.Min),
+		fmt.Sprintf("%.1f", s.P50),
+		fmt.Sprintf("%.1f", s.P85),
+		fmt.Sprintf("%.1f", s.P95),
+		fmt.Sprintf("%.1f", s.Max),
+	}
+}
+
+// IssuesSummary - summary statistics of the durations (in days) of a set of issues
+type IssuesSummary struct {
+	CycleTime   Stats `json:"cycleTime"`
+	LeadTime    Stats `json:"leadTime"`
+	TimeToStart Stats `json:"timeToStart"`
+}
+
+// Summary - returns the IssuesSummary of the issues
+func (issues Issues) Summary() IssuesSummary {
+	cycleTimes := make([]float64, 0, len(issues))
+	leadTimes := make([]float64, 0, len(issues))
+	timesToStart := make([]float64, 0, len(issues))
+	for _, issue := range issues {
+		cycleTimes = append(cycleTimes, issue.CalcDays())
+		leadTimes = append(leadTimes, issue.LeadTimeDays())
+		timesToStart = append(timesToStart, issue.TimeToStartDays())
+	}
+	return IssuesSummary{
+		CycleTime:   NewStats(cycleTimes),
+		LeadTime:    NewStats(leadTimes),
+		TimeToStart: NewStats(timesToStart),
+	}
+}
+
+// Values - returns csv rows (with headers) of each measure's stats
+func (s IssuesSummary) Values() [][]string {
+	return [][]string{
+		append([]string{"Measure"}, StatsHeaders()...),
+		append([]string{"Cycle Time Days"}, s.CycleTime.Values()...),
+		append([]string{"Lead Time Days"}, s.LeadTime.Values()...),
+		append([]string{"Time To Start Days"}, s.TimeToStart.Values()...),
+	}
+}
+
+// BusinessDays - returns the days from from until to, counting weekdays only; 0 when to is not after from
+func BusinessDays(from, to time.Time) float64 {
+	total := time.Duration(0)
+	for start := from; start.Before(to); {
+		end := time.Date(start.Year(), start.Month(), start.Day()+1, 0, 0, 0, 0, start.Location())
+		if end.After(to) {
+			end = to
+		}
+		if weekday := start.Weekday(); weekday != time.Saturday && weekday != time.Sunday {
+			total += end.Sub(start)
+		}
+		start = end
+	}
+	return days(total)
+}
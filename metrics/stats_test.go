@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPercentile(t *testing.T) {
+	values := []float64{4, 1, 3, 2, 5}
+
+	t.Run("interpolates between closest ranks", func(t *testing.T) {
+		assert.Equal(t, 3.0, Percentile(values, 50))
+		assert.InDelta(t, 4.4, Percentile(values, 85), 0.0001)
+		assert.Equal(t, 1.0, Percentile(values, 0))
+		assert.Equal(t, 5.0, Percentile(values, 100))
+	})
+
+	t.Run("does not reorder values", func(t *testing.T) {
+		assert.Equal(t, []float64{4, 1, 3, 2, 5}, values)
+	})
+
+	t.Run("returns 0 with no values", func(t *testing.T) {
+		assert.Equal(t, 0.0, Percentile(nil, 50))
+	})
+}
+
+func TestNewStats(t *testing.T) {
+	t.Run("returns summary statistics", func(t *testing.T) {
+		stats := NewStats([]float64{4, 1, 3, 2, 5})
+		assert.Equal(t, 5, stats.Count)
+		assert.Equal(t, 3.0, stats.Mean)
+		assert.Equal(t, 1.0, stats.Min)
+		assert.Equal(t, 5.0, stats.Max)
+		assert.Equal(t, 3.0, stats.P50)
+		assert.Equal(t, []string{"5", "3.0", "1.0", "3.0", "4.4", "4.8", "5.0"}, stats.Values())
+	})
+
+	t.Run("returns empty stats with no values", func(t *testing.T) {
+		assert.Equal(t, Stats{}, NewStats(nil))
+	})
+}
+
+func TestBusinessDays(t *testing.T) {
+	friday := time.Date(2020, 1, 3, 12, 0, 0, 0, time.UTC)
+
+	t.Run("skips weekends", func(t *testing.T) {
+		assert.Equal(t, 1.0, BusinessDays(friday, friday.AddDate(0, 0, 3)))
+		assert.Equal(t, 5.0, BusinessDays(friday, friday.AddDate(0, 0, 7)))
+	})
+
+	t.Run("counts parts of days", func(t *testing.T) {
+		assert.Equal(t, 0.25, BusinessDays(friday, friday.Add(6*time.Hour)))
+		assert.Equal(t, 0.0, BusinessDays(friday.AddDate(0, 0, 1), friday.AddDate(0, 0, 2)))
+	})
+
+	t.Run("is 0 when to is not after from", func(t *testing.T) {
+		assert.Equal(t, 0.0, BusinessDays(friday, friday.Add(-time.Hour)))
+	})
+}
@@ -9,6 +9,7 @@ type IssueEvent struct {
 	Event              string
 	CreatedAt          time.Time
 	ProjectID          int64
+	ProjectCardID      int64
 	Type               IssueEventType
 	ColumnName         string
 	PreviousColumnName string
@@ -26,12 +27,14 @@ type IssueEventType string
 
 // labels
 const (
-	Assigned       IssueEventType = "ASSIGNED"
-	Unassigned     IssueEventType = "UNASSIGNED"
-	Mentioned      IssueEventType = "MENTIONED"
-	Closed         IssueEventType = "CLOSED"
-	MovedColumns   IssueEventType = "MOVED_COLUMNS_IN_PROJECT"
-	Labeled        IssueEventType = "LABELED"
-	Unlabeled      IssueEventType = "UNLABELED"
-	AddedToProject IssueEventType = "ADDED_TO_PROJECT"
+	Assigned           IssueEventType = "ASSIGNED"
+	Unassigned         IssueEventType = "UNASSIGNED"
+	Mentioned          IssueEventType = "MENTIONED"
+	Closed             IssueEventType = "CLOSED"
+	MovedColumns       IssueEventType = "MOVED_COLUMNS_IN_PROJECT"
+	Labeled            IssueEventType = "LABELED"
+	Unlabeled          IssueEventType = "UNLABELED"
+	AddedToProject     IssueEventType = "ADDED_TO_PROJECT"
+	RemovedFromProject IssueEventType = "REMOVED_FROM_PROJECT"
+	Transferred        IssueEventType = "TRANSFERRED"
 )
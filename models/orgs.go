@@ -2,10 +2,10 @@ package models
 
 // Organization - model for github.Organization
 type Organization struct {
-	Name     string
-	ID       int64
-	URL      string
-	ReposURL string
+	Name     string `json:"name"`
+	ID       int64  `json:"id"`
+	URL      string `json:"url"`
+	ReposURL string `json:"reposUrl"`
 }
 
 // Organizations - slice of Organization
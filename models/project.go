@@ -8,13 +8,13 @@ import (
 
 // Project - model used for processing metrics
 type Project struct {
-	Name     string
-	ID       int64
-	Owner    string
-	OwnerURL string
-	Body     string
-	Repo     string
-	URL      string
+	Name     string `json:"name"`
+	ID       int64  `json:"id"`
+	Owner    string `json:"owner"`
+	OwnerURL string `json:"ownerUrl"`
+	Body     string `json:"body"`
+	Repo     string `json:"repo"`
+	URL      string `json:"url"`
 }
 
 // RunConfig - returns a new config.RunConfig with Name, ProjectID, and Owner
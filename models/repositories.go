@@ -2,10 +2,10 @@ package models
 
 // Repository - model used for github Repositories
 type Repository struct {
-	Owner string
-	Name  string
-	ID    int64
-	URL   string
+	Owner string `json:"owner"`
+	Name  string `json:"name"`
+	ID    int64  `json:"id"`
+	URL   string `json:"url"`
 }
 
 // Repositories - slice of Repository
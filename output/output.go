@@ -0,0 +1,91 @@
+// Package output writes command results as csv, json, ndjson or aligned text so every
+// command supports the same formats
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+)
+
+// Format - name of an output format
+type Format string
+
+// formats shared by all commands
+const (
+	CSV    Format = "csv"
+	JSON   Format = "json"
+	NDJSON Format = "ndjson"
+	Text   Format = "text"
+)
+
+// Ext - returns the file extension used for the format
+func (f Format) Ext() string {
+	if f == Text {
+		return "txt"
+	}
+	return string(f)
+}
+
+// ParseFormat - returns the Format named, or an error if it is not one of allowed
+func ParseFormat(name string, allowed ...Format) (Format, error) {
+	names := make([]string, 0, len(allowed))
+	for _, format := range allowed {
+		if string(format) == name {
+			return format, nil
+		}
+		names = append(names, string(format))
+	}
+	return "", fmt.Errorf("unknown format %q: must be one of %s", name, strings.Join(names, ", "))
+}
+
+// Usage - returns the help text for a --format flag accepting formats
+func Usage(formats ...Format) string {
+	names := make([]string, 0, len(formats))
+	for _, format := range formats {
+		names = append(names, string(format))
+	}
+	return "output format: " + strings.Join(names, ", ")
+}
+
+// Result - a command result; Rows are written as csv or text and Records, a typed struct
+// or slice of structs, as json or ndjson
+type Result struct {
+	Rows    [][]string
+	Records interface{}
+}
+
+// Write - writes the result to w in the format provided
+func Write(w io.Writer, format Format, result Result) error {
+	switch format {
+	case CSV:
+		return csv.NewWriter(w).WriteAll(result.Rows)
+	case Text:
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		for _, row := range result.Rows {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+		return tw.Flush()
+	case JSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result.Records)
+	case NDJSON:
+		encoder := json.NewEncoder(w)
+		records := reflect.ValueOf(result.Records)
+		if records.Kind() != reflect.Slice {
+			return encoder.Encode(result.Records)
+		}
+		for idx := 0; idx < records.Len(); idx++ {
+			if err := encoder.Encode(records.Index(idx).Interface()); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown format %q", format)
+}
@@ -0,0 +1,73 @@
+package output_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/3xcellent/github-metrics/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type record struct {
+	Name string    `json:"name"`
+	Days float64   `json:"days"`
+	At   time.Time `json:"at"`
+}
+
+func TestWrite(t *testing.T) {
+	at := time.Date(2001, 2, 3, 4, 5, 6, 0, time.UTC)
+	result := output.Result{
+		Rows:    [][]string{{"Name", "Days"}, {"a", "1.5"}, {"bb", "2.0"}},
+		Records: []record{{Name: "a", Days: 1.5, At: at}, {Name: "bb", Days: 2, At: at}},
+	}
+
+	t.Run("csv writes rows", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		require.NoError(t, output.Write(buf, output.CSV, result))
+		assert.Equal(t, "Name,Days\na,1.5\nbb,2.0\n", buf.String())
+	})
+
+	t.Run("text aligns rows", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		require.NoError(t, output.Write(buf, output.Text, result))
+		assert.Equal(t, "Name  Days\na     1.5\nbb    2.0\n", buf.String())
+	})
+
+	t.Run("json writes typed records", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		require.NoError(t, output.Write(buf, output.JSON, result))
+		assert.JSONEq(t, `[
+			{"name": "a", "days": 1.5, "at": "2001-02-03T04:05:06Z"},
+			{"name": "bb", "days": 2, "at": "2001-02-03T04:05:06Z"}
+		]`, buf.String())
+	})
+
+	t.Run("ndjson writes a record per line", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		require.NoError(t, output.Write(buf, output.NDJSON, result))
+		assert.Equal(t, `{"name":"a","days":1.5,"at":"2001-02-03T04:05:06Z"}
+{"name":"bb","days":2,"at":"2001-02-03T04:05:06Z"}
+`, buf.String())
+	})
+
+	t.Run("ndjson writes a single record on one line", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		require.NoError(t, output.Write(buf, output.NDJSON, output.Result{Records: record{Name: "a"}}))
+		assert.Equal(t, `{"name":"a","days":0,"at":"0001-01-01T00:00:00Z"}`+"\n", buf.String())
+	})
+}
+
+func TestParseFormat(t *testing.T) {
+	t.Run("returns allowed format", func(t *testing.T) {
+		format, err := output.ParseFormat("ndjson", output.CSV, output.NDJSON)
+		require.NoError(t, err)
+		assert.Equal(t, output.NDJSON, format)
+	})
+
+	t.Run("errors on other formats", func(t *testing.T) {
+		_, err := output.ParseFormat("svg", output.CSV, output.JSON)
+		assert.EqualError(t, err, `unknown format "svg": must be one of csv, json`)
+	})
+}
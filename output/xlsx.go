@@ -0,0 +1,225 @@
+package output
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// XLSX - excel workbook output format
+const XLSX Format = "xlsx"
+
+// Workbook - an excel workbook of sheets with a frozen, filterable header row; cells are typed
+// by the Go type of their value: numbers, bools, time.Time (as dates) and strings
+type Workbook struct {
+	Sheets []Sheet
+}
+
+// Sheet - a named sheet of a Workbook
+type Sheet struct {
+	Name    string
+	Headers []string
+	Rows    [][]interface{}
+}
+
+// AddSheet - appends a sheet to the workbook
+func (wb *Workbook) AddSheet(name string, headers []string, rows [][]interface{}) {
+	wb.Sheets = append(wb.Sheets, Sheet{Name: name, Headers: headers, Rows: rows})
+}
+
+// cell styles, indexes into cellXfs of xlsxStyles
+const (
+	styleDefault = 0
+	styleHeader  = 1
+	styleDate    = 2
+	styleNumber  = 3
+)
+
+const xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<numFmts count="1"><numFmt numFmtId="164" formatCode="yyyy-mm-dd hh:mm"/></numFmts>
+<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>
+<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>
+<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>
+<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>
+<cellXfs count="4">
+<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>
+<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>
+<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>
+<xf numFmtId="2" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>
+</cellXfs>
+</styleSheet>
+`
+
+// excel stores dates as days since 1899-12-30
+var excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+func excelDate(t time.Time) float64 {
+	wallClock := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	return wallClock.Sub(excelEpoch).Hours() / 24
+}
+
+// columnName - returns the excel column name of the zero based index, e.g. 0 is A and 26 is AA
+func columnName(idx int) string {
+	name := ""
+	for idx++; idx > 0; idx = (idx - 1) / 26 {
+		name = string(rune('A'+(idx-1)%26)) + name
+	}
+	return name
+}
+
+func escape(s string) string {
+	buf := new(bytes.Buffer)
+	xml.EscapeText(buf, []byte(s))
+	return buf.String()
+}
+
+func writeCell(buf *bytes.Buffer, ref string, value interface{}, style int) {
+	switch v := value.(type) {
+	case nil:
+	case time.Time:
+		if v.IsZero() {
+			return
+		}
+		fmt.Fprintf(buf, `<c r="%s" s="%d"><v>%s</v></c>`, ref, styleDate, strconv.FormatFloat(excelDate(v), 'f', -1, 64))
+	case float64:
+		fmt.Fprintf(buf, `<c r="%s" s="%d"><v>%s</v></c>`, ref, styleNumber, strconv.FormatFloat(v, 'f', -1, 64))
+	case int:
+		fmt.Fprintf(buf, `<c r="%s"><v>%d</v></c>`, ref, v)
+	case int64:
+		fmt.Fprintf(buf, `<c r="%s"><v>%d</v></c>`, ref, v)
+	case bool:
+		b := 0
+		if v {
+			b = 1
+		}
+		fmt.Fprintf(buf, `<c r="%s" t="b"><v>%d</v></c>`, ref, b)
+	default:
+		fmt.Fprintf(buf, `<c r="%s" s="%d" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, style, escape(fmt.Sprint(v)))
+	}
+}
+
+func (s Sheet) lastColumn() int {
+	last := len(s.Headers)
+	for _, row := range s.Rows {
+		if len(row) > last {
+			last = len(row)
+		}
+	}
+	return last - 1
+}
+
+func (s Sheet) xml() []byte {
+	buf := new(bytes.Buffer)
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n")
+	buf.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	buf.WriteString(`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>`)
+	buf.WriteString(`<sheetData>`)
+	buf.WriteString(`<row r="1">`)
+	for idx, header := range s.Headers {
+		writeCell(buf, columnName(idx)+"1", header, styleHeader)
+	}
+	buf.WriteString(`</row>`)
+	for rowIdx, row := range s.Rows {
+		fmt.Fprintf(buf, `<row r="%d">`, rowIdx+2)
+		for idx, value := range row {
+			writeCell(buf, fmt.Sprintf("%s%d", columnName(idx), rowIdx+2), value, styleDefault)
+		}
+		buf.WriteString(`</row>`)
+	}
+	buf.WriteString(`</sheetData>`)
+	if s.lastColumn() >= 0 {
+		fmt.Fprintf(buf, `<autoFilter ref="%s"/>`, s.filterRef())
+	}
+	buf.WriteString(`</worksheet>`)
+	return buf.Bytes()
+}
+
+func (s Sheet) filterRef() string {
+	return fmt.Sprintf("A1:%s%d", columnName(s.lastColumn()), len(s.Rows)+1)
+}
+
+// Write - writes the workbook as an .xlsx file
+func (wb *Workbook) Write(w io.Writer) error {
+	z := zip.NewWriter(w)
+	files := []struct {
+		name string
+		data []byte
+	}{
+		{"[Content_Types].xml", wb.contentTypes()},
+		{"_rels/.rels", []byte(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>
+`)},
+		{"xl/workbook.xml", wb.workbook()},
+		{"xl/_rels/workbook.xml.rels", wb.relationships()},
+		{"xl/styles.xml", []byte(xlsxStyles)},
+	}
+	for idx, sheet := range wb.Sheets {
+		files = append(files, struct {
+			name string
+			data []byte
+		}{fmt.Sprintf("xl/worksheets/sheet%d.xml", idx+1), sheet.xml()})
+	}
+
+	for _, file := range files {
+		f, err := z.Create(file.name)
+		if err != nil {
+			return err
+		}
+		if _, err = f.Write(file.data); err != nil {
+			return err
+		}
+	}
+	return z.Close()
+}
+
+func (wb *Workbook) contentTypes() []byte {
+	buf := new(bytes.Buffer)
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>
+`)
+	for idx := range wb.Sheets {
+		fmt.Fprintf(buf, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`+"\n", idx+1)
+	}
+	buf.WriteString("</Types>\n")
+	return buf.Bytes()
+}
+
+func (wb *Workbook) workbook() []byte {
+	buf := new(bytes.Buffer)
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	for idx, sheet := range wb.Sheets {
+		fmt.Fprintf(buf, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, escape(sheet.Name), idx+1, idx+1)
+	}
+	buf.WriteString(`</sheets><definedNames>`)
+	for idx, sheet := range wb.Sheets {
+		if sheet.lastColumn() < 0 {
+			continue
+		}
+		absolute := fmt.Sprintf("$A$1:$%s$%d", columnName(sheet.lastColumn()), len(sheet.Rows)+1)
+		fmt.Fprintf(buf, `<definedName name="_xlnm._FilterDatabase" localSheetId="%d" hidden="1">'%s'!%s</definedName>`, idx, escape(sheet.Name), absolute)
+	}
+	buf.WriteString("</definedNames></workbook>\n")
+	return buf.Bytes()
+}
+
+func (wb *Workbook) relationships() []byte {
+	buf := new(bytes.Buffer)
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for idx := range wb.Sheets {
+		fmt.Fprintf(buf, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, idx+1, idx+1)
+	}
+	fmt.Fprintf(buf, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, len(wb.Sheets)+1)
+	buf.WriteString("</Relationships>\n")
+	return buf.Bytes()
+}
@@ -0,0 +1,69 @@
+package output_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/3xcellent/github-metrics/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readXLSX - returns the contents of each file of the xlsx workbook by name
+func readXLSX(t *testing.T, data []byte) map[string]string {
+	z, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	files := make(map[string]string)
+	for _, f := range z.File {
+		r, err := f.Open()
+		require.NoError(t, err)
+		content, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+		files[f.Name] = string(content)
+	}
+	return files
+}
+
+func TestWorkbook_Write(t *testing.T) {
+	wb := new(output.Workbook)
+	wb.AddSheet("issues", []string{"Card #", "Description", "Created", "Days", "Blocked?"}, [][]interface{}{
+		{12, "fix <this> & that", time.Date(2020, 1, 2, 12, 0, 0, 0, time.UTC), 1.5, true},
+		{13, "other", time.Time{}, 0.25, false},
+	})
+	wb.AddSheet("run info", []string{"Setting", "Value"}, nil)
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, wb.Write(buf))
+	files := readXLSX(t, buf.Bytes())
+
+	t.Run("includes a worksheet per sheet", func(t *testing.T) {
+		assert.Contains(t, files["xl/workbook.xml"], `<sheet name="issues" sheetId="1" r:id="rId1"/>`)
+		assert.Contains(t, files["xl/workbook.xml"], `<sheet name="run info" sheetId="2" r:id="rId2"/>`)
+		assert.Contains(t, files["[Content_Types].xml"], `/xl/worksheets/sheet2.xml`)
+		assert.Contains(t, files, "xl/styles.xml")
+	})
+
+	sheet := files["xl/worksheets/sheet1.xml"]
+	t.Run("freezes and filters the header row", func(t *testing.T) {
+		assert.Contains(t, sheet, `<pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/>`)
+		assert.Contains(t, sheet, `<autoFilter ref="A1:E3"/>`)
+		assert.Contains(t, files["xl/workbook.xml"], `localSheetId="0" hidden="1">'issues'!$A$1:$E$3</definedName>`)
+		assert.Contains(t, files["xl/worksheets/sheet2.xml"], `<autoFilter ref="A1:B1"/>`)
+	})
+
+	t.Run("writes typed cells", func(t *testing.T) {
+		assert.Contains(t, sheet, `<c r="A2"><v>12</v></c>`)
+		assert.Contains(t, sheet, `<c r="B2" s="0" t="inlineStr"><is><t xml:space="preserve">fix &lt;this&gt; &amp; that</t></is></c>`)
+		assert.Contains(t, sheet, `<c r="C2" s="2"><v>43832.5</v></c>`)
+		assert.Contains(t, sheet, `<c r="D2" s="3"><v>1.5</v></c>`)
+		assert.Contains(t, sheet, `<c r="E2" t="b"><v>1</v></c>`)
+		assert.Contains(t, sheet, `<c r="A1" s="1" t="inlineStr"><is><t xml:space="preserve">Card #</t></is></c>`)
+	})
+
+	t.Run("leaves zero dates empty", func(t *testing.T) {
+		assert.NotContains(t, sheet, `r="C3"`)
+	})
+}
@@ -0,0 +1,103 @@
+// Package report renders the results of a run as a single, standalone html page
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/3xcellent/github-metrics/charts"
+	"github.com/3xcellent/github-metrics/config"
+	"github.com/3xcellent/github-metrics/metrics"
+	"github.com/3xcellent/github-metrics/metrics/runners"
+	"github.com/pkg/errors"
+)
+
+// Report - the data available to the html template
+type Report struct {
+	Title       string
+	Generated   time.Time
+	RunConfig   config.RunConfig
+	ColumnNames []string
+	Summary     metrics.IssuesSummary
+	Issues      []metrics.IssueResult
+	CFD         template.HTML
+	Scatter     template.HTML
+}
+
+// New - returns the Report of a completed issues and columns run of the same RunConfig
+func New(runCfg config.RunConfig, issues *runners.IssuesRunner, columns *runners.ColumnsRunner) (*Report, error) {
+	cfd := charts.NewCumulativeFlow(columns)
+	annotations, err := charts.Annotations(runCfg.Annotations, runCfg.StartDate.Location())
+	if err != nil {
+		return nil, err
+	}
+	cfd.Annotations = annotations
+
+	cfdSVG, err := svg(cfd.SVG)
+	if err != nil {
+		return nil, err
+	}
+	scatterSVG, err := svg(charts.NewCycleTimeScatter(issues).SVG)
+	if err != nil {
+		return nil, err
+	}
+
+	completed := issues.CompletedIssues()
+	return &Report{
+		Title:       fmt.Sprintf("%s - %d-%02d", issues.ProjectName, runCfg.StartDate.Year(), runCfg.StartDate.Month()),
+		Generated:   time.Now(),
+		RunConfig:   runCfg,
+		ColumnNames: issues.ColumnNames,
+		Summary:     completed.Summary(),
+		Issues:      completed.Results(),
+		CFD:         cfdSVG,
+		Scatter:     scatterSVG,
+	}, nil
+}
+
+// charts are generated by this package and safe to include without escaping
+func svg(write func(io.Writer) error) (template.HTML, error) {
+	buf := new(bytes.Buffer)
+	if err := write(buf); err != nil {
+		return "", err
+	}
+	return template.HTML(buf.String()), nil
+}
+
+// Template - returns the template at path, or the default template when path is empty
+func Template(path string) (*template.Template, error) {
+	text := defaultTemplate
+	if path != "" {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, errors.Wrap(err, "error reading report template")
+		}
+		text = string(b)
+	}
+	tmpl, err := template.New("report").Funcs(funcs).Parse(text)
+	if err != nil {
+		return nil, errors.Wrap(err, "error parsing report template")
+	}
+	return tmpl, nil
+}
+
+// Write - renders the report to w using tmpl, see Template
+func (r *Report) Write(w io.Writer, tmpl *template.Template) error {
+	return tmpl.Execute(w, r)
+}
+
+var funcs = template.FuncMap{
+	"date": func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.Format("2006-01-02")
+	},
+	"days": func(d float64) string {
+		return fmt.Sprintf("%.1f", d)
+	},
+}
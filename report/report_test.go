@@ -0,0 +1,78 @@
+package report_test
+
+import (
+	"bytes"
+	"html/template"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/3xcellent/github-metrics/config"
+	"github.com/3xcellent/github-metrics/metrics"
+	"github.com/3xcellent/github-metrics/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReport_Write(t *testing.T) {
+	date := time.Date(2001, 2, 3, 0, 0, 0, 0, time.UTC)
+	r := &report.Report{
+		Title:       "Board - 2001-02",
+		Generated:   date,
+		RunConfig:   config.RunConfig{Name: "Board", StartColumn: "Develop", StartDate: date},
+		ColumnNames: []string{"Develop", "Done"},
+		Summary:     metrics.IssuesSummary{CycleTime: metrics.Stats{Count: 1, Mean: 2.25}},
+		Issues: []metrics.IssueResult{{
+			Number: 12,
+			Title:  "<script>alert(1)</script>",
+			ColumnDates: []metrics.ColumnDateResult{
+				{Column: "Develop", Date: date},
+				{Column: "Done", Date: date.AddDate(0, 0, 2), Inferred: true},
+			},
+			DevelopmentDays: 2,
+		}},
+		CFD: template.HTML(`<svg id="cfd"></svg>`),
+	}
+
+	t.Run("default template", func(t *testing.T) {
+		tmpl, err := report.Template("")
+		require.NoError(t, err)
+		buf := new(bytes.Buffer)
+		require.NoError(t, r.Write(buf, tmpl))
+		html := buf.String()
+
+		t.Run("includes summary, issues, charts and run config", func(t *testing.T) {
+			assert.Contains(t, html, "<title>Board - 2001-02</title>")
+			assert.Contains(t, html, `<td class="num">2.2</td>`)
+			assert.Contains(t, html, `<td data-sort="2001-02-05">2001-02-05*</td>`)
+			assert.Contains(t, html, `<svg id="cfd"></svg>`)
+			assert.Contains(t, html, "<tr><th>Start Column</th><td>Develop</td></tr>")
+		})
+
+		t.Run("escapes issue data", func(t *testing.T) {
+			assert.NotContains(t, html, "<script>alert(1)</script>")
+			assert.Contains(t, html, "&lt;script&gt;alert(1)&lt;/script&gt;")
+		})
+	})
+
+	t.Run("template from file", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "report")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "report.html")
+		require.NoError(t, ioutil.WriteFile(path, []byte(`{{ .Title }}: {{ len .Issues }} issues`), 0600))
+
+		tmpl, err := report.Template(path)
+		require.NoError(t, err)
+		buf := new(bytes.Buffer)
+		require.NoError(t, r.Write(buf, tmpl))
+		assert.Equal(t, "Board - 2001-02: 1 issues", buf.String())
+	})
+
+	t.Run("missing template file", func(t *testing.T) {
+		_, err := report.Template("does-not-exist.html")
+		assert.Error(t, err)
+	})
+}
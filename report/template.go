@@ -0,0 +1,110 @@
+package report
+
+// defaultTemplate - the html/template used unless RunConfig.ReportTemplate is set; it is
+// executed with a *Report
+const defaultTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{ .Title }}</title>
+<style>
+  body { font-family: sans-serif; margin: 2em; color: #222; }
+  h1 { margin-bottom: 0; }
+  .generated { color: #777; margin-top: 0.25em; }
+  table { border-collapse: collapse; margin: 1em 0; }
+  th, td { border: 1px solid #ddd; padding: 4px 8px; text-align: left; }
+  td.num { text-align: right; }
+  th { background: #f4f4f4; }
+  table.sortable th { cursor: pointer; user-select: none; }
+  table.sortable th.asc::after { content: " \25B2"; }
+  table.sortable th.desc::after { content: " \25BC"; }
+  .charts svg { max-width: 100%; height: auto; display: block; margin: 1em 0; }
+</style>
+</head>
+<body>
+<h1>{{ .Title }}</h1>
+<p class="generated">generated {{ .Generated.Format "2006-01-02 15:04" }}</p>
+
+<h2>Summary</h2>
+<table>
+  <tr><th>Measure</th><th>Count</th><th>Mean</th><th>Min</th><th>P50</th><th>P85</th><th>P95</th><th>Max</th></tr>
+  {{ with .Summary.CycleTime }}<tr><td>Cycle Time Days</td><td class="num">{{ .Count }}</td><td class="num">{{ days .Mean }}</td><td class="num">{{ days .Min }}</td><td class="num">{{ days .P50 }}</td><td class="num">{{ days .P85 }}</td><td class="num">{{ days .P95 }}</td><td class="num">{{ days .Max }}</td></tr>{{ end }}
+  {{ with .Summary.LeadTime }}<tr><td>Lead Time Days</td><td class="num">{{ .Count }}</td><td class="num">{{ days .Mean }}</td><td class="num">{{ days .Min }}</td><td class="num">{{ days .P50 }}</td><td class="num">{{ days .P85 }}</td><td class="num">{{ days .P95 }}</td><td class="num">{{ days .Max }}</td></tr>{{ end }}
+  {{ with .Summary.TimeToStart }}<tr><td>Time To Start Days</td><td class="num">{{ .Count }}</td><td class="num">{{ days .Mean }}</td><td class="num">{{ days .Min }}</td><td class="num">{{ days .P50 }}</td><td class="num">{{ days .P85 }}</td><td class="num">{{ days .P95 }}</td><td class="num">{{ days .Max }}</td></tr>{{ end }}
+</table>
+
+<h2>Charts</h2>
+<div class="charts">
+{{ .CFD }}
+{{ .Scatter }}
+</div>
+
+<h2>Issues</h2>
+<table class="sortable">
+  <thead>
+  <tr>
+    <th>Card #</th><th>Team</th><th>Type</th><th>Description</th>
+    {{ range .ColumnNames }}<th>{{ . }}</th>{{ end }}
+    <th>Development Days</th><th>Feature?</th><th>Blocked Days</th><th>Lead Time Days</th>
+  </tr>
+  </thead>
+  <tbody>
+  {{ range .Issues }}
+  <tr>
+    <td class="num" data-sort="{{ .Number }}">{{ .Number }}</td>
+    <td>{{ .Repo }}</td>
+    <td>{{ .Type }}</td>
+    <td>{{ .Title }}</td>
+    {{ range .ColumnDates }}<td data-sort="{{ date .Date }}">{{ date .Date }}{{ if .Inferred }}*{{ end }}</td>{{ end }}
+    <td class="num" data-sort="{{ .DevelopmentDays }}">{{ days .DevelopmentDays }}</td>
+    <td>{{ .Feature }}</td>
+    <td class="num" data-sort="{{ .BlockedDays }}">{{ days .BlockedDays }}</td>
+    <td class="num" data-sort="{{ .LeadTimeDays }}">{{ days .LeadTimeDays }}</td>
+  </tr>
+  {{ end }}
+  </tbody>
+</table>
+<p>* date inferred from a neighbouring column</p>
+
+<h2>Run Config</h2>
+{{ with .RunConfig }}
+<table>
+  <tr><th>Name</th><td>{{ .Name }}</td></tr>
+  <tr><th>Owner</th><td>{{ .Owner }}</td></tr>
+  <tr><th>Project ID</th><td>{{ .ProjectID }}</td></tr>
+  <tr><th>Start Column</th><td>{{ .StartColumn }}</td></tr>
+  <tr><th>End Column</th><td>{{ .EndColumn }}</td></tr>
+  <tr><th>Start Date</th><td>{{ date .StartDate }}</td></tr>
+  <tr><th>End Date</th><td>{{ date .EndDate }}</td></tr>
+  {{ range .ColumnAliases }}<tr><th>Column Alias</th><td>{{ .Name }}: {{ range $i, $a := .Aliases }}{{ if $i }}, {{ end }}{{ $a }}{{ end }}</td></tr>{{ end }}
+  {{ range .Stages }}<tr><th>Stage</th><td>{{ .Name }}: {{ range $i, $c := .Columns }}{{ if $i }}, {{ end }}{{ $c }}{{ end }}</td></tr>{{ end }}
+  {{ range .Annotations }}<tr><th>Annotation</th><td>{{ .Date }} {{ .Label }}</td></tr>{{ end }}
+</table>
+{{ end }}
+
+<script>
+document.querySelectorAll("table.sortable").forEach(function (table) {
+  table.querySelectorAll("th").forEach(function (th, col) {
+    th.addEventListener("click", function () {
+      var asc = !th.classList.contains("asc");
+      table.querySelectorAll("th").forEach(function (h) { h.classList.remove("asc", "desc"); });
+      th.classList.add(asc ? "asc" : "desc");
+      var body = table.tBodies[0];
+      var rows = Array.prototype.slice.call(body.rows);
+      var value = function (row) {
+        var cell = row.cells[col];
+        return cell.getAttribute("data-sort") || cell.textContent;
+      };
+      rows.sort(function (a, b) {
+        var x = value(a), y = value(b);
+        var cmp = (!isNaN(x) && !isNaN(y)) ? x - y : x.localeCompare(y);
+        return asc ? cmp : -cmp;
+      });
+      rows.forEach(function (row) { body.appendChild(row); });
+    });
+  });
+});
+</script>
+</body>
+</html>
+`
@@ -0,0 +1,133 @@
+package report
+
+import (
+	"io"
+	"strings"
+	"time"
+
+	"github.com/3xcellent/github-metrics/config"
+	"github.com/3xcellent/github-metrics/metrics"
+	"github.com/3xcellent/github-metrics/metrics/runners"
+	"github.com/3xcellent/github-metrics/output"
+)
+
+// Workbook - the typed results of a run, written as the sheets of an xlsx workbook
+type Workbook struct {
+	Generated    time.Time
+	RunConfig    config.RunConfig
+	ColumnNames  []string
+	Issues       []metrics.IssueResult
+	Summary      metrics.IssuesSummary
+	Columns      []metrics.ColumnsMetric
+	PullRequests []metrics.PullRequestResult
+}
+
+// NewWorkbook - returns the Workbook of a completed issues and columns run and the pull requests closed during it
+func NewWorkbook(runCfg config.RunConfig, issues *runners.IssuesRunner, columns *runners.ColumnsRunner, prs []metrics.PullRequestResult) *Workbook {
+	completed := issues.CompletedIssues()
+	return &Workbook{
+		Generated:    time.Now(),
+		RunConfig:    runCfg,
+		ColumnNames:  issues.ColumnNames,
+		Issues:       completed.Results(),
+		Summary:      completed.Summary(),
+		Columns:      columns.ColumnsMetrics(),
+		PullRequests: prs,
+	}
+}
+
+// Write - writes the workbook as xlsx with the sheets issues, columns, prs, summary and run info
+func (wb *Workbook) Write(w io.Writer) error {
+	xlsx := new(output.Workbook)
+	xlsx.AddSheet("issues", wb.issuesHeaders(), wb.issuesRows())
+	xlsx.AddSheet("columns", append([]string{"Date"}, wb.ColumnNames...), wb.columnsRows())
+	xlsx.AddSheet("prs", []string{"Repo", "Issue #", "Created At", "Created By", "Group", "Closed At", "Requested Reviewers", "Days Open"}, wb.pullRequestsRows())
+	xlsx.AddSheet("summary", append([]string{"Measure"}, metrics.StatsHeaders()...), wb.summaryRows())
+	xlsx.AddSheet("run info", []string{"Setting", "Value"}, wb.runInfoRows())
+	return xlsx.Write(w)
+}
+
+func (wb *Workbook) issuesHeaders() []string {
+	headers := append([]string{"Card #", "Team", "Type", "Description"}, wb.ColumnNames...)
+	return append(headers, "Development Days", "Feature?", "Blocked?", "Blocked Days", "Created", "Lead Time Days", "Time To Start Days")
+}
+
+func (wb *Workbook) issuesRows() [][]interface{} {
+	rows := make([][]interface{}, 0, len(wb.Issues))
+	for _, issue := range wb.Issues {
+		row := []interface{}{issue.Number, issue.Repo, issue.Type, issue.Title}
+		for _, col := range issue.ColumnDates {
+			row = append(row, col.Date)
+		}
+		rows = append(rows, append(row,
+			issue.DevelopmentDays,
+			issue.Feature,
+			issue.Blocked,
+			issue.BlockedDays,
+			issue.Created,
+			issue.LeadTimeDays,
+			issue.TimeToStartDays,
+		))
+	}
+	return rows
+}
+
+func (wb *Workbook) columnsRows() [][]interface{} {
+	rows := make([][]interface{}, 0, len(wb.Columns))
+	for _, day := range wb.Columns {
+		row := []interface{}{day.Date}
+		for _, amount := range day.ColumnAmounts {
+			row = append(row, amount.Amount)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func (wb *Workbook) pullRequestsRows() [][]interface{} {
+	rows := make([][]interface{}, 0, len(wb.PullRequests))
+	for _, pr := range wb.PullRequests {
+		rows = append(rows, []interface{}{
+			pr.Repo,
+			pr.IssueNumber,
+			pr.CreatedAt,
+			pr.CreatedBy,
+			pr.Group,
+			pr.ClosedAt,
+			strings.Join(pr.RequestedReviewers, ","),
+			pr.DaysOpen,
+		})
+	}
+	return rows
+}
+
+func (wb *Workbook) summaryRows() [][]interface{} {
+	row := func(measure string, s metrics.Stats) []interface{} {
+		return []interface{}{measure, s.Count, s.Mean, s.Min, s.P50, s.P85, s.P95, s.Max}
+	}
+	return [][]interface{}{
+		row("Cycle Time Days", wb.Summary.CycleTime),
+		row("Lead Time Days", wb.Summary.LeadTime),
+		row("Time To Start Days", wb.Summary.TimeToStart),
+	}
+}
+
+func (wb *Workbook) runInfoRows() [][]interface{} {
+	cfg := wb.RunConfig
+	rows := [][]interface{}{
+		{"Board", cfg.Name},
+		{"Owner", cfg.Owner},
+		{"Project ID", cfg.ProjectID},
+		{"Start Date", cfg.StartDate},
+		{"End Date", cfg.EndDate},
+		{"Start Column", cfg.StartColumn},
+		{"End Column", cfg.EndColumn},
+	}
+	for _, alias := range cfg.ColumnAliases {
+		rows = append(rows, []interface{}{"Column Alias: " + alias.Name, strings.Join(alias.Aliases, ", ")})
+	}
+	for _, stage := range cfg.Stages {
+		rows = append(rows, []interface{}{"Stage: " + stage.Name, strings.Join(stage.Columns, ", ")})
+	}
+	return append(rows, []interface{}{"Generated", wb.Generated})
+}
@@ -0,0 +1,73 @@
+package report_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/3xcellent/github-metrics/config"
+	"github.com/3xcellent/github-metrics/metrics"
+	"github.com/3xcellent/github-metrics/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkbook_Write(t *testing.T) {
+	date := time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)
+	wb := &report.Workbook{
+		Generated: date,
+		RunConfig: config.RunConfig{
+			Name:        "Board",
+			StartColumn: "Develop",
+			EndColumn:   "Done",
+			StartDate:   date,
+			Stages:      []config.Stage{{Name: "Review", Columns: []string{"Code Review", "QA"}}},
+		},
+		ColumnNames: []string{"Develop", "Done"},
+		Issues: []metrics.IssueResult{{
+			Number:      12,
+			Title:       "an issue",
+			ColumnDates: []metrics.ColumnDateResult{{Column: "Develop", Date: date}, {Column: "Done", Date: date.AddDate(0, 0, 2)}},
+		}},
+		Summary: metrics.IssuesSummary{CycleTime: metrics.Stats{Count: 1, Mean: 2}},
+		Columns: []metrics.ColumnsMetric{{Date: date, ColumnAmounts: metrics.ColumnAmounts{{Name: "Develop", Amount: 3}, {Name: "Done", Amount: 4}}}},
+	}
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, wb.Write(buf))
+
+	z, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	files := make(map[string]string)
+	for _, f := range z.File {
+		r, err := f.Open()
+		require.NoError(t, err)
+		content, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+		files[f.Name] = string(content)
+	}
+
+	t.Run("writes a sheet per metric and the run info", func(t *testing.T) {
+		for _, name := range []string{"issues", "columns", "prs", "summary", "run info"} {
+			assert.Contains(t, files["xl/workbook.xml"], `<sheet name="`+name+`"`)
+		}
+	})
+
+	t.Run("writes column dates of issues as dates", func(t *testing.T) {
+		assert.Contains(t, files["xl/worksheets/sheet1.xml"], `<c r="E2" s="2"><v>43832</v></c>`)
+		assert.Contains(t, files["xl/worksheets/sheet1.xml"], `<c r="F2" s="2"><v>43834</v></c>`)
+	})
+
+	t.Run("writes column counts as numbers", func(t *testing.T) {
+		assert.Contains(t, files["xl/worksheets/sheet2.xml"], `<c r="B2"><v>3</v></c><c r="C2"><v>4</v></c>`)
+	})
+
+	t.Run("writes the run window and stages", func(t *testing.T) {
+		runInfo := files["xl/worksheets/sheet5.xml"]
+		assert.Contains(t, runInfo, `<t xml:space="preserve">Stage: Review</t>`)
+		assert.Contains(t, runInfo, `<t xml:space="preserve">Code Review, QA</t>`)
+		assert.Contains(t, runInfo, `<c r="B5" s="2"><v>43832</v></c>`)
+	})
+}
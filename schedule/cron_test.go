@@ -0,0 +1,75 @@
+package schedule_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/3xcellent/github-metrics/schedule"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCron(t *testing.T) {
+	t.Run("accepts lists, ranges, steps and macros", func(t *testing.T) {
+		for _, expr := range []string{"0 6 1 * *", "*/15 8-18 * * 1-5", "0,30 * * 1,6 7", "@daily", "@monthly"} {
+			_, err := schedule.ParseCron(expr)
+			assert.NoError(t, err, expr)
+		}
+	})
+
+	t.Run("rejects invalid expressions", func(t *testing.T) {
+		for _, expr := range []string{"", "0 6 1 *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * * 8", "*/0 * * * *", "5-1 * * * *", "a * * * *"} {
+			_, err := schedule.ParseCron(expr)
+			assert.Error(t, err, expr)
+		}
+	})
+}
+
+func TestCron_Next(t *testing.T) {
+	from := time.Date(2001, 2, 10, 12, 30, 45, 0, time.Local)
+
+	t.Run("returns the next scheduled minute", func(t *testing.T) {
+		cron, err := schedule.ParseCron("0 6 1 * *")
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2001, 3, 1, 6, 0, 0, 0, time.Local), cron.Next(from))
+	})
+
+	t.Run("is after the given time", func(t *testing.T) {
+		cron, err := schedule.ParseCron("* * * * *")
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2001, 2, 10, 12, 31, 0, 0, time.Local), cron.Next(from))
+	})
+
+	t.Run("matches either day when both day fields are restricted", func(t *testing.T) {
+		cron, err := schedule.ParseCron("0 0 20 * 1")
+		require.NoError(t, err)
+		// the 12th is a monday
+		assert.Equal(t, time.Date(2001, 2, 12, 0, 0, 0, 0, time.Local), cron.Next(from))
+	})
+
+	t.Run("treats day of week 7 as sunday", func(t *testing.T) {
+		cron, err := schedule.ParseCron("0 0 * * 7")
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2001, 2, 11, 0, 0, 0, 0, time.Local), cron.Next(from))
+	})
+
+	t.Run("returns the zero time when never scheduled", func(t *testing.T) {
+		cron, err := schedule.ParseCron("0 0 31 2 *")
+		require.NoError(t, err)
+		assert.True(t, cron.Next(from).IsZero())
+	})
+}
+
+func TestCron_Previous(t *testing.T) {
+	cron, err := schedule.ParseCron("0 6 1 * *")
+	require.NoError(t, err)
+
+	t.Run("returns the last scheduled minute", func(t *testing.T) {
+		assert.Equal(t, time.Date(2001, 2, 1, 6, 0, 0, 0, time.Local), cron.Previous(time.Date(2001, 2, 10, 12, 0, 0, 0, time.Local)))
+	})
+
+	t.Run("includes the given minute", func(t *testing.T) {
+		at := time.Date(2001, 2, 1, 6, 0, 0, 0, time.Local)
+		assert.Equal(t, at, cron.Previous(at))
+	})
+}
@@ -0,0 +1,87 @@
+package schedule
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// maxRecords - number of the most recent records kept in the history
+const maxRecords = 1000
+
+// Record - a scheduled run
+type Record struct {
+	Schedule    string    `json:"schedule"`
+	ScheduledAt time.Time `json:"scheduledAt"`
+	StartedAt   time.Time `json:"startedAt"`
+	FinishedAt  time.Time `json:"finishedAt"`
+	Attempts    int       `json:"attempts"`
+	Files       []string  `json:"files"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// History - the records of scheduled runs, saved as json
+type History struct {
+	path    string
+	Records []Record `json:"records"`
+}
+
+// LoadHistory - loads the history saved at path, a missing file is an empty history
+func LoadHistory(path string) (*History, error) {
+	h := &History{path: path}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading schedule history %s", path)
+	}
+	if err = json.Unmarshal(data, h); err != nil {
+		return nil, errors.Wrapf(err, "reading schedule history %s", path)
+	}
+	return h, nil
+}
+
+// Save - writes the history to its path, replacing the previous file only once fully written
+func (h *History) Save() error {
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(h.path), filepath.Base(h.path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), h.path)
+}
+
+// Add - appends the record, dropping the oldest records over maxRecords
+func (h *History) Add(record Record) {
+	h.Records = append(h.Records, record)
+	if len(h.Records) > maxRecords {
+		h.Records = h.Records[len(h.Records)-maxRecords:]
+	}
+}
+
+// Last - returns the most recent record of the schedule
+func (h *History) Last(schedule string) (Record, bool) {
+	for idx := len(h.Records) - 1; idx >= 0; idx-- {
+		if h.Records[idx].Schedule == schedule {
+			return h.Records[idx], true
+		}
+	}
+	return Record{}, false
+}
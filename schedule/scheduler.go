@@ -0,0 +1,230 @@
+// Package schedule runs the metrics of RunConfigs on cron schedules defined in the config, retrying failed runs
+// and keeping a history of runs
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/3xcellent/github-metrics/apperrors"
+	"github.com/3xcellent/github-metrics/batch"
+	"github.com/3xcellent/github-metrics/config"
+	"github.com/3xcellent/github-metrics/logging"
+	"github.com/3xcellent/github-metrics/metrics/runners"
+	"github.com/3xcellent/github-metrics/output"
+	"github.com/pkg/errors"
+)
+
+var logger = logging.For("schedule")
+
+// Scheduler - runs the Schedules of Config that are due
+type Scheduler struct {
+	Client runners.Client
+	// Profiles - the clients of RunConfigs with a connection profile, Client is used for the others
+	Profiles runners.ProfileClients
+	Config   *config.AppConfig
+	History  *History
+	// Now - returns the current time, time.Now by default
+	Now func() time.Time
+}
+
+// Job - a schedule due to run for the time it was scheduled at
+type Job struct {
+	Schedule    config.Schedule
+	ScheduledAt time.Time
+}
+
+// New - returns a Scheduler of the cfg's Schedules
+func New(client runners.Client, cfg *config.AppConfig, history *History) *Scheduler {
+	return &Scheduler{Client: client, Config: cfg, History: history, Now: time.Now}
+}
+
+// Validate - returns an error of the config for the first schedule that cannot be run
+func (s *Scheduler) Validate() error {
+	return apperrors.Wrap(apperrors.Config, s.validate(), "")
+}
+
+func (s *Scheduler) validate() error {
+	names := make(map[string]bool)
+	for _, schedule := range s.Config.Schedules {
+		if schedule.Name == "" {
+			return errors.New("schedule name required")
+		}
+		if names[schedule.Name] {
+			return fmt.Errorf("schedule %q: duplicate name", schedule.Name)
+		}
+		names[schedule.Name] = true
+		if _, err := ParseCron(schedule.Cron); err != nil {
+			return errors.Wrapf(err, "schedule %q", schedule.Name)
+		}
+		if len(schedule.RunConfigs) == 0 || len(schedule.Metrics) == 0 {
+			return fmt.Errorf("schedule %q: runConfigs and metrics required", schedule.Name)
+		}
+		for _, name := range schedule.RunConfigs {
+			if _, err := s.Config.GetRunConfig(name); err != nil {
+				return errors.Wrapf(err, "schedule %q: %s", schedule.Name, name)
+			}
+		}
+		if _, err := scheduleFormat(schedule); err != nil {
+			return errors.Wrapf(err, "schedule %q", schedule.Name)
+		}
+		if _, err := batch.New(s.Client, output.CSV, "", schedule.Filename); err != nil {
+			return errors.Wrapf(err, "schedule %q", schedule.Name)
+		}
+		switch schedule.Window {
+		case "", config.WindowPreviousMonth, config.WindowMonthToDate:
+		default:
+			return fmt.Errorf("schedule %q: unknown window %q", schedule.Name, schedule.Window)
+		}
+	}
+	return nil
+}
+
+// Due - returns the schedules with a scheduled time after their last run, up to now; a schedule that has never
+// run is due for its latest scheduled time within the last year, missed times are run once
+func (s *Scheduler) Due(now time.Time) ([]Job, error) {
+	jobs := make([]Job, 0)
+	for _, schedule := range s.Config.Schedules {
+		cron, err := ParseCron(schedule.Cron)
+		if err != nil {
+			return nil, errors.Wrapf(err, "schedule %q", schedule.Name)
+		}
+
+		last, found := s.History.Last(schedule.Name)
+		if found && cron.Next(last.ScheduledAt).After(now) {
+			continue
+		}
+		scheduledAt := cron.Previous(now)
+		if scheduledAt.IsZero() || (found && !scheduledAt.After(last.ScheduledAt)) {
+			continue
+		}
+		jobs = append(jobs, Job{Schedule: schedule, ScheduledAt: scheduledAt})
+	}
+	return jobs, nil
+}
+
+// RunDue - runs the schedules that are due, adds their records to the history and saves it; when ctx is done the
+// failed run is not recorded, so the schedule is still due, and ctx's error is returned
+func (s *Scheduler) RunDue(ctx context.Context) ([]Record, error) {
+	jobs, err := s.Due(s.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]Record, 0, len(jobs))
+	for _, job := range jobs {
+		record := s.Run(ctx, job)
+		if record.Error != "" && ctx.Err() != nil {
+			logger.Infof("schedule %q stopped, it runs again once due", job.Schedule.Name)
+			return records, ctx.Err()
+		}
+		s.History.Add(record)
+		if err := s.History.Save(); err != nil {
+			return records, err
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// Daemon - runs the schedules that are due at the start of every minute until ctx is done
+func (s *Scheduler) Daemon(ctx context.Context) error {
+	for {
+		if _, err := s.RunDue(ctx); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		now := s.Now()
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(now.Truncate(time.Minute).Add(time.Minute).Sub(now)):
+		}
+	}
+}
+
+// Run - runs the job, retrying it up to its schedule's Retries times
+func (s *Scheduler) Run(ctx context.Context, job Job) Record {
+	record := Record{Schedule: job.Schedule.Name, ScheduledAt: job.ScheduledAt, StartedAt: s.Now()}
+	for {
+		record.Attempts++
+		files, err := s.runOnce(ctx, job)
+		record.Files = files
+		record.Error = ""
+		if err == nil {
+			break
+		}
+
+		record.Error = err.Error()
+		logger.Warnf("schedule %q attempt %d failed: %v", job.Schedule.Name, record.Attempts, err)
+		if record.Attempts > job.Schedule.Retries || ctx.Err() != nil {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			record.FinishedAt = s.Now()
+			return record
+		case <-time.After(job.Schedule.RetryDelay):
+		}
+	}
+	record.FinishedAt = s.Now()
+	return record
+}
+
+// runOnce - runs each metric of each RunConfig of the job and writes them to the job's destination
+func (s *Scheduler) runOnce(ctx context.Context, job Job) ([]string, error) {
+	format, err := scheduleFormat(job.Schedule)
+	if err != nil {
+		return nil, err
+	}
+	b, err := batch.New(s.Client, format, job.Schedule.Destination, job.Schedule.Filename)
+	if err != nil {
+		return nil, err
+	}
+	b.Profiles = s.Profiles
+	start, end := window(job.Schedule.Window, job.ScheduledAt)
+
+	runConfigs := make(config.RunConfigs, 0, len(job.Schedule.RunConfigs))
+	for _, name := range job.Schedule.RunConfigs {
+		runCfg, err := s.Config.GetRunConfig(name)
+		if err != nil {
+			return nil, err
+		}
+		runCfg.StartDate = start
+		runCfg.EndDate = end
+		runConfigs = append(runConfigs, runCfg)
+	}
+
+	results := b.Run(ctx, runConfigs, job.Schedule.Metrics)
+	files := make([]string, 0, len(results))
+	for _, result := range results {
+		if result.Err == nil {
+			logger.Infof("schedule %q wrote %s", job.Schedule.Name, result.File)
+			files = append(files, result.File)
+		}
+	}
+	if failed := batch.Failed(results); len(failed) > 0 {
+		return files, errors.Wrapf(failed[0].Err, "running %s %s", failed[0].Board, failed[0].Metric)
+	}
+	return files, nil
+}
+
+// window - returns the start and end dates of a run scheduled at
+func window(name string, scheduledAt time.Time) (time.Time, time.Time) {
+	month := time.Date(scheduledAt.Year(), scheduledAt.Month(), 1, 0, 0, 0, 0, scheduledAt.Location())
+	if name == config.WindowMonthToDate {
+		return month, time.Date(scheduledAt.Year(), scheduledAt.Month(), scheduledAt.Day()+1, 0, 0, 0, 0, scheduledAt.Location())
+	}
+	return month.AddDate(0, -1, 0), month
+}
+
+func scheduleFormat(schedule config.Schedule) (output.Format, error) {
+	if schedule.Format == "" {
+		return output.CSV, nil
+	}
+	return output.ParseFormat(schedule.Format, output.CSV, output.JSON, output.NDJSON)
+}
@@ -0,0 +1,193 @@
+package schedule_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/3xcellent/github-metrics/config"
+	"github.com/3xcellent/github-metrics/metrics/runners/runnersfakes"
+	"github.com/3xcellent/github-metrics/models"
+	"github.com/3xcellent/github-metrics/schedule"
+	"github.com/3xcellent/github-metrics/tools/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const projectID = int64(123)
+
+var cols = testhelpers.NewProjectColumns(3)
+
+func newFakeClient() *runnersfakes.FakeClient {
+	fakeClient := new(runnersfakes.FakeClient)
+	fakeClient.GetProjectReturns(models.Project{ID: projectID, Name: "Board"}, nil)
+	fakeClient.GetProjectColumnsReturns(cols, nil)
+	fakeClient.GetReposFromProjectColumnReturns(models.Repositories{{Name: "repo"}}, nil)
+	fakeClient.GetIssuesReturns(models.Issues{}, nil)
+	return fakeClient
+}
+
+func newScheduler(t *testing.T, fakeClient *runnersfakes.FakeClient, s config.Schedule) (*schedule.Scheduler, string, func()) {
+	dir, err := ioutil.TempDir("", "schedule")
+	require.NoError(t, err)
+
+	s.Destination = filepath.Join(dir, "out")
+	cfg := &config.AppConfig{
+		RunConfigs: config.RunConfigs{{
+			Name:        "My Board",
+			ProjectID:   projectID,
+			Owner:       "owner",
+			StartColumn: cols[1].Name,
+			EndColumn:   cols[2].Name,
+		}},
+		Schedules: []config.Schedule{s},
+	}
+	history, err := schedule.LoadHistory(filepath.Join(dir, "history.json"))
+	require.NoError(t, err)
+
+	scheduler := schedule.New(fakeClient, cfg, history)
+	return scheduler, dir, func() { os.RemoveAll(dir) }
+}
+
+func TestScheduler_Due(t *testing.T) {
+	scheduler, _, cleanup := newScheduler(t, newFakeClient(), config.Schedule{Name: "monthly", Cron: "0 6 1 * *"})
+	defer cleanup()
+	now := time.Date(2001, 2, 10, 12, 0, 0, 0, time.Local)
+	scheduledAt := time.Date(2001, 2, 1, 6, 0, 0, 0, time.Local)
+
+	t.Run("a schedule that has never run is due for its latest time", func(t *testing.T) {
+		jobs, err := scheduler.Due(now)
+		require.NoError(t, err)
+		require.Len(t, jobs, 1)
+		assert.Equal(t, scheduledAt, jobs[0].ScheduledAt)
+	})
+
+	t.Run("a schedule is not due again until its next time", func(t *testing.T) {
+		scheduler.History.Add(schedule.Record{Schedule: "monthly", ScheduledAt: scheduledAt})
+
+		jobs, err := scheduler.Due(now)
+		require.NoError(t, err)
+		assert.Empty(t, jobs)
+
+		jobs, err = scheduler.Due(time.Date(2001, 3, 1, 6, 0, 0, 0, time.Local))
+		require.NoError(t, err)
+		assert.Len(t, jobs, 1)
+	})
+
+	t.Run("missed times are run once", func(t *testing.T) {
+		jobs, err := scheduler.Due(time.Date(2001, 6, 10, 0, 0, 0, 0, time.Local))
+		require.NoError(t, err)
+		require.Len(t, jobs, 1)
+		assert.Equal(t, time.Date(2001, 6, 1, 6, 0, 0, 0, time.Local), jobs[0].ScheduledAt)
+	})
+}
+
+func TestScheduler_RunDue(t *testing.T) {
+	now := time.Date(2001, 2, 10, 12, 0, 0, 0, time.Local)
+
+	t.Run("writes each metric of the previous month and records the run", func(t *testing.T) {
+		fakeClient := newFakeClient()
+		scheduler, dir, cleanup := newScheduler(t, fakeClient, config.Schedule{
+			Name:       "monthly",
+			Cron:       "0 6 1 * *",
+			RunConfigs: []string{"My Board"},
+			Metrics:    []string{"issues", "columns"},
+			Format:     "json",
+		})
+		defer cleanup()
+		scheduler.Now = func() time.Time { return now }
+		require.NoError(t, scheduler.Validate())
+
+		records, err := scheduler.RunDue(context.Background())
+		require.NoError(t, err)
+		require.Len(t, records, 1)
+		assert.Empty(t, records[0].Error)
+		assert.Equal(t, 1, records[0].Attempts)
+		assert.Equal(t, []string{
+			filepath.Join(dir, "out", "My_Board_issues_2001-01.json"),
+			filepath.Join(dir, "out", "My_Board_columns_2001-01.json"),
+		}, records[0].Files)
+		for _, file := range records[0].Files {
+			assert.FileExists(t, file)
+		}
+
+		_, _, _, begin, end := fakeClient.GetIssuesArgsForCall(0)
+		assert.Equal(t, time.Date(2001, 1, 1, 0, 0, 0, 0, time.Local), begin)
+		assert.Equal(t, time.Date(2001, 2, 1, 0, 0, 0, 0, time.Local), end)
+
+		history, err := schedule.LoadHistory(filepath.Join(dir, "history.json"))
+		require.NoError(t, err)
+		last, found := history.Last("monthly")
+		require.True(t, found)
+		assert.Equal(t, records[0].ScheduledAt.Unix(), last.ScheduledAt.Unix())
+	})
+
+	t.Run("retries failed runs and records the error", func(t *testing.T) {
+		fakeClient := newFakeClient()
+		fakeClient.GetProjectReturns(models.Project{}, errors.New("rate limited"))
+		scheduler, _, cleanup := newScheduler(t, fakeClient, config.Schedule{
+			Name:       "monthly",
+			Cron:       "0 6 1 * *",
+			RunConfigs: []string{"My Board"},
+			Metrics:    []string{"issues"},
+			Retries:    2,
+		})
+		defer cleanup()
+		scheduler.Now = func() time.Time { return now }
+
+		records, err := scheduler.RunDue(context.Background())
+		require.NoError(t, err)
+		require.Len(t, records, 1)
+		assert.Equal(t, 3, records[0].Attempts)
+		assert.Contains(t, records[0].Error, "rate limited")
+	})
+
+	t.Run("does not record runs stopped by ctx, so they are still due", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		fakeClient := newFakeClient()
+		fakeClient.GetProjectStub = func(context.Context, int64) (models.Project, error) {
+			cancel()
+			return models.Project{}, context.Canceled
+		}
+		scheduler, _, cleanup := newScheduler(t, fakeClient, config.Schedule{
+			Name:       "monthly",
+			Cron:       "0 6 1 * *",
+			RunConfigs: []string{"My Board"},
+			Metrics:    []string{"issues"},
+			Retries:    2,
+		})
+		defer cleanup()
+		scheduler.Now = func() time.Time { return now }
+
+		records, err := scheduler.RunDue(ctx)
+		assert.Equal(t, context.Canceled, err)
+		assert.Empty(t, records)
+		assert.Equal(t, 1, fakeClient.GetProjectCallCount())
+		_, found := scheduler.History.Last("monthly")
+		assert.False(t, found)
+
+		jobs, err := scheduler.Due(now)
+		require.NoError(t, err)
+		assert.Len(t, jobs, 1)
+	})
+}
+
+func TestScheduler_Validate(t *testing.T) {
+	for name, s := range map[string]config.Schedule{
+		"invalid cron":       {Name: "s", Cron: "* *", RunConfigs: []string{"My Board"}, Metrics: []string{"issues"}},
+		"unknown run config": {Name: "s", Cron: "@daily", RunConfigs: []string{"other"}, Metrics: []string{"issues"}},
+		"no metrics":         {Name: "s", Cron: "@daily", RunConfigs: []string{"My Board"}},
+		"unknown format":     {Name: "s", Cron: "@daily", RunConfigs: []string{"My Board"}, Metrics: []string{"issues"}, Format: "xml"},
+		"unknown window":     {Name: "s", Cron: "@daily", RunConfigs: []string{"My Board"}, Metrics: []string{"issues"}, Window: "lastYear"},
+	} {
+		t.Run("rejects "+name, func(t *testing.T) {
+			scheduler, _, cleanup := newScheduler(t, newFakeClient(), s)
+			defer cleanup()
+			assert.Error(t, scheduler.Validate())
+		})
+	}
+}
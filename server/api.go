@@ -0,0 +1,464 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/3xcellent/github-metrics/config"
+	"github.com/3xcellent/github-metrics/metrics/runners"
+	"github.com/3xcellent/github-metrics/models"
+	"github.com/3xcellent/github-metrics/output"
+)
+
+// maxRuns - number of runs kept, the oldest finished runs are dropped first
+const maxRuns = 100
+
+// default limits of the runs of an API
+const (
+	defaultMaxActiveRuns = 2
+	defaultMaxQueuedRuns = 20
+)
+
+// ErrTooManyRuns - returned by Start when MaxQueuedRuns runs are pending or running
+var ErrTooManyRuns = errors.New("too many runs pending, try again once some are done")
+
+// windowDateFormat - format of the dates of a run's window
+const windowDateFormat = "2006-01-02"
+
+// run statuses
+const (
+	RunPending = "pending"
+	RunRunning = "running"
+	RunDone    = "done"
+	RunFailed  = "failed"
+)
+
+// API - json endpoints listing projects and run configs, and running metrics asynchronously
+type API struct {
+	Client runners.Client
+	// Profiles - the clients of RunConfigs with a connection profile, Client is used for the others
+	Profiles runners.ProfileClients
+	// Owner - the organization whose projects /projects lists
+	Owner      string
+	RunConfigs config.RunConfigs
+	// Now - returns the time a run without a window ends on, time.Now by default
+	Now func() time.Time
+	// Context - the context runs are run with, cancelling it cancels the runs; context.Background by default
+	Context context.Context
+	// MaxActiveRuns - number of runs running at once, the others stay pending; MaxQueuedRuns - number of runs
+	// pending or running, more are rejected so requests cannot use up the github rate limit
+	MaxActiveRuns int
+	MaxQueuedRuns int
+
+	mu    sync.RWMutex
+	runs  map[string]*Run
+	order []string
+	wg    sync.WaitGroup
+	// slots - holds a value for each run running
+	slots chan struct{}
+}
+
+// RunRequest - the body of POST /runs
+type RunRequest struct {
+	Metric    string `json:"metric"`
+	RunConfig string `json:"runConfig"`
+	Window    Window `json:"window"`
+}
+
+// Window - the dates a run covers, yyyy-mm-dd; the end date is excluded. The current month up to and including
+// today when blank, matching the command line default
+type Window struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// Run - a metric run requested through the api
+type Run struct {
+	ID         string    `json:"id"`
+	Metric     string    `json:"metric"`
+	RunConfig  string    `json:"runConfig"`
+	StartDate  time.Time `json:"startDate"`
+	EndDate    time.Time `json:"endDate"`
+	Status     string    `json:"status"`
+	Progress   Progress  `json:"progress"`
+	Error      string    `json:"error,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+	StartedAt  time.Time `json:"startedAt"`
+	FinishedAt time.Time `json:"finishedAt"`
+
+	runner   runners.MetricsRunner
+	progress *progressClient
+}
+
+// Progress - counts of the github data a run has fetched; a run is done fetching events once EventsFetched
+// reaches Issues
+type Progress struct {
+	Issues        int64 `json:"issues"`
+	EventsFetched int64 `json:"eventsFetched"`
+}
+
+// runConfigInfo - a run config as listed by /runconfigs
+type runConfigInfo struct {
+	Name        string `json:"name"`
+	Owner       string `json:"owner"`
+	ProjectID   int64  `json:"projectId"`
+	StartColumn string `json:"startColumn"`
+	EndColumn   string `json:"endColumn"`
+}
+
+// NewAPI - returns an API running runConfigs with client
+func NewAPI(client runners.Client, owner string, runConfigs config.RunConfigs) *API {
+	return &API{
+		Client:        client,
+		Owner:         owner,
+		RunConfigs:    runConfigs,
+		Now:           time.Now,
+		Context:       context.Background(),
+		MaxActiveRuns: defaultMaxActiveRuns,
+		MaxQueuedRuns: defaultMaxQueuedRuns,
+		runs:          make(map[string]*Run),
+	}
+}
+
+// Register - adds the api's endpoints to mux
+func (a *API) Register(mux *http.ServeMux) {
+	mux.HandleFunc("/projects", a.serveProjects)
+	mux.HandleFunc("/runconfigs", a.serveRunConfigs)
+	mux.HandleFunc("/runs", a.serveRuns)
+	mux.HandleFunc("/runs/", a.serveRun)
+}
+
+// Wait - waits for the runs started to finish
+func (a *API) Wait() {
+	a.wg.Wait()
+}
+
+// Start - validates the request and runs it in the background once fewer than MaxActiveRuns are running, returning
+// the pending run; ErrTooManyRuns when MaxQueuedRuns are already pending or running
+func (a *API) Start(req RunRequest) (Run, error) {
+	runCfg, found := a.runConfig(req.RunConfig)
+	if !found {
+		return Run{}, fmt.Errorf("unknown runConfig %q", req.RunConfig)
+	}
+	start, end, err := a.window(req.Window)
+	if err != nil {
+		return Run{}, err
+	}
+	runCfg.MetricName = req.Metric
+	runCfg.StartDate = start
+	runCfg.EndDate = end
+
+	progress := &progressClient{Client: a.Profiles.Get(runCfg.Profile, a.Client)}
+	runner, err := runners.New(runCfg, progress)
+	if err != nil {
+		return Run{}, fmt.Errorf("unknown metric %q", req.Metric)
+	}
+
+	id, err := newRunID()
+	if err != nil {
+		return Run{}, err
+	}
+	run := &Run{
+		ID:        id,
+		Metric:    req.Metric,
+		RunConfig: runCfg.Name,
+		StartDate: start,
+		EndDate:   end,
+		Status:    RunPending,
+		CreatedAt: time.Now(),
+		runner:    runner,
+		progress:  progress,
+	}
+
+	a.mu.Lock()
+	if a.unfinished() >= a.MaxQueuedRuns {
+		a.mu.Unlock()
+		return Run{}, ErrTooManyRuns
+	}
+	if a.slots == nil {
+		a.slots = make(chan struct{}, a.MaxActiveRuns)
+	}
+	a.runs[id] = run
+	a.order = append(a.order, id)
+	a.prune()
+	pending := a.snapshot(run)
+	a.mu.Unlock()
+
+	a.wg.Add(1)
+	go a.execute(run)
+	return pending, nil
+}
+
+// Get - returns a copy of the run with id
+func (a *API) Get(id string) (Run, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	run, found := a.runs[id]
+	if !found {
+		return Run{}, false
+	}
+	return a.snapshot(run), true
+}
+
+func (a *API) execute(run *Run) {
+	defer a.wg.Done()
+
+	var err error
+	select {
+	case a.slots <- struct{}{}:
+		a.mu.Lock()
+		run.Status = RunRunning
+		run.StartedAt = time.Now()
+		a.mu.Unlock()
+
+		err = run.runner.Run(a.Context)
+		<-a.slots
+	case <-a.Context.Done():
+		err = a.Context.Err()
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	run.FinishedAt = time.Now()
+	if err != nil {
+		logger.Errorf("run %s of %s %s: %v", run.ID, run.RunConfig, run.Metric, err)
+		run.Status = RunFailed
+		run.Error = err.Error()
+		return
+	}
+	run.Status = RunDone
+}
+
+// snapshot - returns a copy of run with its current progress, a.mu must be held
+func (a *API) snapshot(run *Run) Run {
+	copied := *run
+	copied.Progress = Progress{
+		Issues:        atomic.LoadInt64(&run.progress.issues),
+		EventsFetched: atomic.LoadInt64(&run.progress.events),
+	}
+	return copied
+}
+
+// unfinished - returns the number of runs pending or running, a.mu must be held
+func (a *API) unfinished() int {
+	count := 0
+	for _, run := range a.runs {
+		if run.Status == RunPending || run.Status == RunRunning {
+			count++
+		}
+	}
+	return count
+}
+
+// prune - drops the oldest finished runs over maxRuns, a.mu must be held
+func (a *API) prune() {
+	for idx := 0; len(a.order) > maxRuns && idx < len(a.order); {
+		run := a.runs[a.order[idx]]
+		if run.Status != RunDone && run.Status != RunFailed {
+			idx++
+			continue
+		}
+		delete(a.runs, run.ID)
+		a.order = append(a.order[:idx], a.order[idx+1:]...)
+	}
+}
+
+func (a *API) runConfig(name string) (config.RunConfig, bool) {
+	for _, runCfg := range a.RunConfigs {
+		if runCfg.Name == name {
+			return runCfg, true
+		}
+	}
+	return config.RunConfig{}, false
+}
+
+func (a *API) window(w Window) (time.Time, time.Time, error) {
+	now := a.Now()
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+	end := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.Local)
+
+	var err error
+	if w.Start != "" {
+		if start, err = time.ParseInLocation(windowDateFormat, w.Start, time.Local); err != nil {
+			return start, end, fmt.Errorf("invalid window start %q: expected yyyy-mm-dd", w.Start)
+		}
+	}
+	if w.End != "" {
+		if end, err = time.ParseInLocation(windowDateFormat, w.End, time.Local); err != nil {
+			return start, end, fmt.Errorf("invalid window end %q: expected yyyy-mm-dd", w.End)
+		}
+	}
+	if !start.Before(end) {
+		return start, end, fmt.Errorf("window start %s must be before its end %s", start.Format(windowDateFormat), end.Format(windowDateFormat))
+	}
+	return start, end, nil
+}
+
+func (a *API) serveProjects(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		return
+	}
+	owner := r.URL.Query().Get("owner")
+	if owner == "" {
+		owner = a.Owner
+	}
+	projects, err := a.Client.GetProjects(r.Context(), owner)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	if projects == nil {
+		projects = models.Projects{}
+	}
+	writeJSON(w, http.StatusOK, projects)
+}
+
+func (a *API) serveRunConfigs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		return
+	}
+	infos := make([]runConfigInfo, 0, len(a.RunConfigs))
+	for _, runCfg := range a.RunConfigs {
+		infos = append(infos, runConfigInfo{
+			Name:        runCfg.Name,
+			Owner:       runCfg.Owner,
+			ProjectID:   runCfg.ProjectID,
+			StartColumn: runCfg.StartColumn,
+			EndColumn:   runCfg.EndColumn,
+		})
+	}
+	writeJSON(w, http.StatusOK, infos)
+}
+
+func (a *API) serveRuns(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		a.mu.RLock()
+		runs := make([]Run, 0, len(a.order))
+		for _, id := range a.order {
+			runs = append(runs, a.snapshot(a.runs[id]))
+		}
+		a.mu.RUnlock()
+		writeJSON(w, http.StatusOK, runs)
+	case http.MethodPost:
+		var req RunRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid run request: %v", err))
+			return
+		}
+		run, err := a.Start(req)
+		if err == ErrTooManyRuns {
+			writeError(w, http.StatusTooManyRequests, err)
+			return
+		}
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		w.Header().Set("Location", "/runs/"+run.ID)
+		writeJSON(w, http.StatusAccepted, run)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+	}
+}
+
+// serveRun - serves /runs/{id} and /runs/{id}/result
+func (a *API) serveRun(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		return
+	}
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/runs/"), "/")
+	if len(parts) > 2 || (len(parts) == 2 && parts[1] != "result") {
+		writeError(w, http.StatusNotFound, fmt.Errorf("%s not found", r.URL.Path))
+		return
+	}
+	run, found := a.Get(parts[0])
+	if !found {
+		writeError(w, http.StatusNotFound, fmt.Errorf("run %q not found", parts[0]))
+		return
+	}
+	if len(parts) == 1 {
+		writeJSON(w, http.StatusOK, run)
+		return
+	}
+
+	if run.Status != RunDone {
+		writeError(w, http.StatusConflict, fmt.Errorf("run %s is %s", run.ID, run.Status))
+		return
+	}
+	format, err := resultFormat(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if format == output.CSV {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	} else {
+		w.Header().Set("Content-Type", "application/json")
+	}
+	result := output.Result{Rows: run.runner.Values(), Records: run.runner.Results()}
+	if err := output.Write(w, format, result); err != nil {
+		logger.Errorf("writing result of run %s: %v", run.ID, err)
+	}
+}
+
+// resultFormat - returns the format of the format query parameter, or text/csv when accepted, json by default
+func resultFormat(r *http.Request) (output.Format, error) {
+	if name := r.URL.Query().Get("format"); name != "" {
+		return output.ParseFormat(name, output.JSON, output.CSV)
+	}
+	if strings.Contains(r.Header.Get("Accept"), "text/csv") {
+		return output.CSV, nil
+	}
+	return output.JSON, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logger.Errorf("writing response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func newRunID() (string, error) {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
+
+// progressClient - counts the issues and issue events a run fetches
+type progressClient struct {
+	runners.Client
+	issues, events int64
+}
+
+func (c *progressClient) GetIssues(ctx context.Context, repoOwner string, reposNames []string, beginDate, endDate time.Time) (models.Issues, error) {
+	issues, err := c.Client.GetIssues(ctx, repoOwner, reposNames, beginDate, endDate)
+	atomic.AddInt64(&c.issues, int64(len(issues)))
+	return issues, err
+}
+
+func (c *progressClient) GetIssueEvents(ctx context.Context, repoOwner, repoName string, issueNumber int) (models.IssueEvents, error) {
+	events, err := c.Client.GetIssueEvents(ctx, repoOwner, repoName, issueNumber)
+	atomic.AddInt64(&c.events, 1)
+	return events, err
+}
@@ -0,0 +1,243 @@
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/3xcellent/github-metrics/models"
+	"github.com/3xcellent/github-metrics/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newAPIServer(api *server.API) *httptest.Server {
+	srv := server.New(api.Client, nil, 7)
+	srv.API = api
+	return httptest.NewServer(srv.Handler())
+}
+
+func getJSON(t *testing.T, ts *httptest.Server, path string, v interface{}) *http.Response {
+	resp, err := ts.Client().Get(ts.URL + path)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(v))
+	return resp
+}
+
+func postRun(t *testing.T, ts *httptest.Server, body string) (*http.Response, map[string]interface{}) {
+	resp, err := ts.Client().Post(ts.URL+"/runs", "application/json", strings.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	var decoded map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&decoded))
+	return resp, decoded
+}
+
+func TestAPI_Lists(t *testing.T) {
+	fakeClient := newFakeClient()
+	fakeClient.GetProjectsReturns(models.Projects{{ID: projectID, Name: "Board", Owner: "owner"}}, nil)
+	ts := newAPIServer(server.NewAPI(fakeClient, "owner", runConfigs))
+	defer ts.Close()
+
+	t.Run("lists the projects of the owner", func(t *testing.T) {
+		var projects models.Projects
+		resp := getJSON(t, ts, "/projects", &projects)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, models.Projects{{ID: projectID, Name: "Board", Owner: "owner"}}, projects)
+		_, owner := fakeClient.GetProjectsArgsForCall(0)
+		assert.Equal(t, "owner", owner)
+	})
+
+	t.Run("lists the run configs", func(t *testing.T) {
+		var infos []map[string]interface{}
+		resp := getJSON(t, ts, "/runconfigs", &infos)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Len(t, infos, 1)
+		assert.Equal(t, "Board", infos[0]["name"])
+		assert.Equal(t, float64(projectID), infos[0]["projectId"])
+	})
+
+	t.Run("reports client errors", func(t *testing.T) {
+		fakeClient.GetProjectsReturns(nil, errors.New("bad credentials"))
+		var body map[string]string
+		resp := getJSON(t, ts, "/projects", &body)
+		assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+		assert.Equal(t, "bad credentials", body["error"])
+	})
+}
+
+func TestAPI_Runs(t *testing.T) {
+	fakeClient := newFakeClient()
+	api := server.NewAPI(fakeClient, "owner", runConfigs)
+	api.Now = func() time.Time { return now }
+	ts := newAPIServer(api)
+	defer ts.Close()
+
+	resp, run := postRun(t, ts, `{"metric":"issues","runConfig":"Board","window":{"start":"2001-02-01","end":"2001-02-11"}}`)
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+	id, _ := run["id"].(string)
+	require.NotEmpty(t, id)
+	api.Wait()
+
+	t.Run("returns the run with its location", func(t *testing.T) {
+		assert.Equal(t, "/runs/"+id, resp.Header.Get("Location"))
+		assert.Equal(t, "Board", run["runConfig"])
+		assert.Equal(t, "issues", run["metric"])
+	})
+
+	t.Run("runs over the window", func(t *testing.T) {
+		_, _, _, begin, end := fakeClient.GetIssuesArgsForCall(0)
+		assert.Equal(t, time.Date(2001, 2, 1, 0, 0, 0, 0, time.Local), begin)
+		assert.Equal(t, time.Date(2001, 2, 11, 0, 0, 0, 0, time.Local), end)
+	})
+
+	t.Run("reports the status and progress of the run", func(t *testing.T) {
+		var status server.Run
+		resp := getJSON(t, ts, "/runs/"+id, &status)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, server.RunDone, status.Status)
+		assert.Equal(t, server.Progress{Issues: 2, EventsFetched: 2}, status.Progress)
+	})
+
+	t.Run("returns the result as json by default", func(t *testing.T) {
+		var issues []map[string]interface{}
+		resp := getJSON(t, ts, "/runs/"+id+"/result", &issues)
+		assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+		assert.Len(t, issues, 1)
+	})
+
+	t.Run("returns the result as csv", func(t *testing.T) {
+		resp, err := ts.Client().Get(ts.URL + "/runs/" + id + "/result?format=csv")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, "text/csv; charset=utf-8", resp.Header.Get("Content-Type"))
+		assert.Len(t, strings.Split(strings.TrimSpace(string(body)), "\n"), 2)
+	})
+
+	t.Run("lists the runs", func(t *testing.T) {
+		var runs []server.Run
+		getJSON(t, ts, "/runs", &runs)
+		require.Len(t, runs, 1)
+		assert.Equal(t, id, runs[0].ID)
+	})
+
+	t.Run("returns not found for unknown runs", func(t *testing.T) {
+		var body map[string]string
+		resp := getJSON(t, ts, "/runs/unknown", &body)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
+
+func TestAPI_FailedRuns(t *testing.T) {
+	fakeClient := newFakeClient()
+	fakeClient.GetProjectReturns(models.Project{}, errors.New("rate limited"))
+	api := server.NewAPI(fakeClient, "owner", runConfigs)
+	ts := newAPIServer(api)
+	defer ts.Close()
+
+	t.Run("rejects invalid requests", func(t *testing.T) {
+		for _, body := range []string{
+			`{"metric":"issues","runConfig":"Other"}`,
+			`{"metric":"unknown","runConfig":"Board"}`,
+			`{"metric":"issues","runConfig":"Board","window":{"start":"02/01/2001"}}`,
+			`{"metric":"issues","runConfig":"Board","window":{"start":"2001-02-11","end":"2001-02-01"}}`,
+			`not json`,
+		} {
+			resp, decoded := postRun(t, ts, body)
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode, body)
+			assert.NotEmpty(t, decoded["error"], body)
+		}
+	})
+
+	t.Run("reports the error of a failed run", func(t *testing.T) {
+		_, run := postRun(t, ts, `{"metric":"columns","runConfig":"Board"}`)
+		api.Wait()
+		id, _ := run["id"].(string)
+
+		var status server.Run
+		getJSON(t, ts, "/runs/"+id, &status)
+		assert.Equal(t, server.RunFailed, status.Status)
+		assert.Equal(t, "rate limited", status.Error)
+
+		var body map[string]string
+		resp := getJSON(t, ts, "/runs/"+id+"/result", &body)
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	})
+}
+
+func TestAPI_Context(t *testing.T) {
+	fakeClient := newFakeClient()
+	fakeClient.GetProjectStub = func(ctx context.Context, id int64) (models.Project, error) {
+		return models.Project{ID: id, Name: "Board"}, ctx.Err()
+	}
+	api := server.NewAPI(fakeClient, "owner", runConfigs)
+	ctx, cancel := context.WithCancel(context.Background())
+	api.Context = ctx
+	ts := newAPIServer(api)
+	defer ts.Close()
+
+	t.Run("cancels runs with its context", func(t *testing.T) {
+		cancel()
+		_, run := postRun(t, ts, `{"metric":"issues","runConfig":"Board"}`)
+		api.Wait()
+		id, _ := run["id"].(string)
+
+		var status server.Run
+		getJSON(t, ts, "/runs/"+id, &status)
+		assert.Equal(t, server.RunFailed, status.Status)
+		assert.Equal(t, context.Canceled.Error(), status.Error)
+	})
+}
+
+func TestAPI_Limits(t *testing.T) {
+	release := make(chan struct{})
+	var releaseOnce sync.Once
+	fakeClient := newFakeClient()
+	fakeClient.GetProjectStub = func(ctx context.Context, id int64) (models.Project, error) {
+		<-release
+		return models.Project{ID: id, Name: "Board"}, nil
+	}
+	api := server.NewAPI(fakeClient, "owner", runConfigs)
+	api.MaxActiveRuns, api.MaxQueuedRuns = 1, 2
+	ts := newAPIServer(api)
+	defer ts.Close()
+	defer api.Wait()
+	// released before waiting for the runs, so a failed test does not wait for them
+	defer releaseOnce.Do(func() { close(release) })
+
+	status := func(id string) string {
+		run, _ := api.Get(id)
+		return run.Status
+	}
+
+	t.Run("leaves runs over MaxActiveRuns pending and rejects runs over MaxQueuedRuns", func(t *testing.T) {
+		_, first := postRun(t, ts, `{"metric":"issues","runConfig":"Board"}`)
+		_, second := postRun(t, ts, `{"metric":"issues","runConfig":"Board"}`)
+		firstID, _ := first["id"].(string)
+		secondID, _ := second["id"].(string)
+		require.Eventually(t, func() bool { return status(firstID) == server.RunRunning }, time.Second, time.Millisecond)
+		assert.Equal(t, server.RunPending, status(secondID))
+
+		resp, decoded := postRun(t, ts, `{"metric":"issues","runConfig":"Board"}`)
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		assert.Equal(t, server.ErrTooManyRuns.Error(), decoded["error"])
+
+		releaseOnce.Do(func() { close(release) })
+		api.Wait()
+		assert.Equal(t, server.RunDone, status(firstID))
+		assert.Equal(t, server.RunDone, status(secondID))
+
+		resp, _ = postRun(t, ts, `{"metric":"issues","runConfig":"Board"}`)
+		assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+	})
+}
@@ -0,0 +1,409 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/3xcellent/github-metrics/charts"
+	"github.com/3xcellent/github-metrics/config"
+	"github.com/3xcellent/github-metrics/metrics"
+	"github.com/3xcellent/github-metrics/metrics/runners"
+)
+
+// grafana target prefixes: columns.<board>.<column> is a daily time series of the issues in the column,
+// issues.<board> a table of the board's completed issues
+const (
+	columnsTarget = "columns."
+	issuesTarget  = "issues."
+)
+
+// Grafana - serves the grafana json datasource protocol (/, /search, /query and /annotations) over runs of
+// RunConfigs for the requested time range
+type Grafana struct {
+	Client runners.Client
+	// Profiles - the clients of RunConfigs with a connection profile, Client is used for the others
+	Profiles   runners.ProfileClients
+	RunConfigs config.RunConfigs
+	// TTL - how long the run of a board over a time range is reused by later queries
+	TTL time.Duration
+	// Now - returns the current time, time.Now by default
+	Now func() time.Time
+	// Context - the context runs are run with, shared by the queries waiting for them and cancelled when the server
+	// stops; context.Background by default
+	Context context.Context
+
+	// mu - guards cache and inFlight, not the runs, so a slow board does not hold up queries of the others
+	mu       sync.Mutex
+	cache    map[string]cachedRun
+	inFlight map[string]*pendingRun
+	wg       sync.WaitGroup
+}
+
+type cachedRun struct {
+	ranAt  time.Time
+	runner runners.MetricsRunner
+}
+
+// pendingRun - a run queries of the same board and dates wait for, done is closed once runner or err is set
+type pendingRun struct {
+	done   chan struct{}
+	runner runners.MetricsRunner
+	err    error
+}
+
+// wait - returns the runner of the run once done, or ctx's error when ctx is done first
+func (p *pendingRun) wait(ctx context.Context) (runners.MetricsRunner, error) {
+	select {
+	case <-p.done:
+		return p.runner, p.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+type grafanaRange struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+type grafanaTarget struct {
+	Target string `json:"target"`
+	RefID  string `json:"refId"`
+	Type   string `json:"type"`
+}
+
+type grafanaQuery struct {
+	Range   grafanaRange    `json:"range"`
+	Targets []grafanaTarget `json:"targets"`
+}
+
+type grafanaSeries struct {
+	Target     string       `json:"target"`
+	Datapoints [][2]float64 `json:"datapoints"`
+}
+
+type grafanaColumn struct {
+	Text string `json:"text"`
+	Type string `json:"type"`
+}
+
+type grafanaTable struct {
+	Type    string          `json:"type"`
+	Columns []grafanaColumn `json:"columns"`
+	Rows    [][]string      `json:"rows"`
+}
+
+type grafanaAnnotationQuery struct {
+	Range      grafanaRange `json:"range"`
+	Annotation struct {
+		Name  string `json:"name"`
+		Query string `json:"query"`
+	} `json:"annotation"`
+}
+
+type grafanaAnnotation struct {
+	Annotation interface{} `json:"annotation"`
+	Time       int64       `json:"time"`
+	Title      string      `json:"title"`
+	Text       string      `json:"text"`
+	Tags       []string    `json:"tags"`
+}
+
+// NewGrafana - returns a Grafana datasource running runConfigs with client
+func NewGrafana(client runners.Client, runConfigs config.RunConfigs, ttl time.Duration) *Grafana {
+	return &Grafana{
+		Client:     client,
+		RunConfigs: runConfigs,
+		TTL:        ttl,
+		Now:        time.Now,
+		Context:    context.Background(),
+		cache:      make(map[string]cachedRun),
+		inFlight:   make(map[string]*pendingRun),
+	}
+}
+
+// Wait - waits for the runs started by queries to finish
+func (g *Grafana) Wait() {
+	g.wg.Wait()
+}
+
+// Register - adds the datasource's endpoints to mux
+func (g *Grafana) Register(mux *http.ServeMux) {
+	mux.HandleFunc("/", g.serveRoot)
+	mux.HandleFunc("/search", g.serveSearch)
+	mux.HandleFunc("/query", g.serveQuery)
+	mux.HandleFunc("/annotations", g.serveAnnotations)
+}
+
+// serveRoot - answers grafana's connection test
+func (g *Grafana) serveRoot(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		writeError(w, http.StatusNotFound, fmt.Errorf("%s not found", r.URL.Path))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// search - returns the targets of every board matching query
+func (g *Grafana) search(ctx context.Context, query string) ([]string, error) {
+	targets := make([]string, 0)
+	for _, runCfg := range g.RunConfigs {
+		names, err := runners.NewBaseRunner(runCfg, g.Profiles.Get(runCfg.Profile, g.Client)).GetColumnNames(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			targets = append(targets, columnsTarget+runCfg.Name+"."+name)
+		}
+		targets = append(targets, issuesTarget+runCfg.Name)
+	}
+
+	matches := targets[:0]
+	for _, target := range targets {
+		if strings.Contains(target, query) {
+			matches = append(matches, target)
+		}
+	}
+	return matches, nil
+}
+
+// query - returns a time series for each columns target and a table for each issues target over the range
+func (g *Grafana) query(ctx context.Context, query grafanaQuery) ([]interface{}, error) {
+	start, end := g.window(query.Range)
+	results := make([]interface{}, 0, len(query.Targets))
+	for _, target := range query.Targets {
+		switch {
+		case strings.HasPrefix(target.Target, columnsTarget):
+			runCfg, column, found := g.columnsTarget(target.Target)
+			if !found {
+				return nil, fmt.Errorf("unknown target %q", target.Target)
+			}
+			runner, err := g.run(ctx, "columns", runCfg, start, end)
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, columnSeries(target.Target, column, runner.(*runners.ColumnsRunner), query.Range))
+		case strings.HasPrefix(target.Target, issuesTarget):
+			runCfg, found := g.runConfig(strings.TrimPrefix(target.Target, issuesTarget))
+			if !found {
+				return nil, fmt.Errorf("unknown target %q", target.Target)
+			}
+			runner, err := g.run(ctx, "issues", runCfg, start, end)
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, issuesTable(runner.(*runners.IssuesRunner)))
+		default:
+			return nil, fmt.Errorf("unknown target %q: must start with %q or %q", target.Target, columnsTarget, issuesTarget)
+		}
+	}
+	return results, nil
+}
+
+// annotations - returns the annotations of the boards named by query, or of every board when blank, within the range
+func (g *Grafana) annotations(query grafanaAnnotationQuery) ([]grafanaAnnotation, error) {
+	annotations := make([]grafanaAnnotation, 0)
+	for _, runCfg := range g.RunConfigs {
+		if query.Annotation.Query != "" && query.Annotation.Query != runCfg.Name {
+			continue
+		}
+		parsed, err := charts.Annotations(runCfg.Annotations, time.Local)
+		if err != nil {
+			return nil, err
+		}
+		for _, annotation := range parsed {
+			if annotation.Date.Before(query.Range.From) || annotation.Date.After(query.Range.To) {
+				continue
+			}
+			annotations = append(annotations, grafanaAnnotation{
+				Annotation: query.Annotation,
+				Time:       milliseconds(annotation.Date),
+				Title:      annotation.Label,
+				Text:       annotation.Label,
+				Tags:       []string{runCfg.Name},
+			})
+		}
+	}
+	return annotations, nil
+}
+
+// run - returns the runner of metric over the dates, reusing a run of the same board and dates within TTL; queries
+// of a board and dates being run wait for that run rather than starting another. The run is not tied to the query
+// that started it, only each query's wait follows its ctx
+func (g *Grafana) run(ctx context.Context, metric string, runCfg config.RunConfig, start, end time.Time) (runners.MetricsRunner, error) {
+	key := strings.Join([]string{metric, runCfg.Name, metrics.DateKey(start), metrics.DateKey(end)}, "|")
+
+	g.mu.Lock()
+	if cached, found := g.cache[key]; found && g.Now().Sub(cached.ranAt) < g.TTL {
+		g.mu.Unlock()
+		return cached.runner, nil
+	}
+	if pending, found := g.inFlight[key]; found {
+		g.mu.Unlock()
+		return pending.wait(ctx)
+	}
+	for k, cached := range g.cache {
+		if g.Now().Sub(cached.ranAt) >= g.TTL {
+			delete(g.cache, k)
+		}
+	}
+	pending := &pendingRun{done: make(chan struct{})}
+	g.inFlight[key] = pending
+	g.mu.Unlock()
+
+	g.wg.Add(1)
+	go g.runPending(key, pending, metric, runCfg, start, end)
+	return pending.wait(ctx)
+}
+
+// runPending - runs metric of the board over the dates with Context, caching the runner once it succeeds
+func (g *Grafana) runPending(key string, pending *pendingRun, metric string, runCfg config.RunConfig, start, end time.Time) {
+	defer g.wg.Done()
+	pending.runner, pending.err = g.runMetric(g.Context, metric, runCfg, start, end)
+
+	g.mu.Lock()
+	delete(g.inFlight, key)
+	if pending.err == nil {
+		g.cache[key] = cachedRun{ranAt: g.Now(), runner: pending.runner}
+	}
+	g.mu.Unlock()
+	close(pending.done)
+}
+
+// runMetric - runs metric of the board over the dates
+func (g *Grafana) runMetric(ctx context.Context, metric string, runCfg config.RunConfig, start, end time.Time) (runners.MetricsRunner, error) {
+	runCfg.MetricName = metric
+	runCfg.StartDate = start
+	runCfg.EndDate = end
+	runCfg.NoHeaders = false
+	runner, err := runners.New(runCfg, g.Profiles.Get(runCfg.Profile, g.Client))
+	if err != nil {
+		return nil, err
+	}
+	logger.Debugf("grafana running %s %s: %s - %s", runCfg.Name, metric, start, end)
+	if err := runner.Run(ctx); err != nil {
+		return nil, err
+	}
+	return runner, nil
+}
+
+// window - returns the days covering the range
+func (g *Grafana) window(r grafanaRange) (time.Time, time.Time) {
+	from, to := r.From.In(time.Local), r.To.In(time.Local)
+	return time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.Local),
+		time.Date(to.Year(), to.Month(), to.Day()+1, 0, 0, 0, 0, time.Local)
+}
+
+// columnsTarget - returns the run config and column of a columns.<board>.<column> target; board names may contain dots
+func (g *Grafana) columnsTarget(target string) (config.RunConfig, string, bool) {
+	rest := strings.TrimPrefix(target, columnsTarget)
+	for _, runCfg := range g.RunConfigs {
+		if strings.HasPrefix(rest, runCfg.Name+".") {
+			return runCfg, strings.TrimPrefix(rest, runCfg.Name+"."), true
+		}
+	}
+	return config.RunConfig{}, "", false
+}
+
+func (g *Grafana) runConfig(name string) (config.RunConfig, bool) {
+	for _, runCfg := range g.RunConfigs {
+		if runCfg.Name == name {
+			return runCfg, true
+		}
+	}
+	return config.RunConfig{}, false
+}
+
+// columnSeries - returns the number of issues in column at the start of each day within the range
+func columnSeries(target, column string, runner *runners.ColumnsRunner, r grafanaRange) grafanaSeries {
+	series := grafanaSeries{Target: target, Datapoints: make([][2]float64, 0)}
+	for _, day := range runner.ColumnsMetrics() {
+		if day.Date.After(r.To) {
+			continue
+		}
+		for _, amount := range day.ColumnAmounts {
+			if amount.Name == column {
+				series.Datapoints = append(series.Datapoints, [2]float64{float64(amount.Amount), float64(milliseconds(day.Date))})
+			}
+		}
+	}
+	return series
+}
+
+// issuesTable - returns the rows of the completed issues, with the csv headers as columns
+func issuesTable(runner *runners.IssuesRunner) grafanaTable {
+	table := grafanaTable{Type: "table", Columns: make([]grafanaColumn, 0), Rows: make([][]string, 0)}
+	rows := runner.Values()
+	if len(rows) == 0 {
+		return table
+	}
+	for _, header := range rows[0] {
+		table.Columns = append(table.Columns, grafanaColumn{Text: header, Type: "string"})
+	}
+	table.Rows = append(table.Rows, rows[1:]...)
+	return table
+}
+
+func milliseconds(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
+
+func (g *Grafana) serveSearch(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Target string `json:"target"`
+	}
+	if !decodePost(w, r, &req) {
+		return
+	}
+	targets, err := g.search(r.Context(), req.Target)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, targets)
+}
+
+func (g *Grafana) serveQuery(w http.ResponseWriter, r *http.Request) {
+	var req grafanaQuery
+	if !decodePost(w, r, &req) {
+		return
+	}
+	results, err := g.query(r.Context(), req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, results)
+}
+
+func (g *Grafana) serveAnnotations(w http.ResponseWriter, r *http.Request) {
+	var req grafanaAnnotationQuery
+	if !decodePost(w, r, &req) {
+		return
+	}
+	annotations, err := g.annotations(req)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, annotations)
+}
+
+// decodePost - decodes the json body of a POST request into v, writing an error response and returning false
+// when it cannot
+func decodePost(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		return false
+	}
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request: %v", err))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,229 @@
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/3xcellent/github-metrics/config"
+	"github.com/3xcellent/github-metrics/models"
+	"github.com/3xcellent/github-metrics/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func postJSON(t *testing.T, ts *httptest.Server, path, body string, v interface{}) *http.Response {
+	resp, err := ts.Client().Post(ts.URL+path, "application/json", strings.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(v))
+	return resp
+}
+
+func TestGrafana(t *testing.T) {
+	fakeClient := newFakeClient()
+	annotated := append(config.RunConfigs{}, runConfigs...)
+	annotated[0].Annotations = []config.Annotation{{Date: "2001-02-06", Label: "release"}, {Date: "2001-03-01", Label: "later"}}
+
+	srv := server.New(fakeClient, nil, 7)
+	srv.Grafana = server.NewGrafana(fakeClient, annotated, time.Minute)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	from := time.Date(2001, 2, 4, 0, 0, 0, 0, time.Local).UTC().Format(time.RFC3339)
+	to := time.Date(2001, 2, 8, 12, 0, 0, 0, time.Local).UTC().Format(time.RFC3339)
+	rangeJSON := `"range":{"from":"` + from + `","to":"` + to + `"}`
+
+	t.Run("answers the connection test", func(t *testing.T) {
+		resp, err := ts.Client().Get(ts.URL + "/")
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("searches the targets of the boards", func(t *testing.T) {
+		var targets []string
+		postJSON(t, ts, "/search", `{"target":""}`, &targets)
+		assert.Equal(t, []string{"columns.Board.col 1", "columns.Board.col 2", "issues.Board"}, targets)
+
+		postJSON(t, ts, "/search", `{"target":"issues"}`, &targets)
+		assert.Equal(t, []string{"issues.Board"}, targets)
+	})
+
+	t.Run("returns daily series of a column over the range", func(t *testing.T) {
+		var series []struct {
+			Target     string
+			Datapoints [][2]float64
+		}
+		postJSON(t, ts, "/query", `{`+rangeJSON+`,"targets":[{"target":"columns.Board.col 1","type":"timeserie"}]}`, &series)
+		require.Len(t, series, 1)
+		assert.Equal(t, "columns.Board.col 1", series[0].Target)
+
+		values := make([]float64, 0)
+		for _, point := range series[0].Datapoints {
+			values = append(values, point[0])
+		}
+		assert.Equal(t, []float64{0, 1, 2, 1, 1}, values)
+		assert.Equal(t, float64(time.Date(2001, 2, 4, 0, 0, 0, 0, time.Local).Unix()*1000), series[0].Datapoints[0][1])
+	})
+
+	t.Run("returns the completed issues as a table", func(t *testing.T) {
+		var tables []struct {
+			Type    string
+			Columns []struct{ Text string }
+			Rows    [][]string
+		}
+		postJSON(t, ts, "/query", `{`+rangeJSON+`,"targets":[{"target":"issues.Board","type":"table"}]}`, &tables)
+		require.Len(t, tables, 1)
+		assert.Equal(t, "table", tables[0].Type)
+		assert.NotEmpty(t, tables[0].Columns)
+		assert.Len(t, tables[0].Rows, 1)
+	})
+
+	t.Run("reuses runs of the same range", func(t *testing.T) {
+		calls := fakeClient.GetIssuesCallCount()
+		var series []interface{}
+		postJSON(t, ts, "/query", `{`+rangeJSON+`,"targets":[{"target":"columns.Board.col 2"}]}`, &series)
+		assert.Equal(t, calls, fakeClient.GetIssuesCallCount())
+	})
+
+	t.Run("rejects unknown targets", func(t *testing.T) {
+		var body map[string]string
+		resp := postJSON(t, ts, "/query", `{`+rangeJSON+`,"targets":[{"target":"columns.Other.col 1"}]}`, &body)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("returns the annotations of the boards within the range", func(t *testing.T) {
+		var annotations []struct {
+			Time  int64
+			Title string
+			Tags  []string
+		}
+		postJSON(t, ts, "/annotations", `{`+rangeJSON+`,"annotation":{"name":"releases","query":"Board"}}`, &annotations)
+		require.Len(t, annotations, 1)
+		assert.Equal(t, "release", annotations[0].Title)
+		assert.Equal(t, []string{"Board"}, annotations[0].Tags)
+		assert.Equal(t, time.Date(2001, 2, 6, 0, 0, 0, 0, time.Local).Unix()*1000, annotations[0].Time)
+	})
+}
+
+func TestGrafana_ConcurrentQueries(t *testing.T) {
+	const slowProjectID = int64(456)
+	release := make(chan struct{})
+	var releaseOnce sync.Once
+	fakeClient := newFakeClient()
+	fakeClient.GetProjectStub = func(ctx context.Context, id int64) (models.Project, error) {
+		if id == slowProjectID {
+			<-release
+		}
+		return models.Project{ID: id, Name: "Board"}, nil
+	}
+	slow := runConfigs[0]
+	slow.Name, slow.ProjectID = "Slow", slowProjectID
+
+	srv := server.New(fakeClient, nil, 7)
+	srv.Grafana = server.NewGrafana(fakeClient, append(config.RunConfigs{slow}, runConfigs...), time.Minute)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+	// released before the server is closed, so a failed test does not wait for the slow run
+	defer releaseOnce.Do(func() { close(release) })
+
+	from := time.Date(2001, 2, 4, 0, 0, 0, 0, time.Local).UTC().Format(time.RFC3339)
+	to := time.Date(2001, 2, 8, 12, 0, 0, 0, time.Local).UTC().Format(time.RFC3339)
+	query := func(target string) string {
+		return `{"range":{"from":"` + from + `","to":"` + to + `"},"targets":[{"target":"` + target + `"}]}`
+	}
+	slowCalls := func() int {
+		calls := 0
+		for idx := 0; idx < fakeClient.GetProjectCallCount(); idx++ {
+			if _, id := fakeClient.GetProjectArgsForCall(idx); id == slowProjectID {
+				calls++
+			}
+		}
+		return calls
+	}
+
+	t.Run("answers queries of other boards while a board runs, and runs a board once for the same queries", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for idx := 0; idx < 2; idx++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				var series []interface{}
+				postJSON(t, ts, "/query", query("columns.Slow.col 1"), &series)
+			}()
+		}
+		require.Eventually(t, func() bool { return slowCalls() == 1 }, time.Second, time.Millisecond)
+
+		answered := make(chan struct{})
+		go func() {
+			var series []interface{}
+			postJSON(t, ts, "/query", query("columns.Board.col 1"), &series)
+			close(answered)
+		}()
+		select {
+		case <-answered:
+		case <-time.After(5 * time.Second):
+			t.Fatal("query of Board waited for the run of Slow")
+		}
+
+		releaseOnce.Do(func() { close(release) })
+		wg.Wait()
+		assert.Equal(t, 1, slowCalls())
+	})
+}
+
+func TestGrafana_CancelledQueries(t *testing.T) {
+	release := make(chan struct{})
+	var releaseOnce sync.Once
+	fakeClient := newFakeClient()
+	fakeClient.GetProjectStub = func(ctx context.Context, id int64) (models.Project, error) {
+		<-release
+		return models.Project{ID: id, Name: "Board"}, ctx.Err()
+	}
+	srv := server.New(fakeClient, nil, 7)
+	srv.Grafana = server.NewGrafana(fakeClient, runConfigs, time.Minute)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+	defer srv.Grafana.Wait()
+	// released before the server is closed, so a failed test does not wait for the run
+	defer releaseOnce.Do(func() { close(release) })
+
+	from := time.Date(2001, 2, 4, 0, 0, 0, 0, time.Local).UTC().Format(time.RFC3339)
+	to := time.Date(2001, 2, 8, 12, 0, 0, 0, time.Local).UTC().Format(time.RFC3339)
+	query := `{"range":{"from":"` + from + `","to":"` + to + `"},"targets":[{"target":"columns.Board.col 1"}]}`
+
+	t.Run("runs the board on its own context, so a cancelled query does not fail the queries waiting with it", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		req, err := http.NewRequest(http.MethodPost, ts.URL+"/query", strings.NewReader(query))
+		require.NoError(t, err)
+		cancelled := make(chan error)
+		go func() {
+			resp, err := ts.Client().Do(req.WithContext(ctx))
+			if err == nil {
+				resp.Body.Close()
+			}
+			cancelled <- err
+		}()
+		require.Eventually(t, func() bool { return fakeClient.GetProjectCallCount() == 1 }, time.Second, time.Millisecond)
+
+		var series []map[string]interface{}
+		answered := make(chan *http.Response)
+		go func() {
+			answered <- postJSON(t, ts, "/query", query, &series)
+		}()
+		cancel()
+		assert.Error(t, <-cancelled)
+
+		releaseOnce.Do(func() { close(release) })
+		resp := <-answered
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Len(t, series, 1)
+		assert.Equal(t, 1, fakeClient.GetProjectCallCount())
+	})
+}
@@ -0,0 +1,132 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// cycleTimeBuckets - upper bounds, in days, of the cycle time histogram buckets
+var cycleTimeBuckets = []float64{1, 2, 3, 5, 8, 13, 21, 34, 55, 89}
+
+// serveMetrics - writes the latest results in the prometheus text exposition format
+func (s *Server) serveMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	s.WriteMetrics(w)
+}
+
+// WriteMetrics - writes the latest results of every board, and the server's own metrics, as prometheus metrics
+func (s *Server) WriteMetrics(w io.Writer) {
+	boards := s.Boards()
+
+	family(w, "github_metrics_column_wip", "gauge", "Number of issues in each column of the board at the end of the run.")
+	for _, board := range boards {
+		if board.Columns == nil {
+			continue
+		}
+		days := board.Columns.ColumnsMetrics()
+		if len(days) == 0 {
+			continue
+		}
+		for _, amount := range days[len(days)-1].ColumnAmounts {
+			sample(w, "github_metrics_column_wip", labels("board", board.RunConfig.Name, "column", amount.Name), float64(amount.Amount))
+		}
+	}
+
+	family(w, "github_metrics_cycle_time_days", "histogram", "Days from the start column to the end column of issues completed during the run.")
+	for _, board := range boards {
+		if board.Issues == nil {
+			continue
+		}
+		counts := make([]int, len(cycleTimeBuckets))
+		sum := 0.0
+		completed := board.Issues.CompletedIssues()
+		for _, issue := range completed {
+			days := issue.CalcDays()
+			sum += days
+			for idx, bound := range cycleTimeBuckets {
+				if days <= bound {
+					counts[idx]++
+				}
+			}
+		}
+		for idx, bound := range cycleTimeBuckets {
+			sample(w, "github_metrics_cycle_time_days_bucket", labels("board", board.RunConfig.Name, "le", formatFloat(bound)), float64(counts[idx]))
+		}
+		sample(w, "github_metrics_cycle_time_days_bucket", labels("board", board.RunConfig.Name, "le", "+Inf"), float64(len(completed)))
+		sample(w, "github_metrics_cycle_time_days_sum", labels("board", board.RunConfig.Name), sum)
+		sample(w, "github_metrics_cycle_time_days_count", labels("board", board.RunConfig.Name), float64(len(completed)))
+	}
+
+	family(w, "github_metrics_pull_requests_open", "gauge", "Number of open pull requests in each repo of the board's end column.")
+	for _, board := range boards {
+		repos := make([]string, 0, len(board.OpenPullRequests))
+		for repo := range board.OpenPullRequests {
+			repos = append(repos, repo)
+		}
+		sort.Strings(repos)
+		for _, repo := range repos {
+			sample(w, "github_metrics_pull_requests_open", labels("board", board.RunConfig.Name, "repo", repo), float64(board.OpenPullRequests[repo]))
+		}
+	}
+
+	family(w, "github_metrics_run_duration_seconds", "gauge", "Duration of the board's latest run.")
+	for _, board := range boards {
+		sample(w, "github_metrics_run_duration_seconds", labels("board", board.RunConfig.Name), board.Duration.Seconds())
+	}
+	family(w, "github_metrics_run_last_success_timestamp_seconds", "gauge", "Unix time the board's latest successful run started.")
+	for _, board := range boards {
+		if !board.RanAt.IsZero() {
+			sample(w, "github_metrics_run_last_success_timestamp_seconds", labels("board", board.RunConfig.Name), float64(board.RanAt.Unix()))
+		}
+	}
+	family(w, "github_metrics_run_errors_total", "counter", "Number of failed runs of the board.")
+	for _, board := range boards {
+		sample(w, "github_metrics_run_errors_total", labels("board", board.RunConfig.Name), float64(board.Errors))
+	}
+
+	if s.APIStats != nil {
+		stats := s.APIStats()
+		profiles := make([]string, 0, len(stats))
+		for profile := range stats {
+			profiles = append(profiles, profile)
+		}
+		sort.Strings(profiles)
+		family(w, "github_metrics_api_calls_total", "counter", "Number of requests made to the github api with the connection profile.")
+		for _, profile := range profiles {
+			sample(w, "github_metrics_api_calls_total", labels("profile", profile), float64(stats[profile].Calls))
+		}
+		family(w, "github_metrics_api_rate_limit_remaining", "gauge", "Github api requests remaining in the current rate limit window of the connection profile.")
+		for _, profile := range profiles {
+			if stats[profile].RateLimitRemaining >= 0 {
+				sample(w, "github_metrics_api_rate_limit_remaining", labels("profile", profile), float64(stats[profile].RateLimitRemaining))
+			}
+		}
+	}
+}
+
+func family(w io.Writer, name, metricType, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+}
+
+func sample(w io.Writer, name, labels string, value float64) {
+	fmt.Fprintf(w, "%s%s %s\n", name, labels, formatFloat(value))
+}
+
+// labels - returns the label set of the name, value pairs, e.g. {board="MyBoard"}
+func labels(pairs ...string) string {
+	set := make([]string, 0, len(pairs)/2)
+	for idx := 0; idx+1 < len(pairs); idx += 2 {
+		set = append(set, pairs[idx]+`="`+labelEscaper.Replace(pairs[idx+1])+`"`)
+	}
+	return "{" + strings.Join(set, ",") + "}"
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
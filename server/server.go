@@ -0,0 +1,184 @@
+// Package server periodically runs the configured RunConfigs and serves their latest results over http, along
+// with a json api running metrics on request and a grafana json datasource
+package server
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/3xcellent/github-metrics/client"
+	"github.com/3xcellent/github-metrics/config"
+	"github.com/3xcellent/github-metrics/logging"
+	"github.com/3xcellent/github-metrics/metrics/runners"
+)
+
+var logger = logging.For("server")
+
+// Server - runs the issues and columns metrics of each RunConfig every Interval and serves the results
+type Server struct {
+	Client runners.Client
+	// Profiles - the clients of RunConfigs with a connection profile, Client is used for the others
+	Profiles   runners.ProfileClients
+	RunConfigs config.RunConfigs
+	// Days - number of days, up to and including today, each run covers
+	Days int
+	// APIStats - returns the github api counts reported on /metrics by connection profile, blank for the config
+	// without one, nil when not running against the api
+	APIStats func() map[string]client.APIStats
+	// Now - returns the time runs end on, time.Now by default
+	Now func() time.Time
+	// API - the json api served alongside the metrics, none when nil
+	API *API
+	// Grafana - the grafana json datasource served alongside the metrics, none when nil
+	Grafana *Grafana
+
+	mu     sync.RWMutex
+	boards map[string]*Board
+}
+
+// Board - the latest run of a RunConfig
+type Board struct {
+	RunConfig config.RunConfig
+	Issues    *runners.IssuesRunner
+	Columns   *runners.ColumnsRunner
+	// OpenPullRequests - number of open pull requests by repo of the board's end column
+	OpenPullRequests map[string]int
+
+	RanAt    time.Time
+	Duration time.Duration
+	Err      error
+	Errors   int
+}
+
+// New - returns a Server running runConfigs with client
+func New(client runners.Client, runConfigs config.RunConfigs, days int) *Server {
+	return &Server{
+		Client:     client,
+		RunConfigs: runConfigs,
+		Days:       days,
+		Now:        time.Now,
+		boards:     make(map[string]*Board),
+	}
+}
+
+// Handler - returns the http handler of the server's endpoints
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", s.serveMetrics)
+	if s.API != nil {
+		s.API.Register(mux)
+	}
+	if s.Grafana != nil {
+		s.Grafana.Register(mux)
+	}
+	return mux
+}
+
+// Run - refreshes every interval until ctx is done
+func (s *Server) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		s.Refresh(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Refresh - runs each RunConfig over the last Days; a failed run keeps the board's previous results
+func (s *Server) Refresh(ctx context.Context) {
+	end := s.Now()
+	end = time.Date(end.Year(), end.Month(), end.Day()+1, 0, 0, 0, 0, end.Location())
+	start := end.AddDate(0, 0, -s.Days)
+
+	for _, runCfg := range s.RunConfigs {
+		runCfg.StartDate = start
+		runCfg.EndDate = end
+
+		began := time.Now()
+		board, err := s.run(ctx, runCfg)
+		duration := time.Since(began)
+
+		s.mu.Lock()
+		previous, found := s.boards[runCfg.Name]
+		if err != nil {
+			logger.Errorf("running %s: %v", runCfg.Name, err)
+			if !found {
+				previous = &Board{RunConfig: runCfg}
+				s.boards[runCfg.Name] = previous
+			}
+			previous.Err = err
+			previous.Errors++
+			previous.Duration = duration
+		} else {
+			board.RanAt = began
+			board.Duration = duration
+			if found {
+				board.Errors = previous.Errors
+			}
+			s.boards[runCfg.Name] = board
+		}
+		s.mu.Unlock()
+	}
+}
+
+func (s *Server) run(ctx context.Context, runCfg config.RunConfig) (*Board, error) {
+	logger.Debugf("running %s: %s - %s", runCfg.Name, runCfg.StartDate, runCfg.EndDate)
+	client := s.Profiles.Get(runCfg.Profile, s.Client)
+	runCfg.MetricName = "issues"
+	issues := runners.NewIssuesRunner(runCfg, client)
+	if err := issues.Run(ctx); err != nil {
+		return nil, err
+	}
+
+	runCfg.MetricName = "columns"
+	columns := runners.NewColumnsRunner(runCfg, client)
+	if err := columns.Run(ctx); err != nil {
+		return nil, err
+	}
+
+	openPullRequests, err := s.openPullRequests(ctx, client, issues.Runner)
+	if err != nil {
+		return nil, err
+	}
+	return &Board{RunConfig: runCfg, Issues: issues, Columns: columns, OpenPullRequests: openPullRequests}, nil
+}
+
+func (s *Server) openPullRequests(ctx context.Context, client runners.Client, r *runners.Runner) (map[string]int, error) {
+	repos, err := client.GetReposFromProjectColumn(ctx, r.EndColumnID)
+	if err != nil {
+		return nil, err
+	}
+	open := make(map[string]int, len(repos))
+	for _, repo := range repos {
+		prs, err := client.GetPullRequests(ctx, r.Owner, repo.Name)
+		if err != nil {
+			return nil, err
+		}
+		open[repo.Name] = 0
+		for _, pr := range prs {
+			if pr.ClosedAt.IsZero() {
+				open[repo.Name]++
+			}
+		}
+	}
+	return open, nil
+}
+
+// Boards - returns a copy of the latest run of each RunConfig, sorted by name
+func (s *Server) Boards() []Board {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	boards := make([]Board, 0, len(s.boards))
+	for _, board := range s.boards {
+		boards = append(boards, *board)
+	}
+	sort.Slice(boards, func(i, j int) bool { return boards[i].RunConfig.Name < boards[j].RunConfig.Name })
+	return boards
+}
@@ -0,0 +1,154 @@
+package server_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/3xcellent/github-metrics/client"
+	"github.com/3xcellent/github-metrics/config"
+	"github.com/3xcellent/github-metrics/metrics/runners/runnersfakes"
+	"github.com/3xcellent/github-metrics/models"
+	"github.com/3xcellent/github-metrics/server"
+	"github.com/3xcellent/github-metrics/tools/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const projectID = int64(123)
+
+var (
+	now  = time.Date(2001, 2, 10, 12, 0, 0, 0, time.Local)
+	day  = func(d int) time.Time { return time.Date(2001, 2, d, 9, 0, 0, 0, time.Local) }
+	cols = testhelpers.NewProjectColumns(3)
+
+	runConfigs = config.RunConfigs{{
+		Name:        "Board",
+		ProjectID:   projectID,
+		Owner:       "owner",
+		StartColumn: cols[1].Name,
+		EndColumn:   cols[2].Name,
+	}}
+)
+
+// newFakeClient - returns a client with two issues: one started on the 5th and completed on the 7th, one
+// started on the 6th and still in progress, and one open and one closed pull request
+func newFakeClient() *runnersfakes.FakeClient {
+	fakeClient := new(runnersfakes.FakeClient)
+	fakeClient.GetProjectReturns(models.Project{ID: projectID, Name: "Board"}, nil)
+	fakeClient.GetProjectColumnsReturns(cols, nil)
+	fakeClient.GetReposFromProjectColumnReturns(models.Repositories{{Name: "repo"}}, nil)
+	fakeClient.GetIssuesReturns(models.Issues{
+		{Owner: "owner", RepoName: "repo", Number: 1, CreatedAt: day(4)},
+		{Owner: "owner", RepoName: "repo", Number: 2, CreatedAt: day(4)},
+	}, nil)
+	fakeClient.GetIssueEventsStub = func(ctx context.Context, owner, repo string, number int) (models.IssueEvents, error) {
+		if number == 1 {
+			return models.IssueEvents{
+				{ProjectID: projectID, Type: models.AddedToProject, ColumnName: cols[0].Name, CreatedAt: day(4)},
+				{ProjectID: projectID, Type: models.MovedColumns, ColumnName: cols[1].Name, CreatedAt: day(5)},
+				{ProjectID: projectID, Type: models.MovedColumns, ColumnName: cols[2].Name, CreatedAt: day(7)},
+			}, nil
+		}
+		return models.IssueEvents{
+			{ProjectID: projectID, Type: models.AddedToProject, ColumnName: cols[0].Name, CreatedAt: day(4)},
+			{ProjectID: projectID, Type: models.MovedColumns, ColumnName: cols[1].Name, CreatedAt: day(6)},
+		}, nil
+	}
+	fakeClient.GetPullRequestsReturns(models.PullRequests{
+		{ID: 1, CreatedAt: day(4)},
+		{ID: 2, CreatedAt: day(4), ClosedAt: day(5)},
+	}, nil)
+	return fakeClient
+}
+
+func TestServer_Refresh(t *testing.T) {
+	fakeClient := newFakeClient()
+	srv := server.New(fakeClient, runConfigs, 7)
+	srv.Now = func() time.Time { return now }
+
+	srv.Refresh(context.Background())
+
+	boards := srv.Boards()
+	require.Len(t, boards, 1)
+
+	t.Run("runs over the last days up to and including today", func(t *testing.T) {
+		assert.Equal(t, time.Date(2001, 2, 4, 0, 0, 0, 0, time.Local), boards[0].RunConfig.StartDate)
+		assert.Equal(t, time.Date(2001, 2, 11, 0, 0, 0, 0, time.Local), boards[0].RunConfig.EndDate)
+	})
+
+	t.Run("keeps the results of the run", func(t *testing.T) {
+		assert.NoError(t, boards[0].Err)
+		assert.Len(t, boards[0].Issues.CompletedIssues(), 1)
+		assert.Equal(t, map[string]int{"repo": 1}, boards[0].OpenPullRequests)
+	})
+
+	t.Run("keeps the previous results when a run fails", func(t *testing.T) {
+		fakeClient.GetProjectReturns(models.Project{}, errors.New("rate limited"))
+		srv.Refresh(context.Background())
+
+		boards := srv.Boards()
+		assert.EqualError(t, boards[0].Err, "rate limited")
+		assert.Equal(t, 1, boards[0].Errors)
+		assert.NotNil(t, boards[0].Issues)
+	})
+}
+
+func TestServer_Metrics(t *testing.T) {
+	srv := server.New(newFakeClient(), runConfigs, 7)
+	srv.Now = func() time.Time { return now }
+	srv.APIStats = func() map[string]client.APIStats {
+		return map[string]client.APIStats{
+			"":           {Calls: 12, RateLimitRemaining: 4988},
+			"enterprise": {Calls: 3, RateLimitRemaining: -1},
+		}
+	}
+	srv.Refresh(context.Background())
+
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	resp, err := ts.Client().Get(ts.URL + "/metrics")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	metrics := string(body)
+
+	t.Run("uses the prometheus text format", func(t *testing.T) {
+		assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", resp.Header.Get("Content-Type"))
+		assert.Contains(t, metrics, "# TYPE github_metrics_column_wip gauge\n")
+		assert.Contains(t, metrics, "# TYPE github_metrics_cycle_time_days histogram\n")
+	})
+
+	t.Run("includes column wip of the last day", func(t *testing.T) {
+		assert.Contains(t, metrics, `github_metrics_column_wip{board="Board",column="col 1"} 1`+"\n")
+	})
+
+	t.Run("includes the cycle time histogram of completed issues", func(t *testing.T) {
+		assert.Contains(t, metrics, `github_metrics_cycle_time_days_bucket{board="Board",le="1"} 0`+"\n")
+		assert.Contains(t, metrics, `github_metrics_cycle_time_days_bucket{board="Board",le="2"} 1`+"\n")
+		assert.Contains(t, metrics, `github_metrics_cycle_time_days_bucket{board="Board",le="+Inf"} 1`+"\n")
+		assert.Contains(t, metrics, `github_metrics_cycle_time_days_sum{board="Board"} 2`+"\n")
+		assert.Contains(t, metrics, `github_metrics_cycle_time_days_count{board="Board"} 1`+"\n")
+	})
+
+	t.Run("includes open pull requests", func(t *testing.T) {
+		assert.Contains(t, metrics, `github_metrics_pull_requests_open{board="Board",repo="repo"} 1`+"\n")
+	})
+
+	t.Run("includes self metrics", func(t *testing.T) {
+		assert.Contains(t, metrics, `github_metrics_run_errors_total{board="Board"} 0`+"\n")
+		assert.Contains(t, metrics, `github_metrics_run_duration_seconds{board="Board"} `)
+		assert.Contains(t, metrics, `github_metrics_api_calls_total{profile=""} 12`+"\n")
+		assert.Contains(t, metrics, `github_metrics_api_rate_limit_remaining{profile=""} 4988`+"\n")
+	})
+
+	t.Run("includes the api metrics of each connection profile", func(t *testing.T) {
+		assert.Contains(t, metrics, `github_metrics_api_calls_total{profile="enterprise"} 3`+"\n")
+		assert.NotContains(t, metrics, `github_metrics_api_rate_limit_remaining{profile="enterprise"}`)
+	})
+}
@@ -0,0 +1,248 @@
+package slo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/3xcellent/github-metrics/apperrors"
+	"github.com/3xcellent/github-metrics/config"
+	"github.com/3xcellent/github-metrics/metrics"
+	"github.com/3xcellent/github-metrics/metrics/runners"
+	"github.com/3xcellent/github-metrics/models"
+)
+
+// ErrNoCompletedIssues - the stat of a measure of issues, other than their count, has no value when none was completed
+var ErrNoCompletedIssues = errors.New("no completed issues")
+
+// Result - the check of an SLO for a board
+type Result struct {
+	SLO   config.SLO
+	Board string
+	// Actual - the stat of the measure, compared with the value of the SLO
+	Actual float64
+	Passed bool
+	// Err - why the measure could not be checked
+	Err error
+}
+
+// Violated - returns whether the measure was checked and does not meet the SLO
+func (r Result) Violated() bool {
+	return r.Err == nil && !r.Passed
+}
+
+// Status - returns ok, violated or error
+func (r Result) Status() string {
+	switch {
+	case r.Err != nil:
+		return "error"
+	case r.Passed:
+		return "ok"
+	}
+	return "violated"
+}
+
+// Checker - checks SLOs against the metrics of RunConfigs, run with Client or the client of their profile
+type Checker struct {
+	Client   runners.Client
+	Profiles runners.ProfileClients
+}
+
+// Check - returns the result of each SLO that applies to each RunConfig, in order; the data of a board is fetched once
+// for all of its SLOs
+func (c *Checker) Check(ctx context.Context, slos []config.SLO, runConfigs config.RunConfigs) []Result {
+	results := make([]Result, 0)
+	for _, runCfg := range runConfigs {
+		b := &board{runCfg: runCfg, client: runners.NewCachingClient(c.Profiles.Get(runCfg.Profile, c.Client))}
+		for _, s := range slos {
+			if !Applies(s, runCfg.Name) {
+				continue
+			}
+			result := Result{SLO: s, Board: runCfg.Name}
+			values, err := b.values(ctx, s)
+			if err != nil {
+				result.Err = err
+				logger.Warnf("%s: %s: %v", runCfg.Name, Name(s), err)
+			} else {
+				result.Actual = compute(Stat(s), values)
+				result.Passed = ops[s.Op](result.Actual, s.Value)
+			}
+			results = append(results, result)
+		}
+	}
+	return results
+}
+
+// Violated - returns the results that do not meet their SLO
+func Violated(results []Result) []Result {
+	violated := make([]Result, 0)
+	for _, result := range results {
+		if result.Violated() {
+			violated = append(violated, result)
+		}
+	}
+	return violated
+}
+
+// board - the metrics of a RunConfig, run when first measured
+type board struct {
+	runCfg config.RunConfig
+	client runners.Client
+
+	issues  *runners.IssuesRunner
+	columns *runners.ColumnsRunner
+}
+
+// values - returns the values of the measure of the SLO, the stat of which is checked
+func (b *board) values(ctx context.Context, s config.SLO) ([]float64, error) {
+	switch s.Measure {
+	case config.MeasureWIP:
+		return b.wip(ctx, s.Column)
+	case config.MeasurePROpen:
+		return b.prsOpen(ctx, s)
+	}
+
+	issues, err := b.completedIssues(ctx)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]float64, 0, len(issues))
+	for _, issue := range issues {
+		if s.Type != "" && !strings.EqualFold(issue.Type, s.Type) {
+			continue
+		}
+		values = append(values, issueValue(issue, s))
+	}
+	if len(values) == 0 && Stat(s) != StatCount {
+		if s.Type != "" {
+			return nil, fmt.Errorf("%w of type %s", ErrNoCompletedIssues, s.Type)
+		}
+		return nil, ErrNoCompletedIssues
+	}
+	return values, nil
+}
+
+// issueValue - returns the measure of the SLO of the issue, 1 for throughput
+func issueValue(issue metrics.Issue, s config.SLO) float64 {
+	started := issue.ColumnDates[issue.StartColumnIndex].Date
+	switch s.Measure {
+	case config.MeasureLeadTime:
+		return days(issue.CreatedDate(), issue.CompletedAt(), s.BusinessDays)
+	case config.MeasureTimeToStart:
+		if started.Before(issue.CreatedDate()) {
+			return 0
+		}
+		return days(issue.CreatedDate(), started, s.BusinessDays)
+	case config.MeasureBlockedDays:
+		if !s.BusinessDays {
+			return issue.TotalTimeBlocked.Hours() / 24
+		}
+		blocked := 0.0
+		for _, interval := range issue.BlockedIntervals {
+			blocked += metrics.BusinessDays(interval.From, interval.To)
+		}
+		return blocked
+	case config.MeasureThroughput:
+		return 1
+	}
+	return days(started, issue.CompletedAt(), s.BusinessDays)
+}
+
+func days(from, to time.Time, businessDays bool) float64 {
+	if businessDays {
+		return metrics.BusinessDays(from, to)
+	}
+	return to.Sub(from).Hours() / 24
+}
+
+func (b *board) completedIssues(ctx context.Context) (metrics.Issues, error) {
+	if b.issues == nil {
+		runner := runners.NewIssuesRunner(b.runCfg, b.client)
+		if err := runner.Run(ctx); err != nil {
+			return nil, err
+		}
+		b.issues = runner
+	}
+	return b.issues.CompletedIssues(), nil
+}
+
+// wip - returns the number of issues in the column each day of the run dates
+func (b *board) wip(ctx context.Context, column string) ([]float64, error) {
+	if b.columns == nil {
+		runner := runners.NewColumnsRunner(b.runCfg, b.client)
+		if err := runner.Run(ctx); err != nil {
+			return nil, err
+		}
+		b.columns = runner
+	}
+	name := b.columns.ColumnMapper.Resolve(column)
+	if !contains(b.columns.ColumnNames, name) {
+		return nil, &runners.UnknownColumnError{Column: column, Columns: b.columns.ColumnNames}
+	}
+	values := make([]float64, 0)
+	for _, day := range b.columns.ColumnsMetrics() {
+		for _, amount := range day.ColumnAmounts {
+			if amount.Name == name {
+				values = append(values, float64(amount.Amount))
+			}
+		}
+	}
+	return values, nil
+}
+
+// prsOpen - returns the days each pull request of the repos of the board was open at the end of the run dates, of
+// those open longer than the OlderThanDays of the SLO
+func (b *board) prsOpen(ctx context.Context, s config.SLO) ([]float64, error) {
+	if _, err := b.completedIssues(ctx); err != nil {
+		return nil, err
+	}
+	repos, err := b.client.GetReposFromProjectColumn(ctx, b.issues.EndColumnID)
+	if err != nil {
+		return nil, err
+	}
+	end := b.runCfg.EndDate
+	if now := time.Now(); end.After(now) {
+		end = now
+	}
+	values := make([]float64, 0)
+	for _, repo := range repos {
+		owner := repo.Owner
+		if owner == "" {
+			owner = b.runCfg.Owner
+		}
+		prs, err := b.client.GetPullRequests(ctx, owner, repo.Name)
+		if err != nil {
+			return nil, err
+		}
+		for _, pr := range prs {
+			if !isOpen(pr, end) {
+				continue
+			}
+			if open := days(pr.CreatedAt, end, s.BusinessDays); open > s.OlderThanDays {
+				values = append(values, open)
+			}
+		}
+	}
+	return values, nil
+}
+
+// isOpen - returns whether the pull request was open at the time
+func isOpen(pr models.PullRequest, at time.Time) bool {
+	return pr.CreatedAt.Before(at) && (pr.ClosedAt.IsZero() || pr.ClosedAt.After(at))
+}
+
+// Err - returns an error of the violated results, or of the first result that could not be checked when none was
+// violated; nil when every result passed
+func Err(results []Result) error {
+	if violated := Violated(results); len(violated) > 0 {
+		return apperrors.Errorf(apperrors.Violated, "%d of %d slos violated", len(violated), len(results))
+	}
+	for _, result := range results {
+		if result.Err != nil {
+			return fmt.Errorf("%s: %s: %w", result.Board, Name(result.SLO), result.Err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package slo
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+// junit test suites, see https://llg.cubic.org/docs/junit/
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Errors   int          `xml:"errors,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Errors   int         `xml:"errors,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit - writes the results as JUnit XML, a test suite for each board and a test case for each SLO, failed when
+// violated and in error when it could not be checked, so CI systems show violations as test failures
+func WriteJUnit(w io.Writer, results []Result) error {
+	suites := junitSuites{}
+	index := make(map[string]int)
+	for _, result := range results {
+		idx, found := index[result.Board]
+		if !found {
+			idx = len(suites.Suites)
+			index[result.Board] = idx
+			suites.Suites = append(suites.Suites, junitSuite{Name: result.Board})
+		}
+		suite := &suites.Suites[idx]
+
+		testCase := junitCase{Name: Name(result.SLO), ClassName: result.Board}
+		switch {
+		case result.Err != nil:
+			testCase.Error = &junitMessage{Message: result.Err.Error(), Type: "error", Text: result.Err.Error()}
+			suite.Errors++
+		case !result.Passed:
+			message := fmt.Sprintf("%s: actual %s", Rule(result.SLO), FormatValue(result.Actual))
+			testCase.Failure = &junitMessage{Message: message, Type: "violated", Text: message}
+			suite.Failures++
+		}
+		suite.Tests++
+		suite.Cases = append(suite.Cases, testCase)
+	}
+	for _, suite := range suites.Suites {
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Errors += suite.Errors
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(suites); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
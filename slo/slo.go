@@ -0,0 +1,169 @@
+// Package slo checks the SLOs of the config, rules a measure of the metrics of a board must meet, e.g. the p85
+// cycleTime of Bugs < 5 business days, and writes the results as JUnit XML for CI systems
+package slo
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/3xcellent/github-metrics/config"
+	"github.com/3xcellent/github-metrics/logging"
+	"github.com/3xcellent/github-metrics/metrics"
+)
+
+var logger = logging.For("slo")
+
+// stats of the values of a measure
+const (
+	StatCount = "count"
+	StatMean  = "mean"
+	StatMin   = "min"
+	StatP50   = "p50"
+	StatP85   = "p85"
+	StatP95   = "p95"
+	StatMax   = "max"
+)
+
+var (
+	measures = []string{config.MeasureCycleTime, config.MeasureLeadTime, config.MeasureTimeToStart, config.MeasureBlockedDays, config.MeasureThroughput, config.MeasureWIP, config.MeasurePROpen}
+	stats    = []string{StatCount, StatMean, StatMin, StatP50, StatP85, StatP95, StatMax}
+	ops      = map[string]func(actual, value float64) bool{
+		"<":  func(actual, value float64) bool { return actual < value },
+		"<=": func(actual, value float64) bool { return actual <= value },
+		">":  func(actual, value float64) bool { return actual > value },
+		">=": func(actual, value float64) bool { return actual >= value },
+		"==": func(actual, value float64) bool { return actual == value },
+		"!=": func(actual, value float64) bool { return actual != value },
+	}
+)
+
+// Stat - returns the stat of the SLO, the default of its measure when blank: count for throughput and prsOpen, max
+// for wip and p85 for the others
+func Stat(s config.SLO) string {
+	if s.Stat != "" {
+		return strings.ToLower(s.Stat)
+	}
+	switch s.Measure {
+	case config.MeasureThroughput, config.MeasurePROpen:
+		return StatCount
+	case config.MeasureWIP:
+		return StatMax
+	}
+	return StatP85
+}
+
+// Rule - returns the rule of the SLO, e.g. p85 cycleTime of Bug < 5 business days
+func Rule(s config.SLO) string {
+	rule := Stat(s) + " " + s.Measure
+	switch s.Measure {
+	case config.MeasureWIP:
+		rule += " in " + s.Column
+	case config.MeasurePROpen:
+		rule += fmt.Sprintf(" > %g %s", s.OlderThanDays, unit(s))
+	}
+	if s.Type != "" {
+		rule += " of " + s.Type
+	}
+	rule += fmt.Sprintf(" %s %g", s.Op, s.Value)
+	if isDays(s) {
+		rule += " " + unit(s)
+	}
+	return rule
+}
+
+// Name - returns the name of the SLO, its rule when it has none
+func Name(s config.SLO) string {
+	if s.Name != "" {
+		return s.Name
+	}
+	return Rule(s)
+}
+
+// isDays - returns whether the value of the SLO is days
+func isDays(s config.SLO) bool {
+	switch s.Measure {
+	case config.MeasureCycleTime, config.MeasureLeadTime, config.MeasureTimeToStart, config.MeasureBlockedDays, config.MeasurePROpen:
+		return Stat(s) != StatCount
+	}
+	return false
+}
+
+func unit(s config.SLO) string {
+	if s.BusinessDays {
+		return "business days"
+	}
+	return "days"
+}
+
+// Validate - returns the problems of the SLOs of the config: unknown measures, stats, operators or run configs, and
+// wip without a column
+func Validate(cfg *config.AppConfig) []config.Problem {
+	runConfigs := make(map[string]bool, len(cfg.RunConfigs))
+	for _, runCfg := range cfg.RunConfigs {
+		runConfigs[runCfg.Name] = true
+	}
+	problems := make([]config.Problem, 0)
+	for idx, s := range cfg.SLOs {
+		path := fmt.Sprintf("SLOs[%d]", idx)
+		if !contains(measures, s.Measure) {
+			problems = append(problems, config.Problem{Path: path + ".measure", Message: fmt.Sprintf("unknown measure %q, use one of %s", s.Measure, strings.Join(measures, ", "))})
+		}
+		if !contains(stats, Stat(s)) {
+			problems = append(problems, config.Problem{Path: path + ".stat", Message: fmt.Sprintf("unknown stat %q, use one of %s", s.Stat, strings.Join(stats, ", "))})
+		}
+		if _, found := ops[s.Op]; !found {
+			problems = append(problems, config.Problem{Path: path + ".op", Message: fmt.Sprintf("unknown op %q, use one of <, <=, >, >=, ==, !=", s.Op)})
+		}
+		if s.Measure == config.MeasureWIP && s.Column == "" {
+			problems = append(problems, config.Problem{Path: path + ".column", Message: "required for the wip measure"})
+		}
+		for _, name := range s.RunConfigs {
+			if !runConfigs[name] {
+				problems = append(problems, config.Problem{Path: path + ".runConfigs", Message: fmt.Sprintf("no run config named %q", name)})
+			}
+		}
+	}
+	return problems
+}
+
+// Applies - returns whether the SLO is checked for the board
+func Applies(s config.SLO, board string) bool {
+	return len(s.RunConfigs) == 0 || contains(s.RunConfigs, board)
+}
+
+// FormatValue - returns the value rounded to one decimal, without trailing zeros
+func FormatValue(value float64) string {
+	return strconv.FormatFloat(math.Round(value*10)/10, 'f', -1, 64)
+}
+
+// compute - returns the stat of the values
+func compute(stat string, values []float64) float64 {
+	if stat == StatCount {
+		return float64(len(values))
+	}
+	s := metrics.NewStats(values)
+	switch stat {
+	case StatMean:
+		return s.Mean
+	case StatMin:
+		return s.Min
+	case StatP50:
+		return s.P50
+	case StatP95:
+		return s.P95
+	case StatMax:
+		return s.Max
+	}
+	return s.P85
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
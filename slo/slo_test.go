@@ -0,0 +1,217 @@
+package slo_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/3xcellent/github-metrics/apperrors"
+	"github.com/3xcellent/github-metrics/config"
+	"github.com/3xcellent/github-metrics/metrics/runners/runnersfakes"
+	"github.com/3xcellent/github-metrics/models"
+	"github.com/3xcellent/github-metrics/slo"
+	"github.com/3xcellent/github-metrics/tools/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const projectID = int64(123)
+
+var (
+	cols      = testhelpers.NewProjectColumns(3)
+	monday    = time.Date(2020, 6, 1, 10, 0, 0, 0, time.UTC)
+	runConfig = config.RunConfig{
+		Name:        "Board",
+		ProjectID:   projectID,
+		Owner:       "owner",
+		StartColumn: cols[1].Name,
+		EndColumn:   cols[2].Name,
+		StartDate:   time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:     time.Date(2020, 7, 1, 0, 0, 0, 0, time.UTC),
+	}
+)
+
+// newFakeClient - a board with a bug done in 7 days, 5 business days, an enhancement done in a day, and a pull
+// request open since May
+func newFakeClient() *runnersfakes.FakeClient {
+	fakeClient := new(runnersfakes.FakeClient)
+	fakeClient.GetProjectReturns(models.Project{ID: projectID, Name: "Board"}, nil)
+	fakeClient.GetProjectColumnsReturns(cols, nil)
+	fakeClient.GetReposFromProjectColumnReturns(models.Repositories{{Name: "repo", Owner: "owner"}}, nil)
+	fakeClient.GetIssuesReturns(models.Issues{
+		{Owner: "owner", RepoName: "repo", Number: 1, Labels: []string{"bug"}, CreatedAt: monday.Add(-time.Hour)},
+		{Owner: "owner", RepoName: "repo", Number: 2, CreatedAt: monday},
+	}, nil)
+	fakeClient.GetIssueEventsStub = func(ctx context.Context, owner, repo string, number int) (models.IssueEvents, error) {
+		if number == 1 {
+			return models.IssueEvents{
+				{ProjectID: projectID, Type: models.AddedToProject, ColumnName: cols[1].Name, CreatedAt: monday},
+				{ProjectID: projectID, Type: models.MovedColumns, ColumnName: cols[2].Name, CreatedAt: monday.AddDate(0, 0, 7)},
+			}, nil
+		}
+		return models.IssueEvents{
+			{ProjectID: projectID, Type: models.AddedToProject, ColumnName: cols[1].Name, CreatedAt: monday.AddDate(0, 0, 1)},
+			{ProjectID: projectID, Type: models.MovedColumns, ColumnName: cols[2].Name, CreatedAt: monday.AddDate(0, 0, 2)},
+		}, nil
+	}
+	fakeClient.GetPullRequestsReturns(models.PullRequests{
+		{CreatedAt: time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{CreatedAt: time.Date(2020, 6, 29, 0, 0, 0, 0, time.UTC)},
+		{CreatedAt: time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC), ClosedAt: time.Date(2020, 6, 2, 0, 0, 0, 0, time.UTC)},
+	}, nil)
+	return fakeClient
+}
+
+func TestChecker_Check(t *testing.T) {
+	slos := []config.SLO{
+		{Name: "bug cycle time", Measure: config.MeasureCycleTime, Type: "Bug", BusinessDays: true, Op: "<", Value: 6},
+		{Measure: config.MeasureCycleTime, Type: "Bug", Op: "<", Value: 6},
+		{Measure: config.MeasureThroughput, Op: ">=", Value: 2},
+		{Measure: config.MeasureWIP, Column: cols[1].Name, Op: "<=", Value: 1},
+		{Measure: config.MeasurePROpen, OlderThanDays: 7, Op: "==", Value: 0},
+		{Measure: config.MeasureThroughput, RunConfigs: []string{"Other Board"}, Op: ">", Value: 100},
+	}
+	fakeClient := newFakeClient()
+	checker := slo.Checker{Client: fakeClient}
+	results := checker.Check(context.Background(), slos, config.RunConfigs{runConfig})
+	require.Len(t, results, 5)
+
+	t.Run("checks the stat of the measure of the issues of the type", func(t *testing.T) {
+		assert.NoError(t, results[0].Err)
+		assert.Equal(t, 5.0, results[0].Actual)
+		assert.True(t, results[0].Passed)
+
+		assert.Equal(t, 7.0, results[1].Actual)
+		assert.True(t, results[1].Violated())
+	})
+
+	t.Run("counts the issues completed", func(t *testing.T) {
+		assert.Equal(t, 2.0, results[2].Actual)
+		assert.Equal(t, "ok", results[2].Status())
+	})
+
+	t.Run("checks the most issues in the column on a day", func(t *testing.T) {
+		assert.Equal(t, 2.0, results[3].Actual)
+		assert.Equal(t, "violated", results[3].Status())
+	})
+
+	t.Run("counts the pull requests open longer than the days at the end of the window", func(t *testing.T) {
+		assert.Equal(t, 1.0, results[4].Actual)
+		assert.True(t, results[4].Violated())
+	})
+
+	t.Run("fetches the data of the board once", func(t *testing.T) {
+		assert.Equal(t, 1, fakeClient.GetIssuesCallCount())
+	})
+
+	t.Run("returns an error of the violations", func(t *testing.T) {
+		err := slo.Err(results)
+		assert.EqualError(t, err, "3 of 5 slos violated")
+		assert.Equal(t, 7, apperrors.ExitCode(err))
+	})
+}
+
+func TestChecker_Check_Error(t *testing.T) {
+	fakeClient := newFakeClient()
+	fakeClient.GetProjectReturns(models.Project{}, apperrors.New(apperrors.Auth, "bad credentials"))
+	checker := slo.Checker{Client: fakeClient}
+	results := checker.Check(context.Background(), []config.SLO{{Measure: config.MeasureThroughput, Op: ">", Value: 0}}, config.RunConfigs{runConfig})
+
+	require.Len(t, results, 1)
+	assert.Equal(t, "error", results[0].Status())
+	err := slo.Err(results)
+	assert.True(t, apperrors.Is(err, apperrors.Auth), "%v", err)
+}
+
+func TestChecker_Check_NoCompletedIssues(t *testing.T) {
+	slos := []config.SLO{
+		{Measure: config.MeasureCycleTime, Type: "Chore", Op: "<", Value: 6},
+		{Measure: config.MeasureThroughput, Type: "Chore", Op: ">=", Value: 1},
+	}
+	checker := slo.Checker{Client: newFakeClient()}
+	results := checker.Check(context.Background(), slos, config.RunConfigs{runConfig})
+	require.Len(t, results, 2)
+
+	t.Run("does not check the stat of the measure of no issues", func(t *testing.T) {
+		assert.Equal(t, "error", results[0].Status())
+		assert.True(t, errors.Is(results[0].Err, slo.ErrNoCompletedIssues))
+		assert.EqualError(t, results[0].Err, "no completed issues of type Chore")
+	})
+
+	t.Run("counts no issues completed", func(t *testing.T) {
+		assert.Equal(t, 0.0, results[1].Actual)
+		assert.Equal(t, "violated", results[1].Status())
+	})
+}
+
+func TestRule(t *testing.T) {
+	assert.Equal(t, "p85 cycleTime of Bug < 5 business days", slo.Rule(config.SLO{Measure: config.MeasureCycleTime, Type: "Bug", BusinessDays: true, Op: "<", Value: 5}))
+	assert.Equal(t, "max wip in Code Review <= 6", slo.Rule(config.SLO{Measure: config.MeasureWIP, Column: "Code Review", Op: "<=", Value: 6}))
+	assert.Equal(t, "count prsOpen > 7 days == 0", slo.Rule(config.SLO{Measure: config.MeasurePROpen, OlderThanDays: 7, Op: "==", Value: 0}))
+}
+
+func TestValidate(t *testing.T) {
+	cfg := &config.AppConfig{
+		RunConfigs: config.RunConfigs{runConfig},
+		SLOs: []config.SLO{
+			{Measure: config.MeasureCycleTime, Op: "<", Value: 5},
+			{Measure: "cycletime", Stat: "p99", Op: "=", RunConfigs: []string{"Other"}},
+			{Measure: config.MeasureWIP, Op: "<", Value: 5},
+		},
+	}
+	problems := slo.Validate(cfg)
+
+	paths := make([]string, 0, len(problems))
+	for _, problem := range problems {
+		paths = append(paths, problem.Path)
+	}
+	assert.Equal(t, []string{"SLOs[1].measure", "SLOs[1].stat", "SLOs[1].op", "SLOs[1].runConfigs", "SLOs[2].column"}, paths)
+}
+
+func TestWriteJUnit(t *testing.T) {
+	results := []slo.Result{
+		{SLO: config.SLO{Name: "fast", Measure: config.MeasureThroughput, Op: ">", Value: 1}, Board: "Board", Actual: 2, Passed: true},
+		{SLO: config.SLO{Measure: config.MeasureWIP, Column: "Review", Op: "<=", Value: 6}, Board: "Board", Actual: 8},
+		{SLO: config.SLO{Name: "fast", Measure: config.MeasureThroughput, Op: ">", Value: 1}, Board: "Other", Err: errors.New("not found")},
+	}
+	buf := new(bytes.Buffer)
+	require.NoError(t, slo.WriteJUnit(buf, results))
+
+	var suites struct {
+		Tests    int `xml:"tests,attr"`
+		Failures int `xml:"failures,attr"`
+		Errors   int `xml:"errors,attr"`
+		Suites   []struct {
+			Name  string `xml:"name,attr"`
+			Cases []struct {
+				Name    string `xml:"name,attr"`
+				Failure *struct {
+					Message string `xml:"message,attr"`
+				} `xml:"failure"`
+			} `xml:"testcase"`
+		} `xml:"testsuite"`
+	}
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &suites))
+
+	t.Run("has a suite for each board", func(t *testing.T) {
+		require.Len(t, suites.Suites, 2)
+		assert.Equal(t, "Board", suites.Suites[0].Name)
+		assert.Equal(t, "Other", suites.Suites[1].Name)
+	})
+
+	t.Run("counts the failures and errors", func(t *testing.T) {
+		assert.Equal(t, 3, suites.Tests)
+		assert.Equal(t, 1, suites.Failures)
+		assert.Equal(t, 1, suites.Errors)
+	})
+
+	t.Run("fails violated rules", func(t *testing.T) {
+		testCase := suites.Suites[0].Cases[1]
+		assert.Equal(t, "max wip in Review <= 6", testCase.Name)
+		require.NotNil(t, testCase.Failure)
+		assert.Equal(t, "max wip in Review <= 6: actual 8", testCase.Failure.Message)
+	})
+}
@@ -0,0 +1,261 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/3xcellent/github-metrics/metrics/runners"
+	"github.com/3xcellent/github-metrics/models"
+)
+
+// Store reads the synced data the same way the github client would fetch it, so runners can run from the store
+var _ runners.Client = new(Store)
+
+// GetProject - returns the saved project
+func (s *Store) GetProject(ctx context.Context, projectID int64) (models.Project, error) {
+	project := models.Project{ID: projectID}
+	var body, url sql.NullString
+	err := s.db.QueryRowContext(ctx, `SELECT name, owner, body, url FROM projects WHERE id = ?`, projectID).
+		Scan(&project.Name, &project.Owner, &body, &url)
+	if err == sql.ErrNoRows {
+		return models.Project{}, fmt.Errorf("project %d has not been synced", projectID)
+	}
+	project.Body = body.String
+	project.URL = url.String
+	return project, err
+}
+
+// GetProjects - returns the saved projects of the owner
+func (s *Store) GetProjects(ctx context.Context, owner string) (models.Projects, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT id, name, owner, body, url FROM projects WHERE owner = ? ORDER BY name`, owner)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	projects := make(models.Projects, 0)
+	for rows.Next() {
+		var project models.Project
+		var body, url sql.NullString
+		if err := rows.Scan(&project.ID, &project.Name, &project.Owner, &body, &url); err != nil {
+			return nil, err
+		}
+		project.Body = body.String
+		project.URL = url.String
+		projects = append(projects, project)
+	}
+	return projects, rows.Err()
+}
+
+// GetProjectColumns - returns the saved columns of the project in board order
+func (s *Store) GetProjectColumns(ctx context.Context, projectID int64) (models.ProjectColumns, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT id, name, position FROM project_columns WHERE project_id = ? ORDER BY position`, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns := make(models.ProjectColumns, 0)
+	for rows.Next() {
+		var column models.ProjectColumn
+		if err := rows.Scan(&column.ID, &column.Name, &column.Index); err != nil {
+			return nil, err
+		}
+		columns = append(columns, column)
+	}
+	return columns, rows.Err()
+}
+
+// GetReposFromProjectColumn - returns the saved repos of the project column
+func (s *Store) GetReposFromProjectColumn(ctx context.Context, columnID int64) (models.Repositories, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT r.owner, r.name, r.github_id, r.url FROM repositories r
+		JOIN column_repositories cr ON cr.repository_id = r.id
+		WHERE cr.column_id = ? ORDER BY r.name`, columnID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	repos := make(models.Repositories, 0)
+	for rows.Next() {
+		var repo models.Repository
+		var id sql.NullInt64
+		var url sql.NullString
+		if err := rows.Scan(&repo.Owner, &repo.Name, &id, &url); err != nil {
+			return nil, err
+		}
+		repo.ID = id.Int64
+		repo.URL = url.String
+		repos = append(repos, repo)
+	}
+	return repos, rows.Err()
+}
+
+// GetIssues - returns the saved issues of the repos created before endDate with an event, or created, since beginDate
+func (s *Store) GetIssues(ctx context.Context, repoOwner string, repoNames []string, beginDate, endDate time.Time) (models.Issues, error) {
+	issues := make(models.Issues, 0)
+	if len(repoNames) == 0 {
+		return issues, nil
+	}
+
+	args := []interface{}{repoOwner}
+	for _, repo := range repoNames {
+		args = append(args, repo)
+	}
+	args = append(args, endDate.UTC(), beginDate.UTC(), beginDate.UTC())
+	rows, err := s.db.QueryContext(ctx, `SELECT i.id, i.owner, i.repo, i.number, i.title, i.created_at FROM issues i
+		WHERE i.owner = ? AND i.repo IN (?`+strings.Repeat(", ?", len(repoNames)-1)+`) AND i.created_at <= ?
+			AND (i.created_at >= ? OR EXISTS (SELECT 1 FROM issue_events e WHERE e.issue_id = i.id AND e.created_at >= ?))
+		ORDER BY i.repo, i.number`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		issue, err := scanIssue(rows, &id)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+		issues = append(issues, issue)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for idx := range issues {
+		if issues[idx].Labels, err = s.labels(ctx, ids[idx]); err != nil {
+			return nil, err
+		}
+	}
+	return issues, nil
+}
+
+// GetIssue - returns the saved issue, without its events
+func (s *Store) GetIssue(ctx context.Context, repoOwner, repoName string, issueNumber int) (models.Issue, error) {
+	var id int64
+	issue, err := scanIssue(s.db.QueryRowContext(ctx, `SELECT id, owner, repo, number, title, created_at FROM issues
+		WHERE owner = ? AND repo = ? AND number = ?`, repoOwner, repoName, issueNumber), &id)
+	if err == sql.ErrNoRows {
+		return models.Issue{}, fmt.Errorf("issue %s/%s#%d has not been synced", repoOwner, repoName, issueNumber)
+	}
+	if err != nil {
+		return models.Issue{}, err
+	}
+	issue.Labels, err = s.labels(ctx, id)
+	return issue, err
+}
+
+// GetIssueEvents - returns the saved events of the issue in the order github returned them
+func (s *Store) GetIssueEvents(ctx context.Context, repoOwner, repoName string, issueNumber int) (models.IssueEvents, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT e.event, e.type, e.created_at, e.project_id, e.project_card_id, e.column_name,
+			e.previous_column_name, e.label, e.assignee, e.note, e.login_name
+		FROM issue_events e JOIN issues i ON i.id = e.issue_id
+		WHERE i.owner = ? AND i.repo = ? AND i.number = ? ORDER BY e.seq`, repoOwner, repoName, issueNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make(models.IssueEvents, 0)
+	for rows.Next() {
+		var event models.IssueEvent
+		var eventType string
+		var createdAt sql.NullTime
+		err := rows.Scan(&event.Event, &eventType, &createdAt, &event.ProjectID, &event.ProjectCardID, &event.ColumnName,
+			&event.PreviousColumnName, &event.Label, &event.Assignee, &event.Note, &event.LoginName)
+		if err != nil {
+			return nil, err
+		}
+		event.Type = models.IssueEventType(eventType)
+		// the github client returns event times in local time
+		event.CreatedAt = createdAt.Time.Local()
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+// GetPullRequests - returns the saved pull requests of the repo
+func (s *Store) GetPullRequests(ctx context.Context, repoOwner, repoName string) (models.PullRequests, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT id, owner, repo, url, issue_url, created_by, created_at, closed_at
+		FROM pull_requests WHERE owner = ? AND repo = ? ORDER BY id`, repoOwner, repoName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	prs := make(models.PullRequests, 0)
+	var ids []int64
+	for rows.Next() {
+		var pr models.PullRequest
+		var url, issueURL, createdBy sql.NullString
+		var createdAt, closedAt sql.NullTime
+		if err := rows.Scan(&pr.ID, &pr.Owner, &pr.RepoName, &url, &issueURL, &createdBy, &createdAt, &closedAt); err != nil {
+			return nil, err
+		}
+		pr.URL = url.String
+		pr.IssueURL = issueURL.String
+		pr.CreatedByUser = createdBy.String
+		pr.CreatedAt = createdAt.Time
+		pr.ClosedAt = closedAt.Time
+		prs = append(prs, pr)
+		ids = append(ids, pr.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	for idx := range prs {
+		prs[idx].RequestedReviewers, err = s.stringColumn(ctx, `SELECT login FROM pull_request_reviewers WHERE pull_request_id = ? ORDER BY login`, ids[idx])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return prs, nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanIssue(row scanner, id *int64) (models.Issue, error) {
+	var issue models.Issue
+	var title sql.NullString
+	var createdAt sql.NullTime
+	if err := row.Scan(id, &issue.Owner, &issue.RepoName, &issue.Number, &title, &createdAt); err != nil {
+		return models.Issue{}, err
+	}
+	issue.Title = title.String
+	issue.CreatedAt = createdAt.Time
+	return issue, nil
+}
+
+func (s *Store) labels(ctx context.Context, issueID int64) ([]string, error) {
+	return s.stringColumn(ctx, `SELECT label FROM issue_labels WHERE issue_id = ? ORDER BY label`, issueID)
+}
+
+// stringColumn - returns the single text column of the query's rows
+func (s *Store) stringColumn(ctx context.Context, query string, args ...interface{}) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	values := make([]string, 0)
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, rows.Err()
+}
@@ -0,0 +1,72 @@
+package store
+
+import (
+	"context"
+
+	"github.com/3xcellent/github-metrics/config"
+	"github.com/3xcellent/github-metrics/metrics/runners"
+	"github.com/3xcellent/github-metrics/models"
+)
+
+// Export - saves the project, its columns, the repos of its end column, their issues (with labels and events)
+// within the run dates and their pull requests
+func (s *Store) Export(ctx context.Context, client runners.Client, runCfg config.RunConfig) error {
+	project, err := client.GetProject(ctx, runCfg.ProjectID)
+	if err != nil {
+		return err
+	}
+	if project.Owner == "" {
+		project.Owner = runCfg.Owner
+	}
+	columns, err := client.GetProjectColumns(ctx, runCfg.ProjectID)
+	if err != nil {
+		return err
+	}
+	if err = s.SaveProject(ctx, project, columns); err != nil {
+		return err
+	}
+
+	runner := runners.NewBaseRunner(runCfg, client)
+	issues, _, err := runner.GetIssuesAndColumns(ctx)
+	if err != nil {
+		return err
+	}
+
+	repos, err := client.GetReposFromProjectColumn(ctx, runner.EndColumnID)
+	if err != nil {
+		return err
+	}
+	repos = withOwner(repos, runCfg.Owner)
+	if err = s.SaveColumnRepositories(ctx, runner.EndColumnID, repos); err != nil {
+		return err
+	}
+
+	logger.Debugf("saving %d issues", len(issues))
+	if err = s.SaveIssues(ctx, issues); err != nil {
+		return err
+	}
+
+	for _, repo := range repos {
+		prs, err := client.GetPullRequests(ctx, runCfg.Owner, repo.Name)
+		if err != nil {
+			return err
+		}
+		logger.Debugf("saving %d pull requests of %s", len(prs), repo.Name)
+		if err = s.SavePullRequests(ctx, prs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// withOwner - returns repos with owner set on those github returned without one
+func withOwner(repos models.Repositories, owner string) models.Repositories {
+	owned := make(models.Repositories, 0, len(repos))
+	for _, repo := range repos {
+		if repo.Owner == "" {
+			repo.Owner = owner
+		}
+		owned = append(owned, repo)
+	}
+	return owned
+}
@@ -0,0 +1,131 @@
+package store
+
+// schema - tables, indexes and views of the store; timestamps are stored in UTC
+const schema = `
+CREATE TABLE IF NOT EXISTS projects (
+	id    INTEGER PRIMARY KEY,
+	name  TEXT NOT NULL,
+	owner TEXT NOT NULL,
+	body  TEXT,
+	url   TEXT
+);
+
+CREATE TABLE IF NOT EXISTS project_columns (
+	id         INTEGER PRIMARY KEY,
+	project_id INTEGER NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+	name       TEXT NOT NULL,
+	position   INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS project_columns_project ON project_columns (project_id, position);
+
+CREATE TABLE IF NOT EXISTS repositories (
+	id        INTEGER PRIMARY KEY AUTOINCREMENT,
+	owner     TEXT NOT NULL,
+	name      TEXT NOT NULL,
+	github_id INTEGER,
+	url       TEXT,
+	UNIQUE (owner, name)
+);
+
+-- column_repositories: repos with cards in a project column, as returned by github for the column
+CREATE TABLE IF NOT EXISTS column_repositories (
+	column_id     INTEGER NOT NULL,
+	repository_id INTEGER NOT NULL REFERENCES repositories (id) ON DELETE CASCADE,
+	PRIMARY KEY (column_id, repository_id)
+);
+
+-- sync_cursors: when the issues of each repo were last synced
+CREATE TABLE IF NOT EXISTS sync_cursors (
+	owner     TEXT NOT NULL,
+	repo      TEXT NOT NULL,
+	synced_at TIMESTAMP NOT NULL,
+	PRIMARY KEY (owner, repo)
+);
+
+CREATE TABLE IF NOT EXISTS issues (
+	id         INTEGER PRIMARY KEY AUTOINCREMENT,
+	owner      TEXT NOT NULL,
+	repo       TEXT NOT NULL,
+	number     INTEGER NOT NULL,
+	title      TEXT,
+	created_at TIMESTAMP,
+	UNIQUE (owner, repo, number)
+);
+CREATE INDEX IF NOT EXISTS issues_created_at ON issues (created_at);
+
+CREATE TABLE IF NOT EXISTS issue_labels (
+	issue_id INTEGER NOT NULL REFERENCES issues (id) ON DELETE CASCADE,
+	label    TEXT NOT NULL,
+	PRIMARY KEY (issue_id, label)
+);
+CREATE INDEX IF NOT EXISTS issue_labels_label ON issue_labels (label);
+
+CREATE TABLE IF NOT EXISTS issue_events (
+	id                   INTEGER PRIMARY KEY AUTOINCREMENT,
+	issue_id             INTEGER NOT NULL REFERENCES issues (id) ON DELETE CASCADE,
+	seq                  INTEGER NOT NULL,
+	event                TEXT,
+	type                 TEXT NOT NULL,
+	created_at           TIMESTAMP,
+	project_id           INTEGER,
+	project_card_id      INTEGER,
+	column_name          TEXT,
+	previous_column_name TEXT,
+	label                TEXT,
+	assignee             TEXT,
+	note                 TEXT,
+	login_name           TEXT
+);
+CREATE INDEX IF NOT EXISTS issue_events_issue ON issue_events (issue_id, seq);
+CREATE INDEX IF NOT EXISTS issue_events_project_column ON issue_events (project_id, column_name);
+CREATE INDEX IF NOT EXISTS issue_events_type ON issue_events (type, created_at);
+
+CREATE TABLE IF NOT EXISTS pull_requests (
+	id         INTEGER PRIMARY KEY,
+	owner      TEXT NOT NULL,
+	repo       TEXT NOT NULL,
+	url        TEXT,
+	issue_url  TEXT,
+	created_by TEXT,
+	created_at TIMESTAMP,
+	closed_at  TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS pull_requests_repo ON pull_requests (owner, repo);
+CREATE INDEX IF NOT EXISTS pull_requests_closed_at ON pull_requests (closed_at);
+
+CREATE TABLE IF NOT EXISTS pull_request_reviewers (
+	pull_request_id INTEGER NOT NULL REFERENCES pull_requests (id) ON DELETE CASCADE,
+	login           TEXT NOT NULL,
+	PRIMARY KEY (pull_request_id, login)
+);
+
+-- issue_column_dates: when each issue first (and last) entered each column of a project
+CREATE VIEW IF NOT EXISTS issue_column_dates AS
+SELECT
+	i.owner,
+	i.repo,
+	i.number,
+	i.title,
+	e.project_id,
+	e.column_name,
+	c.position       AS column_position,
+	MIN(e.created_at) AS first_entered_at,
+	MAX(e.created_at) AS last_entered_at
+FROM issue_events e
+JOIN issues i ON i.id = e.issue_id
+LEFT JOIN project_columns c ON c.project_id = e.project_id AND c.name = e.column_name
+WHERE e.type IN ('ADDED_TO_PROJECT', 'MOVED_COLUMNS_IN_PROJECT') AND e.column_name <> ''
+GROUP BY i.id, e.project_id, e.column_name;
+
+-- issue_current_columns: the column each issue was last moved to on each project
+CREATE VIEW IF NOT EXISTS issue_current_columns AS
+SELECT i.owner, i.repo, i.number, i.title, e.project_id, e.column_name, e.created_at AS entered_at
+FROM issue_events e
+JOIN issues i ON i.id = e.issue_id
+WHERE e.type IN ('ADDED_TO_PROJECT', 'MOVED_COLUMNS_IN_PROJECT') AND e.column_name <> ''
+	AND e.seq = (
+		SELECT MAX(latest.seq) FROM issue_events latest
+		WHERE latest.issue_id = e.issue_id AND latest.project_id = e.project_id
+			AND latest.type IN ('ADDED_TO_PROJECT', 'MOVED_COLUMNS_IN_PROJECT') AND latest.column_name <> ''
+	);
+`
@@ -0,0 +1,251 @@
+// Package store keeps the raw github data of boards (repositories, issues, labels, issue events, project columns
+// and pull requests) in normalised tables of a sqlite database, for ad-hoc sql analysis
+package store
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/3xcellent/github-metrics/logging"
+	"github.com/3xcellent/github-metrics/models"
+	"github.com/pkg/errors"
+
+	// registers the sqlite3 database/sql driver
+	_ "github.com/mattn/go-sqlite3"
+)
+
+var logger = logging.For("store")
+
+// Store - a sqlite database of raw github data
+type Store struct {
+	db *sql.DB
+}
+
+// Open - opens, or creates, the sqlite database at path and creates any missing tables, indexes and views
+func Open(path string) (*Store, error) {
+	db, err := sql.Open("sqlite3", path+"?_foreign_keys=on")
+	if err != nil {
+		return nil, errors.Wrapf(err, "opening %s", path)
+	}
+	// sqlite allows a single writer, sharing one connection avoids "database is locked" errors
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, errors.Wrapf(err, "creating schema in %s", path)
+	}
+	return &Store{db: db}, nil
+}
+
+// Close - closes the database
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// DB - returns the underlying database, e.g. for queries of the views
+func (s *Store) DB() *sql.DB {
+	return s.db
+}
+
+// SaveProject - saves the project and replaces its columns, in board order
+func (s *Store) SaveProject(ctx context.Context, project models.Project, columns models.ProjectColumns) error {
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `INSERT INTO projects (id, name, owner, body, url) VALUES (?, ?, ?, ?, ?)
+			ON CONFLICT (id) DO UPDATE SET name = excluded.name, owner = excluded.owner, body = excluded.body, url = excluded.url`,
+			project.ID, project.Name, project.Owner, project.Body, project.URL)
+		if err != nil {
+			return err
+		}
+		if _, err = tx.ExecContext(ctx, `DELETE FROM project_columns WHERE project_id = ?`, project.ID); err != nil {
+			return err
+		}
+		for idx, column := range columns {
+			_, err = tx.ExecContext(ctx, `INSERT INTO project_columns (id, project_id, name, position) VALUES (?, ?, ?, ?)`,
+				column.ID, project.ID, column.Name, idx)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// SaveColumnRepositories - saves the repositories and replaces those linked to the project column
+func (s *Store) SaveColumnRepositories(ctx context.Context, columnID int64, repos models.Repositories) error {
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM column_repositories WHERE column_id = ?`, columnID); err != nil {
+			return err
+		}
+		for _, repo := range repos {
+			_, err := tx.ExecContext(ctx, `INSERT INTO repositories (owner, name, github_id, url) VALUES (?, ?, ?, ?)
+				ON CONFLICT (owner, name) DO UPDATE SET github_id = excluded.github_id, url = excluded.url`,
+				repo.Owner, repo.Name, repo.ID, repo.URL)
+			if err != nil {
+				return err
+			}
+			_, err = tx.ExecContext(ctx, `INSERT OR IGNORE INTO column_repositories (column_id, repository_id)
+				SELECT ?, id FROM repositories WHERE owner = ? AND name = ?`, columnID, repo.Owner, repo.Name)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// SaveIssues - saves the issues and replaces their labels and events
+func (s *Store) SaveIssues(ctx context.Context, issues models.Issues) error {
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		for _, issue := range issues {
+			if err := saveIssue(ctx, tx, issue); err != nil {
+				return errors.Wrapf(err, "saving issue %s/%d", issue.RepoName, issue.Number)
+			}
+		}
+		return nil
+	})
+}
+
+func saveIssue(ctx context.Context, tx *sql.Tx, issue models.Issue) error {
+	_, err := tx.ExecContext(ctx, `INSERT INTO issues (owner, repo, number, title, created_at) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (owner, repo, number) DO UPDATE SET title = excluded.title, created_at = excluded.created_at`,
+		issue.Owner, issue.RepoName, issue.Number, issue.Title, timestamp(issue.CreatedAt))
+	if err != nil {
+		return err
+	}
+
+	var issueID int64
+	err = tx.QueryRowContext(ctx, `SELECT id FROM issues WHERE owner = ? AND repo = ? AND number = ?`,
+		issue.Owner, issue.RepoName, issue.Number).Scan(&issueID)
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, `DELETE FROM issue_labels WHERE issue_id = ?`, issueID); err != nil {
+		return err
+	}
+	for _, label := range issue.Labels {
+		if _, err = tx.ExecContext(ctx, `INSERT OR IGNORE INTO issue_labels (issue_id, label) VALUES (?, ?)`, issueID, label); err != nil {
+			return err
+		}
+	}
+
+	if _, err = tx.ExecContext(ctx, `DELETE FROM issue_events WHERE issue_id = ?`, issueID); err != nil {
+		return err
+	}
+	for seq, event := range issue.Events {
+		_, err = tx.ExecContext(ctx, `INSERT INTO issue_events (issue_id, seq, event, type, created_at, project_id,
+			project_card_id, column_name, previous_column_name, label, assignee, note, login_name)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			issueID, seq, event.Event, string(event.Type), timestamp(event.CreatedAt), event.ProjectID,
+			event.ProjectCardID, event.ColumnName, event.PreviousColumnName, event.Label, event.Assignee, event.Note, event.LoginName)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SavePullRequests - saves the pull requests and replaces their requested reviewers
+func (s *Store) SavePullRequests(ctx context.Context, prs models.PullRequests) error {
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		for _, pr := range prs {
+			_, err := tx.ExecContext(ctx, `INSERT INTO pull_requests (id, owner, repo, url, issue_url, created_by, created_at, closed_at)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+				ON CONFLICT (id) DO UPDATE SET url = excluded.url, issue_url = excluded.issue_url,
+					created_by = excluded.created_by, created_at = excluded.created_at, closed_at = excluded.closed_at`,
+				pr.ID, pr.Owner, pr.RepoName, pr.URL, pr.IssueURL, pr.CreatedByUser, timestamp(pr.CreatedAt), timestamp(pr.ClosedAt))
+			if err != nil {
+				return errors.Wrapf(err, "saving pull request %d", pr.ID)
+			}
+			if _, err = tx.ExecContext(ctx, `DELETE FROM pull_request_reviewers WHERE pull_request_id = ?`, pr.ID); err != nil {
+				return err
+			}
+			for _, login := range pr.RequestedReviewers {
+				_, err = tx.ExecContext(ctx, `INSERT OR IGNORE INTO pull_request_reviewers (pull_request_id, login) VALUES (?, ?)`, pr.ID, login)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteIssuesNotIn - deletes the repo's issues, with their labels and events, whose number is not in numbers,
+// i.e. issues deleted or transferred to another repo since they were saved; returns the number deleted
+func (s *Store) DeleteIssuesNotIn(ctx context.Context, owner, repo string, numbers []int) (int, error) {
+	keep := make(map[int]bool, len(numbers))
+	for _, number := range numbers {
+		keep[number] = true
+	}
+
+	rows, err := s.db.QueryContext(ctx, `SELECT id, number FROM issues WHERE owner = ? AND repo = ?`, owner, repo)
+	if err != nil {
+		return 0, err
+	}
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		var number int
+		if err := rows.Scan(&id, &number); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		if !keep[number] {
+			ids = append(ids, id)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	err = s.inTx(ctx, func(tx *sql.Tx) error {
+		for _, id := range ids {
+			if _, err := tx.ExecContext(ctx, `DELETE FROM issues WHERE id = ?`, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return len(ids), err
+}
+
+// SyncedAt - returns when the issues of the repo were last synced, or the zero time if never
+func (s *Store) SyncedAt(ctx context.Context, owner, repo string) (time.Time, error) {
+	var syncedAt time.Time
+	err := s.db.QueryRowContext(ctx, `SELECT synced_at FROM sync_cursors WHERE owner = ? AND repo = ?`, owner, repo).Scan(&syncedAt)
+	if err == sql.ErrNoRows {
+		return time.Time{}, nil
+	}
+	return syncedAt, err
+}
+
+// SetSyncedAt - records when the issues of the repo were last synced
+func (s *Store) SetSyncedAt(ctx context.Context, owner, repo string, syncedAt time.Time) error {
+	_, err := s.db.ExecContext(ctx, `INSERT INTO sync_cursors (owner, repo, synced_at) VALUES (?, ?, ?)
+		ON CONFLICT (owner, repo) DO UPDATE SET synced_at = excluded.synced_at`, owner, repo, syncedAt.UTC())
+	return err
+}
+
+// inTx - runs f in a transaction, committed when f succeeds
+func (s *Store) inTx(ctx context.Context, f func(tx *sql.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := f(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// timestamp - returns t in UTC, so stored timestamps sort and compare as text, or nil for the zero time
+func timestamp(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return t.UTC()
+}
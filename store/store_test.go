@@ -0,0 +1,129 @@
+package store_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/3xcellent/github-metrics/config"
+	"github.com/3xcellent/github-metrics/metrics/runners/runnersfakes"
+	"github.com/3xcellent/github-metrics/models"
+	"github.com/3xcellent/github-metrics/store"
+	"github.com/3xcellent/github-metrics/tools/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const projectID = int64(1234)
+
+// timestampFormat - the text format the sqlite3 driver stores times in
+const timestampFormat = "2006-01-02 15:04:05.999999999-07:00"
+
+// openStore - opens a store in a temporary directory, removed by the returned func
+func openStore(t *testing.T) (*store.Store, func()) {
+	dir, err := ioutil.TempDir("", "store")
+	require.NoError(t, err)
+
+	s, err := store.Open(filepath.Join(dir, "board.db"))
+	require.NoError(t, err)
+	return s, func() {
+		s.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func count(t *testing.T, s *store.Store, query string, args ...interface{}) int {
+	var n int
+	require.NoError(t, s.DB().QueryRow(query, args...).Scan(&n))
+	return n
+}
+
+func TestStore_Export(t *testing.T) {
+	ctx := context.Background()
+	cols := testhelpers.NewProjectColumns(3)
+	dates := testhelpers.NewDates(4)
+	repos := models.Repositories{{Owner: "owner", Name: "repo", ID: 9}}
+
+	fakeClient := new(runnersfakes.FakeClient)
+	fakeClient.GetProjectReturns(models.Project{ID: projectID, Name: "Board", Owner: "owner"}, nil)
+	fakeClient.GetProjectColumnsReturns(cols, nil)
+	fakeClient.GetReposFromProjectColumnReturns(repos, nil)
+	fakeClient.GetIssuesReturns(models.Issues{
+		{Owner: "owner", RepoName: "repo", Number: 1, Title: "first", Labels: []string{"bug", "feature"}, CreatedAt: dates[0]},
+		{Owner: "owner", RepoName: "repo", Number: 2, Title: "second", CreatedAt: dates[0]},
+	}, nil)
+	fakeClient.GetIssueEventsReturnsOnCall(0, models.IssueEvents{
+		{ProjectID: projectID, Type: models.AddedToProject, ColumnName: cols[0].Name, CreatedAt: dates[0]},
+		{ProjectID: projectID, Type: models.MovedColumns, ColumnName: cols[1].Name, CreatedAt: dates[1]},
+		{ProjectID: projectID, Type: models.MovedColumns, ColumnName: cols[0].Name, CreatedAt: dates[2]},
+		{ProjectID: projectID, Type: models.MovedColumns, ColumnName: cols[1].Name, CreatedAt: dates[3]},
+	}, nil)
+	fakeClient.GetIssueEventsReturnsOnCall(1, models.IssueEvents{}, nil)
+	fakeClient.GetPullRequestsReturns(models.PullRequests{
+		{ID: 5, Owner: "owner", RepoName: "repo", CreatedAt: dates[0], RequestedReviewers: []string{"a", "b"}},
+	}, nil)
+
+	runCfg := config.RunConfig{
+		ProjectID:   projectID,
+		Owner:       "owner",
+		StartColumn: cols[1].Name,
+		EndColumn:   cols[2].Name,
+		StartDate:   dates[0],
+		EndDate:     dates[3],
+	}
+
+	s, cleanup := openStore(t)
+	defer cleanup()
+	require.NoError(t, s.Export(ctx, fakeClient, runCfg))
+
+	t.Run("writes normalised tables", func(t *testing.T) {
+		assert.Equal(t, 1, count(t, s, `SELECT COUNT(*) FROM projects`))
+		assert.Equal(t, 3, count(t, s, `SELECT COUNT(*) FROM project_columns WHERE project_id = ?`, projectID))
+		assert.Equal(t, 1, count(t, s, `SELECT COUNT(*) FROM repositories`))
+		assert.Equal(t, 2, count(t, s, `SELECT COUNT(*) FROM issues`))
+		assert.Equal(t, 2, count(t, s, `SELECT COUNT(*) FROM issue_labels`))
+		assert.Equal(t, 4, count(t, s, `SELECT COUNT(*) FROM issue_events`))
+		assert.Equal(t, 1, count(t, s, `SELECT COUNT(*) FROM pull_requests WHERE closed_at IS NULL`))
+		assert.Equal(t, 2, count(t, s, `SELECT COUNT(*) FROM pull_request_reviewers`))
+	})
+
+	t.Run("gets the pull requests of the end column's repos", func(t *testing.T) {
+		_, actColumnID := fakeClient.GetReposFromProjectColumnArgsForCall(0)
+		assert.Equal(t, cols[2].ID, actColumnID)
+		_, actOwner, actRepo := fakeClient.GetPullRequestsArgsForCall(0)
+		assert.Equal(t, "owner", actOwner)
+		assert.Equal(t, "repo", actRepo)
+	})
+
+	t.Run("issue_column_dates view has the first and last date of each column", func(t *testing.T) {
+		var first, last string
+		var position int
+		err := s.DB().QueryRow(`SELECT column_position, first_entered_at, last_entered_at FROM issue_column_dates
+			WHERE number = 1 AND column_name = ?`, cols[1].Name).Scan(&position, &first, &last)
+		require.NoError(t, err)
+		assert.Equal(t, 1, position)
+		assert.Equal(t, dates[1].UTC().Format(timestampFormat), first)
+		assert.Equal(t, dates[3].UTC().Format(timestampFormat), last)
+	})
+
+	t.Run("issue_current_columns view has the column last moved to", func(t *testing.T) {
+		var column string
+		require.NoError(t, s.DB().QueryRow(`SELECT column_name FROM issue_current_columns WHERE number = 1`).Scan(&column))
+		assert.Equal(t, cols[1].Name, column)
+	})
+
+	t.Run("exporting again replaces rows", func(t *testing.T) {
+		fakeClient.GetIssueEventsReturnsOnCall(2, models.IssueEvents{
+			{ProjectID: projectID, Type: models.AddedToProject, ColumnName: cols[0].Name, CreatedAt: dates[0]},
+		}, nil)
+		fakeClient.GetIssueEventsReturnsOnCall(3, models.IssueEvents{}, nil)
+		require.NoError(t, s.Export(ctx, fakeClient, runCfg))
+
+		assert.Equal(t, 2, count(t, s, `SELECT COUNT(*) FROM issues`))
+		assert.Equal(t, 1, count(t, s, `SELECT COUNT(*) FROM issue_events`))
+		assert.Equal(t, 3, count(t, s, `SELECT COUNT(*) FROM project_columns`))
+		assert.Equal(t, 1, count(t, s, `SELECT COUNT(*) FROM pull_requests`))
+	})
+}
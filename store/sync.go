@@ -0,0 +1,111 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/3xcellent/github-metrics/config"
+	"github.com/3xcellent/github-metrics/metrics/runners"
+)
+
+// SyncResult - what a sync changed for a single repo
+type SyncResult struct {
+	Owner        string
+	Repo         string
+	Since        time.Time
+	Updated      int
+	Deleted      int
+	PullRequests int
+}
+
+// Sync - saves the project, its columns and the repos of its end column, then for each repo saves the issues
+// (with labels and events) updated since the repo was last synced and its pull requests; with prune, issues
+// no longer listed in the repo, deleted or transferred, are removed
+func (s *Store) Sync(ctx context.Context, client runners.Client, runCfg config.RunConfig, prune bool) ([]SyncResult, error) {
+	project, err := client.GetProject(ctx, runCfg.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	if project.Owner == "" {
+		project.Owner = runCfg.Owner
+	}
+
+	runner := runners.NewBaseRunner(runCfg, client)
+	columns, repos, err := runner.GetRepos(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.SaveProject(ctx, project, columns); err != nil {
+		return nil, err
+	}
+	repos = withOwner(repos, runCfg.Owner)
+	if err = s.SaveColumnRepositories(ctx, runner.EndColumnID, repos); err != nil {
+		return nil, err
+	}
+
+	results := make([]SyncResult, 0, len(repos))
+	for _, repo := range repos {
+		result, err := s.syncRepo(ctx, client, runCfg.Owner, repo.Name, prune)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (s *Store) syncRepo(ctx context.Context, client runners.Client, owner, repo string, prune bool) (SyncResult, error) {
+	result := SyncResult{Owner: owner, Repo: repo}
+	since, err := s.SyncedAt(ctx, owner, repo)
+	if err != nil {
+		return result, err
+	}
+	result.Since = since
+	syncStart := time.Now()
+
+	logger.Debugf("syncing %s/%s issues updated since %s", owner, repo, since)
+	issues, err := client.GetIssues(ctx, owner, []string{repo}, since, syncStart)
+	if err != nil {
+		return result, err
+	}
+	for idx := range issues {
+		issues[idx].Events, err = client.GetIssueEvents(ctx, owner, repo, issues[idx].Number)
+		if err != nil {
+			return result, err
+		}
+	}
+	if err = s.SaveIssues(ctx, issues); err != nil {
+		return result, err
+	}
+	result.Updated = len(issues)
+
+	if prune {
+		listed := issues
+		if !since.IsZero() {
+			// list every issue, without events, to find those removed from the repo
+			listed, err = client.GetIssues(ctx, owner, []string{repo}, time.Time{}, syncStart)
+			if err != nil {
+				return result, err
+			}
+		}
+		numbers := make([]int, 0, len(listed))
+		for _, issue := range listed {
+			numbers = append(numbers, issue.Number)
+		}
+		result.Deleted, err = s.DeleteIssuesNotIn(ctx, owner, repo, numbers)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	prs, err := client.GetPullRequests(ctx, owner, repo)
+	if err != nil {
+		return result, err
+	}
+	if err = s.SavePullRequests(ctx, prs); err != nil {
+		return result, err
+	}
+	result.PullRequests = len(prs)
+
+	return result, s.SetSyncedAt(ctx, owner, repo, syncStart)
+}
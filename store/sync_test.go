@@ -0,0 +1,181 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/3xcellent/github-metrics/config"
+	"github.com/3xcellent/github-metrics/metrics/runners/runnersfakes"
+	"github.com/3xcellent/github-metrics/models"
+	"github.com/3xcellent/github-metrics/tools/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore_Sync(t *testing.T) {
+	ctx := context.Background()
+	cols := testhelpers.NewProjectColumns(3)
+	dates := testhelpers.NewDates(3)
+	runCfg := config.RunConfig{ProjectID: projectID, Owner: "owner", StartColumn: cols[1].Name, EndColumn: cols[2].Name}
+
+	fakeClient := new(runnersfakes.FakeClient)
+	fakeClient.GetProjectReturns(models.Project{ID: projectID, Name: "Board"}, nil)
+	fakeClient.GetProjectColumnsReturns(cols, nil)
+	fakeClient.GetReposFromProjectColumnReturns(models.Repositories{{Name: "repo"}}, nil)
+	fakeClient.GetIssueEventsReturns(models.IssueEvents{
+		{ProjectID: projectID, Type: models.AddedToProject, ColumnName: cols[0].Name, CreatedAt: dates[1]},
+	}, nil)
+
+	s, cleanup := openStore(t)
+	defer cleanup()
+
+	t.Run("first sync gets every issue of the end column's repos", func(t *testing.T) {
+		fakeClient.GetIssuesReturnsOnCall(0, models.Issues{
+			{Owner: "owner", RepoName: "repo", Number: 1, CreatedAt: dates[0]},
+			{Owner: "owner", RepoName: "repo", Number: 2, CreatedAt: dates[0]},
+		}, nil)
+
+		results, err := s.Sync(ctx, fakeClient, runCfg, true)
+		require.NoError(t, err)
+
+		require.Len(t, results, 1)
+		assert.Equal(t, "owner", results[0].Owner)
+		assert.True(t, results[0].Since.IsZero())
+		assert.Equal(t, 2, results[0].Updated)
+		assert.Equal(t, 0, results[0].Deleted)
+
+		require.Equal(t, 1, fakeClient.GetIssuesCallCount())
+		_, actOwner, actRepos, actSince, _ := fakeClient.GetIssuesArgsForCall(0)
+		assert.Equal(t, "owner", actOwner)
+		assert.Equal(t, []string{"repo"}, actRepos)
+		assert.True(t, actSince.IsZero())
+		_, actColumnID := fakeClient.GetReposFromProjectColumnArgsForCall(0)
+		assert.Equal(t, cols[2].ID, actColumnID)
+		assert.Equal(t, 2, count(t, s, `SELECT COUNT(*) FROM issue_events`))
+	})
+
+	t.Run("next sync gets issues updated since the last sync and removes those no longer listed", func(t *testing.T) {
+		syncedAt, err := s.SyncedAt(ctx, "owner", "repo")
+		require.NoError(t, err)
+		require.False(t, syncedAt.IsZero())
+
+		fakeClient.GetIssuesReturnsOnCall(1, models.Issues{
+			{Owner: "owner", RepoName: "repo", Number: 1, Title: "updated", CreatedAt: dates[0]},
+		}, nil)
+		fakeClient.GetIssuesReturnsOnCall(2, models.Issues{
+			{Owner: "owner", RepoName: "repo", Number: 1},
+		}, nil)
+
+		results, err := s.Sync(ctx, fakeClient, runCfg, true)
+		require.NoError(t, err)
+
+		require.Equal(t, 3, fakeClient.GetIssuesCallCount())
+		_, _, _, actSince, _ := fakeClient.GetIssuesArgsForCall(1)
+		assert.True(t, syncedAt.Equal(actSince), "since %s, want %s", actSince, syncedAt)
+		_, _, _, actListSince, _ := fakeClient.GetIssuesArgsForCall(2)
+		assert.True(t, actListSince.IsZero())
+
+		assert.Equal(t, 1, results[0].Updated)
+		assert.Equal(t, 1, results[0].Deleted)
+		assert.Equal(t, 1, count(t, s, `SELECT COUNT(*) FROM issues WHERE title = 'updated'`))
+		assert.Equal(t, 1, count(t, s, `SELECT COUNT(*) FROM issues`))
+		assert.Equal(t, 1, count(t, s, `SELECT COUNT(*) FROM issue_events`))
+	})
+
+	t.Run("without prune issues are only added or updated", func(t *testing.T) {
+		fakeClient.GetIssuesReturnsOnCall(3, models.Issues{}, nil)
+
+		results, err := s.Sync(ctx, fakeClient, runCfg, false)
+		require.NoError(t, err)
+		assert.Equal(t, 4, fakeClient.GetIssuesCallCount())
+		assert.Equal(t, 0, results[0].Deleted)
+		assert.Equal(t, 1, count(t, s, `SELECT COUNT(*) FROM issues`))
+	})
+}
+
+func TestStore_Client(t *testing.T) {
+	ctx := context.Background()
+	cols := testhelpers.NewProjectColumns(3)
+	dates := testhelpers.NewDates(5)
+
+	s, cleanup := openStore(t)
+	defer cleanup()
+
+	require.NoError(t, s.SaveProject(ctx, models.Project{ID: projectID, Name: "Board", Owner: "owner"}, cols))
+	require.NoError(t, s.SaveColumnRepositories(ctx, cols[2].ID, models.Repositories{{Owner: "owner", Name: "repo", ID: 9}}))
+	events := models.IssueEvents{
+		{Event: "added_to_project", ProjectID: projectID, Type: models.AddedToProject, ColumnName: cols[0].Name, CreatedAt: dates[1]},
+		{Event: "moved_columns_in_project", ProjectID: projectID, Type: models.MovedColumns, ColumnName: cols[1].Name, PreviousColumnName: cols[0].Name, CreatedAt: dates[3]},
+	}
+	require.NoError(t, s.SaveIssues(ctx, models.Issues{
+		{Owner: "owner", RepoName: "repo", Number: 1, Title: "old", Labels: []string{"bug"}, CreatedAt: dates[0].UTC(), Events: events},
+		{Owner: "owner", RepoName: "repo", Number: 2, Title: "quiet", CreatedAt: dates[0].UTC()},
+	}))
+	require.NoError(t, s.SavePullRequests(ctx, models.PullRequests{
+		{ID: 5, Owner: "owner", RepoName: "repo", CreatedAt: dates[0].UTC(), CreatedByUser: "dev", RequestedReviewers: []string{"b", "a"}},
+	}))
+
+	t.Run("returns projects and columns in board order", func(t *testing.T) {
+		project, err := s.GetProject(ctx, projectID)
+		require.NoError(t, err)
+		assert.Equal(t, "Board", project.Name)
+
+		projects, err := s.GetProjects(ctx, "owner")
+		require.NoError(t, err)
+		assert.Len(t, projects, 1)
+
+		actCols, err := s.GetProjectColumns(ctx, projectID)
+		require.NoError(t, err)
+		assert.Equal(t, cols, actCols)
+
+		_, err = s.GetProject(ctx, 1)
+		assert.EqualError(t, err, "project 1 has not been synced")
+	})
+
+	t.Run("returns the repos of a column", func(t *testing.T) {
+		repos, err := s.GetReposFromProjectColumn(ctx, cols[2].ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.Repositories{{Owner: "owner", Name: "repo", ID: 9}}, repos)
+	})
+
+	t.Run("returns issues with events since the begin date", func(t *testing.T) {
+		issues, err := s.GetIssues(ctx, "owner", []string{"repo"}, dates[2], dates[4])
+		require.NoError(t, err)
+		require.Len(t, issues, 1)
+		assert.Equal(t, 1, issues[0].Number)
+		assert.Equal(t, []string{"bug"}, issues[0].Labels)
+		assert.True(t, dates[0].Equal(issues[0].CreatedAt))
+
+		issues, err = s.GetIssues(ctx, "owner", []string{"repo"}, dates[0], dates[4])
+		require.NoError(t, err)
+		assert.Len(t, issues, 2)
+	})
+
+	t.Run("returns the events of an issue in order", func(t *testing.T) {
+		actEvents, err := s.GetIssueEvents(ctx, "owner", "repo", 1)
+		require.NoError(t, err)
+		require.Len(t, actEvents, 2)
+		assert.Equal(t, events[1].PreviousColumnName, actEvents[1].PreviousColumnName)
+		assert.Equal(t, models.MovedColumns, actEvents[1].Type)
+		assert.True(t, events[1].CreatedAt.Equal(actEvents[1].CreatedAt))
+	})
+
+	t.Run("returns a single issue", func(t *testing.T) {
+		issue, err := s.GetIssue(ctx, "owner", "repo", 2)
+		require.NoError(t, err)
+		assert.Equal(t, "quiet", issue.Title)
+
+		_, err = s.GetIssue(ctx, "owner", "repo", 3)
+		assert.EqualError(t, err, "issue owner/repo#3 has not been synced")
+	})
+
+	t.Run("returns pull requests with reviewers", func(t *testing.T) {
+		prs, err := s.GetPullRequests(ctx, "owner", "repo")
+		require.NoError(t, err)
+		require.Len(t, prs, 1)
+		assert.Equal(t, []string{"a", "b"}, prs[0].RequestedReviewers)
+		assert.Equal(t, "dev", prs[0].CreatedByUser)
+		assert.Equal(t, time.Time{}, prs[0].ClosedAt)
+	})
+}
@@ -0,0 +1,93 @@
+package trend
+
+import (
+	"math"
+	"sort"
+)
+
+// Alpha - the p-value under which a change is significant
+const Alpha = 0.05
+
+// minSample - the fewest values of each window the rank test is run with, below it no change is significant
+const minSample = 5
+
+// mannWhitney - returns the two-sided p-value of the Mann-Whitney U test that the values of a and b come from the same
+// distribution, using the normal approximation with tie and continuity corrections; 1 when either has fewer than
+// minSample values or all values are equal
+func mannWhitney(a, b []float64) float64 {
+	n1, n2 := float64(len(a)), float64(len(b))
+	if len(a) < minSample || len(b) < minSample {
+		return 1
+	}
+
+	type ranked struct {
+		value float64
+		first bool
+	}
+	values := make([]ranked, 0, len(a)+len(b))
+	for _, v := range a {
+		values = append(values, ranked{value: v, first: true})
+	}
+	for _, v := range b {
+		values = append(values, ranked{value: v})
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i].value < values[j].value })
+
+	// values that are equal share the average of their ranks
+	rankSum, ties := 0.0, 0.0
+	for start := 0; start < len(values); {
+		end := start
+		for end < len(values) && values[end].value == values[start].value {
+			end++
+		}
+		rank := float64(start+end+1) / 2
+		for _, v := range values[start:end] {
+			if v.first {
+				rankSum += rank
+			}
+		}
+		t := float64(end - start)
+		ties += t*t*t - t
+		start = end
+	}
+
+	n := n1 + n2
+	u := rankSum - n1*(n1+1)/2
+	mean := n1 * n2 / 2
+	variance := n1 * n2 / 12 * ((n + 1) - ties/(n*(n-1)))
+	if variance <= 0 {
+		return 1
+	}
+	z := (math.Abs(u-mean) - 0.5) / math.Sqrt(variance)
+	if z < 0 {
+		return 1
+	}
+	return math.Erfc(z / math.Sqrt2)
+}
+
+// poissonRates - returns the two-sided p-value of the exact test that the counts a and b, over exposures of days
+// daysA and daysB, have the same rate: given a+b, b is binomial with the share of daysB under the same rate
+func poissonRates(a, b int, daysA, daysB float64) float64 {
+	n := a + b
+	if n == 0 || daysA <= 0 || daysB <= 0 {
+		return 1
+	}
+	p := daysB / (daysA + daysB)
+	observed := binomial(n, b, p)
+	total := 0.0
+	for k := 0; k <= n; k++ {
+		// the outcomes as or less likely than the observed one, with some slack for rounding
+		if prob := binomial(n, k, p); prob <= observed*(1+1e-7) {
+			total += prob
+		}
+	}
+	return math.Min(total, 1)
+}
+
+// binomial - returns the probability of k successes of n trials with probability p
+func binomial(n, k int, p float64) float64 {
+	lgN, _ := math.Lgamma(float64(n + 1))
+	lgK, _ := math.Lgamma(float64(k + 1))
+	lgNK, _ := math.Lgamma(float64(n - k + 1))
+	return math.Exp(lgN - lgK - lgNK + float64(k)*math.Log(p) + float64(n-k)*math.Log1p(-p))
+}
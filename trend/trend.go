@@ -0,0 +1,238 @@
+// Package trend runs the metrics of a board for consecutive windows and compares their aggregates - throughput,
+// p50 and p85 cycle time, blocked days and average WIP per column - flagging the changes that are significant
+package trend
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/3xcellent/github-metrics/config"
+	"github.com/3xcellent/github-metrics/logging"
+	"github.com/3xcellent/github-metrics/metrics"
+	"github.com/3xcellent/github-metrics/metrics/runners"
+)
+
+var logger = logging.For("trend")
+
+// Window - the dates of a run, from Start until End
+type Window struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// Label - returns the month of the window, e.g. 2020-01, or its dates when it is not a calendar month
+func (w Window) Label() string {
+	if w.Start.Day() == 1 && w.End.Equal(w.Start.AddDate(0, 1, 0)) {
+		return w.Start.Format("2006-01")
+	}
+	return w.Start.Format("2006-01-02") + ".." + w.End.AddDate(0, 0, -1).Format("2006-01-02")
+}
+
+// Days - returns the length of the window in days
+func (w Window) Days() float64 {
+	return w.End.Sub(w.Start).Hours() / 24
+}
+
+// Months - returns n consecutive calendar month windows, the last one from start until end, which can be before the
+// end of its month
+func Months(start, end time.Time, n int) []Window {
+	windows := make([]Window, 0, n)
+	for idx := n - 1; idx > 0; idx-- {
+		windowStart := start.AddDate(0, -idx, 0)
+		windows = append(windows, Window{Start: windowStart, End: windowStart.AddDate(0, 1, 0)})
+	}
+	return append(windows, Window{Start: start, End: end})
+}
+
+// Sample - the values of the aggregates of a window
+type Sample struct {
+	Window
+	// CycleTimes and BlockedDays - of each issue completed in the window
+	CycleTimes  []float64
+	BlockedDays []float64
+	// WIP - the number of issues in each column on each day of the window
+	WIP     map[string][]float64
+	Columns []string
+}
+
+// Run - runs the issues and columns metrics of the RunConfig for each window and returns their samples; the events
+// of the issues are fetched once for all windows
+func Run(ctx context.Context, client runners.Client, runCfg config.RunConfig, windows []Window) ([]Sample, error) {
+	client = runners.NewCachingClient(client)
+	samples := make([]Sample, 0, len(windows))
+	for _, window := range windows {
+		logger.Debugf("running %s for %s", runCfg.Name, window.Label())
+		windowCfg := runCfg
+		windowCfg.StartDate, windowCfg.EndDate = window.Start, window.End
+
+		issues := runners.NewIssuesRunner(windowCfg, client)
+		if err := issues.Run(ctx); err != nil {
+			return nil, fmt.Errorf("%s: %w", window.Label(), err)
+		}
+		columns := runners.NewColumnsRunner(windowCfg, client)
+		if err := columns.Run(ctx); err != nil {
+			return nil, fmt.Errorf("%s: %w", window.Label(), err)
+		}
+		samples = append(samples, NewSample(window, issues.CompletedIssues(), columns.ColumnNames, columns.ColumnsMetrics()))
+	}
+	return samples, nil
+}
+
+// NewSample - returns the sample of the window of the issues completed in it and the number of issues in the
+// columns each day
+func NewSample(window Window, completed metrics.Issues, columnNames []string, days []metrics.ColumnsMetric) Sample {
+	sample := Sample{
+		Window:      window,
+		CycleTimes:  make([]float64, 0, len(completed)),
+		BlockedDays: make([]float64, 0, len(completed)),
+		WIP:         make(map[string][]float64, len(columnNames)),
+		Columns:     columnNames,
+	}
+	for _, issue := range completed {
+		sample.CycleTimes = append(sample.CycleTimes, issue.CalcDays())
+		sample.BlockedDays = append(sample.BlockedDays, issue.TotalTimeBlocked.Hours()/24)
+	}
+	for _, day := range days {
+		for _, amount := range day.ColumnAmounts {
+			sample.WIP[amount.Name] = append(sample.WIP[amount.Name], float64(amount.Amount))
+		}
+	}
+	return sample
+}
+
+// Row - an aggregate of each window, and its change from the window before the last to the last
+type Row struct {
+	Measure string    `json:"measure"`
+	Values  []float64 `json:"values"`
+	Change  float64   `json:"change"`
+	// PercentChange - nil when the value before was 0
+	PercentChange *float64 `json:"percentChange"`
+	// PValue - of the test that the values of the two windows are alike, the change is Significant under Alpha; 1 for
+	// the measures that are not tested
+	PValue      float64 `json:"pValue"`
+	Significant bool    `json:"significant"`
+}
+
+// Trend - the aggregates of consecutive windows
+type Trend struct {
+	Windows []Window `json:"windows"`
+	Rows    []Row    `json:"rows"`
+}
+
+// Compare - returns the aggregates of the samples and the changes of the last one: the number of issues completed,
+// compared as rates, the p50 and p85 cycle time and blocked days, compared with a rank test of the values of the
+// issues, and the average WIP of each column, which is not tested and never Significant - the WIP of a day mostly
+// carries over to the next, so a rank test of the days would flag changes that are chance
+func Compare(samples []Sample) Trend {
+	t := Trend{Windows: make([]Window, 0, len(samples))}
+	for _, sample := range samples {
+		t.Windows = append(t.Windows, sample.Window)
+	}
+	if len(samples) == 0 {
+		return t
+	}
+
+	var previous, last Sample
+	last = samples[len(samples)-1]
+	if len(samples) > 1 {
+		previous = samples[len(samples)-2]
+	}
+	aggregate := func(measure string, value func(Sample) float64, pValue float64) {
+		row := Row{Measure: measure, Values: make([]float64, 0, len(samples)), PValue: pValue}
+		for _, sample := range samples {
+			row.Values = append(row.Values, value(sample))
+		}
+		if len(samples) > 1 {
+			before, after := value(previous), value(last)
+			row.Change = after - before
+			if before != 0 {
+				percent := row.Change / before * 100
+				row.PercentChange = &percent
+			}
+			row.Significant = pValue < Alpha
+		}
+		t.Rows = append(t.Rows, row)
+	}
+
+	comparable := len(samples) > 1
+	pValue := func(test func() float64) float64 {
+		if !comparable {
+			return 1
+		}
+		return test()
+	}
+
+	aggregate("Throughput", func(s Sample) float64 { return float64(len(s.CycleTimes)) }, pValue(func() float64 {
+		return poissonRates(len(previous.CycleTimes), len(last.CycleTimes), previous.Days(), last.Days())
+	}))
+	cycleTimes := pValue(func() float64 { return mannWhitney(previous.CycleTimes, last.CycleTimes) })
+	aggregate("Cycle Time P50 Days", func(s Sample) float64 { return metrics.Percentile(s.CycleTimes, 50) }, cycleTimes)
+	aggregate("Cycle Time P85 Days", func(s Sample) float64 { return metrics.Percentile(s.CycleTimes, 85) }, cycleTimes)
+	aggregate("Blocked Days", func(s Sample) float64 { return sum(s.BlockedDays) }, pValue(func() float64 {
+		return mannWhitney(previous.BlockedDays, last.BlockedDays)
+	}))
+	for _, column := range last.Columns {
+		column := column
+		aggregate("Average WIP "+column, func(s Sample) float64 { return mean(s.WIP[column]) }, 1)
+	}
+	return t
+}
+
+// Values - returns csv rows, with headers, of the aggregates of each window and the changes of the last one
+func (t Trend) Values() [][]string {
+	headers := []string{"Measure"}
+	for _, window := range t.Windows {
+		headers = append(headers, window.Label())
+	}
+	headers = append(headers, "Change", "Change %", "Significant")
+
+	rows := [][]string{headers}
+	for _, row := range t.Rows {
+		values := []string{row.Measure}
+		for _, value := range row.Values {
+			values = append(values, format(value))
+		}
+		change, percent, significant := "", "", ""
+		if len(t.Windows) > 1 {
+			change = signed(row.Change)
+			percent = "n/a"
+			if row.PercentChange != nil {
+				percent = signed(*row.PercentChange) + "%"
+			}
+			if row.Significant {
+				significant = fmt.Sprintf("yes (p=%.3f)", row.PValue)
+			}
+		}
+		rows = append(rows, append(values, change, percent, significant))
+	}
+	return rows
+}
+
+func format(value float64) string {
+	return strconv.FormatFloat(math.Round(value*10)/10, 'f', -1, 64)
+}
+
+func signed(value float64) string {
+	if value > 0 {
+		return "+" + format(value)
+	}
+	return format(value)
+}
+
+func sum(values []float64) float64 {
+	total := 0.0
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	return sum(values) / float64(len(values))
+}
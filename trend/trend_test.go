@@ -0,0 +1,141 @@
+package trend_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/3xcellent/github-metrics/config"
+	"github.com/3xcellent/github-metrics/metrics/runners/runnersfakes"
+	"github.com/3xcellent/github-metrics/models"
+	"github.com/3xcellent/github-metrics/tools/testhelpers"
+	"github.com/3xcellent/github-metrics/trend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	may  = trend.Window{Start: time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC), End: time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)}
+	june = trend.Window{Start: time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC), End: time.Date(2020, 7, 1, 0, 0, 0, 0, time.UTC)}
+)
+
+func TestMonths(t *testing.T) {
+	t.Run("returns the months before the last window", func(t *testing.T) {
+		start := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
+		end := time.Date(2020, 3, 15, 0, 0, 0, 0, time.UTC)
+		windows := trend.Months(start, end, 3)
+		require.Len(t, windows, 3)
+		assert.Equal(t, "2020-01", windows[0].Label())
+		assert.Equal(t, "2020-02", windows[1].Label())
+		assert.Equal(t, start, windows[2].Start)
+		assert.Equal(t, end, windows[2].End)
+		assert.Equal(t, "2020-03-01..2020-03-14", windows[2].Label())
+	})
+}
+
+func TestCompare(t *testing.T) {
+	slow := []float64{9, 10, 11, 12, 10, 9, 13, 11, 10, 12}
+	fast := []float64{2, 3, 1, 2, 4, 2, 3, 1, 2, 3}
+	samples := []trend.Sample{
+		{Window: may, CycleTimes: slow, BlockedDays: make([]float64, 10), Columns: []string{"Doing"}, WIP: map[string][]float64{"Doing": {2, 2, 3, 3, 2, 2}}},
+		{Window: june, CycleTimes: fast, BlockedDays: make([]float64, 10), Columns: []string{"Doing"}, WIP: map[string][]float64{"Doing": {2, 3, 3, 2, 2, 2}}},
+	}
+
+	t.Run("flags significant changes of the last window", func(t *testing.T) {
+		result := trend.Compare(samples)
+		require.Len(t, result.Rows, 5)
+		byMeasure := map[string]trend.Row{}
+		for _, row := range result.Rows {
+			byMeasure[row.Measure] = row
+		}
+
+		throughput := byMeasure["Throughput"]
+		assert.Equal(t, []float64{10, 10}, throughput.Values)
+		assert.False(t, throughput.Significant)
+
+		p50 := byMeasure["Cycle Time P50 Days"]
+		assert.Equal(t, p50.Values[1]-p50.Values[0], p50.Change)
+		require.NotNil(t, p50.PercentChange)
+		assert.True(t, *p50.PercentChange < -50)
+		assert.True(t, p50.Significant)
+		assert.True(t, byMeasure["Cycle Time P85 Days"].Significant)
+
+		blocked := byMeasure["Blocked Days"]
+		assert.Nil(t, blocked.PercentChange)
+		assert.False(t, blocked.Significant)
+
+		wip := byMeasure["Average WIP Doing"]
+		assert.InDelta(t, 2.33, wip.Values[1], 0.01)
+		assert.False(t, wip.Significant)
+	})
+
+	t.Run("does not flag small samples", func(t *testing.T) {
+		small := []trend.Sample{
+			{Window: may, CycleTimes: []float64{10, 10}, BlockedDays: []float64{0, 0}},
+			{Window: june, CycleTimes: []float64{1, 1}, BlockedDays: []float64{0, 0}},
+		}
+		for _, row := range trend.Compare(small).Rows {
+			assert.False(t, row.Significant, row.Measure)
+		}
+	})
+
+	t.Run("flags a change in throughput", func(t *testing.T) {
+		busy := []trend.Sample{
+			{Window: may, CycleTimes: make([]float64, 5)},
+			{Window: june, CycleTimes: make([]float64, 30)},
+		}
+		assert.True(t, trend.Compare(busy).Rows[0].Significant)
+	})
+
+	t.Run("does not flag a change in wip, the days are not independent", func(t *testing.T) {
+		crowded := []trend.Sample{
+			{Window: may, Columns: []string{"Doing"}, WIP: map[string][]float64{"Doing": make([]float64, 30)}},
+			{Window: june, Columns: []string{"Doing"}, WIP: map[string][]float64{"Doing": {9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9}}},
+		}
+		wip := trend.Compare(crowded).Rows[4]
+		assert.Equal(t, "Average WIP Doing", wip.Measure)
+		assert.Equal(t, 9.0, wip.Change)
+		assert.Equal(t, 1.0, wip.PValue)
+		assert.False(t, wip.Significant)
+	})
+
+	t.Run("returns the values with the windows as columns", func(t *testing.T) {
+		values := trend.Compare(samples).Values()
+		assert.Equal(t, []string{"Measure", "2020-05", "2020-06", "Change", "Change %", "Significant"}, values[0])
+		assert.Equal(t, []string{"Throughput", "10", "10", "0", "0%", ""}, values[1])
+		assert.Equal(t, "n/a", values[4][4])
+		assert.Contains(t, values[2][5], "yes (p=")
+	})
+}
+
+func TestRun(t *testing.T) {
+	cols := testhelpers.NewProjectColumns(3)
+	fakeClient := new(runnersfakes.FakeClient)
+	fakeClient.GetProjectReturns(models.Project{ID: 1, Name: "Board"}, nil)
+	fakeClient.GetProjectColumnsReturns(cols, nil)
+	fakeClient.GetReposFromProjectColumnReturns(models.Repositories{{Name: "repo", Owner: "owner"}}, nil)
+	fakeClient.GetIssuesReturns(models.Issues{{Owner: "owner", RepoName: "repo", Number: 1, CreatedAt: may.Start}}, nil)
+	fakeClient.GetIssueEventsReturns(models.IssueEvents{
+		{ProjectID: 1, Type: models.AddedToProject, ColumnName: cols[1].Name, CreatedAt: may.Start.AddDate(0, 0, 20)},
+		{ProjectID: 1, Type: models.MovedColumns, ColumnName: cols[2].Name, CreatedAt: june.Start.AddDate(0, 0, 3)},
+	}, nil)
+	runCfg := config.RunConfig{
+		Name:        "Board",
+		ProjectID:   1,
+		Owner:       "owner",
+		StartColumn: cols[1].Name,
+		EndColumn:   cols[2].Name,
+		StartDate:   june.Start,
+		EndDate:     june.End,
+	}
+
+	t.Run("returns the sample of each window", func(t *testing.T) {
+		samples, err := trend.Run(context.Background(), fakeClient, runCfg, trend.Months(runCfg.StartDate, runCfg.EndDate, 2))
+		require.NoError(t, err)
+		require.Len(t, samples, 2)
+		assert.Empty(t, samples[0].CycleTimes)
+		require.Len(t, samples[1].CycleTimes, 1)
+		assert.Equal(t, float64(14), samples[1].CycleTimes[0])
+		assert.Equal(t, 1, fakeClient.GetIssueEventsCallCount())
+	})
+}
@@ -0,0 +1,368 @@
+// Package wizard interactively creates the config.yaml and .env of a new setup: it asks for the api settings,
+// lists the owner's projects to pick boards from and the columns of each board to pick its start and end columns
+package wizard
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/3xcellent/github-metrics/config"
+	"github.com/3xcellent/github-metrics/metrics/runners"
+	"github.com/3xcellent/github-metrics/models"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// TokenEnv - the environment variable, and .env key, the api token is read from
+const TokenEnv = "GH_METRICS_API_TOKEN"
+
+// ErrNotOverwritten - returned when the config file exists and overwriting it was declined
+var ErrNotOverwritten = errors.New("config file exists and was not overwritten")
+
+// Wizard - prompts on Out and reads answers from In
+type Wizard struct {
+	In  *bufio.Reader
+	Out io.Writer
+	// ReadToken - reads the token without echoing it, a line of In when nil
+	ReadToken func() (string, error)
+	// NewClient - returns the client of the api settings entered
+	NewClient func(ctx context.Context, api config.APIConfig) (runners.Client, error)
+
+	ConfigPath string
+	EnvPath    string
+}
+
+// File - the config written, in the layout of the README
+type File struct {
+	API        FileAPI         `yaml:"API"`
+	Owner      string          `yaml:"Owner"`
+	RunConfigs []FileRunConfig `yaml:"RunConfigs"`
+}
+
+// FileAPI - the api settings written, the token is written to the .env file
+type FileAPI struct {
+	Owner     string `yaml:"Owner"`
+	BaseURL   string `yaml:"BaseURL,omitempty"`
+	UploadURL string `yaml:"UploadURL,omitempty"`
+}
+
+// FileRunConfig - a board written
+type FileRunConfig struct {
+	Name        string `yaml:"name"`
+	Owner       string `yaml:"owner"`
+	ProjectID   int64  `yaml:"projectID"`
+	StartColumn string `yaml:"startColumn"`
+	EndColumn   string `yaml:"endColumn"`
+}
+
+// New - returns a Wizard writing config.yaml and .env in the working directory
+func New(in io.Reader, out io.Writer, newClient func(ctx context.Context, api config.APIConfig) (runners.Client, error)) *Wizard {
+	return &Wizard{
+		In:         bufio.NewReader(in),
+		Out:        out,
+		NewClient:  newClient,
+		ConfigPath: "config.yaml",
+		EnvPath:    ".env",
+	}
+}
+
+// Run - asks for the settings and boards and writes the config and .env files; existing files are only
+// overwritten when confirmed
+func (w *Wizard) Run(ctx context.Context) error {
+	if exists(w.ConfigPath) {
+		overwrite, err := w.confirm(fmt.Sprintf("%s exists, overwrite it?", w.ConfigPath))
+		if err != nil {
+			return err
+		}
+		if !overwrite {
+			return ErrNotOverwritten
+		}
+	}
+
+	api, err := w.askAPI()
+	if err != nil {
+		return err
+	}
+	client, err := w.NewClient(ctx, api)
+	if err != nil {
+		return err
+	}
+
+	projects, err := client.GetProjects(ctx, api.Owner)
+	if err != nil {
+		return errors.Wrapf(err, "listing the projects of %s", api.Owner)
+	}
+	if len(projects) == 0 {
+		return fmt.Errorf("%s has no projects", api.Owner)
+	}
+
+	file := File{
+		API:   FileAPI{Owner: api.Owner, BaseURL: api.BaseURL, UploadURL: api.UploadURL},
+		Owner: api.Owner,
+	}
+	chosen, err := w.pickProjects(projects)
+	if err != nil {
+		return err
+	}
+	for _, project := range chosen {
+		runCfg, err := w.askRunConfig(ctx, client, project, api.Owner)
+		if err != nil {
+			return err
+		}
+		file.RunConfigs = append(file.RunConfigs, runCfg)
+	}
+
+	data, err := Marshal(file)
+	if err != nil {
+		return err
+	}
+	if err = ioutil.WriteFile(w.ConfigPath, data, 0644); err != nil {
+		return err
+	}
+	fmt.Fprintf(w.Out, "wrote %s with %d boards\n", w.ConfigPath, len(file.RunConfigs))
+
+	return w.writeEnv(api.Token)
+}
+
+// Marshal - returns the yaml of the file, validated by parsing it as a config
+func Marshal(file File) ([]byte, error) {
+	data, err := yaml.Marshal(file)
+	if err != nil {
+		return nil, err
+	}
+	data = append([]byte("---\n"), data...)
+
+	cfg, err := config.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]bool, len(cfg.RunConfigs))
+	for _, runCfg := range cfg.RunConfigs {
+		if runCfg.Name == "" || runCfg.ProjectID == 0 || runCfg.StartColumn == "" || runCfg.EndColumn == "" {
+			return nil, fmt.Errorf("run config %q: name, projectID, startColumn and endColumn required", runCfg.Name)
+		}
+		if names[runCfg.Name] {
+			return nil, fmt.Errorf("run config %q: duplicate name", runCfg.Name)
+		}
+		names[runCfg.Name] = true
+	}
+	return data, nil
+}
+
+func (w *Wizard) askAPI() (config.APIConfig, error) {
+	var api config.APIConfig
+	var err error
+	if api.BaseURL, err = w.ask("Github Enterprise API url, blank for github.com", ""); err != nil {
+		return api, err
+	}
+	if api.BaseURL != "" {
+		if api.UploadURL, err = w.ask("Upload url", api.BaseURL); err != nil {
+			return api, err
+		}
+		if api.UploadURL == api.BaseURL {
+			api.UploadURL = ""
+		}
+	}
+
+	for api.Token == "" {
+		if os.Getenv(TokenEnv) != "" {
+			fmt.Fprintf(w.Out, "Access token, blank to use %s: ", TokenEnv)
+		} else {
+			fmt.Fprint(w.Out, "Access token: ")
+		}
+		if api.Token, err = w.readToken(); err != nil {
+			return api, err
+		}
+		if api.Token == "" {
+			api.Token = os.Getenv(TokenEnv)
+		}
+	}
+
+	for api.Owner == "" {
+		if api.Owner, err = w.ask("Organization or user owning the boards", ""); err != nil {
+			return api, err
+		}
+	}
+	return api, nil
+}
+
+func (w *Wizard) pickProjects(projects models.Projects) (models.Projects, error) {
+	fmt.Fprintln(w.Out, "Projects:")
+	for idx, project := range projects {
+		fmt.Fprintf(w.Out, "  %d) %s (%d)\n", idx+1, project.Name, project.ID)
+	}
+	for {
+		answer, err := w.ask("Boards to add, e.g. 1,3", "")
+		if err != nil {
+			return nil, err
+		}
+		picked, err := pick(answer, len(projects))
+		if err != nil || len(picked) == 0 {
+			fmt.Fprintf(w.Out, "enter numbers from 1 to %d separated by commas\n", len(projects))
+			continue
+		}
+		chosen := make(models.Projects, 0, len(picked))
+		for _, idx := range picked {
+			chosen = append(chosen, projects[idx])
+		}
+		return chosen, nil
+	}
+}
+
+func (w *Wizard) askRunConfig(ctx context.Context, client runners.Client, project models.Project, owner string) (FileRunConfig, error) {
+	columns, err := client.GetProjectColumns(ctx, project.ID)
+	if err != nil {
+		return FileRunConfig{}, errors.Wrapf(err, "listing the columns of %s", project.Name)
+	}
+	if len(columns) == 0 {
+		return FileRunConfig{}, fmt.Errorf("%s has no columns", project.Name)
+	}
+
+	name, err := w.ask(fmt.Sprintf("Name of %s", project.Name), project.Name)
+	if err != nil {
+		return FileRunConfig{}, err
+	}
+
+	fmt.Fprintf(w.Out, "Columns of %s:\n", project.Name)
+	for idx, column := range columns {
+		fmt.Fprintf(w.Out, "  %d) %s\n", idx+1, column.Name)
+	}
+	startDefault := 1
+	if len(columns) > 2 {
+		startDefault = 2
+	}
+	start, err := w.askColumn("Start column, where work begins", startDefault, 1, len(columns))
+	if err != nil {
+		return FileRunConfig{}, err
+	}
+	end, err := w.askColumn("End column, where work is done", len(columns), start+1, len(columns))
+	if err != nil {
+		return FileRunConfig{}, err
+	}
+
+	return FileRunConfig{
+		Name:        name,
+		Owner:       owner,
+		ProjectID:   project.ID,
+		StartColumn: columns[start-1].Name,
+		EndColumn:   columns[end-1].Name,
+	}, nil
+}
+
+// askColumn - returns the column number, from min to max, entered
+func (w *Wizard) askColumn(prompt string, def, min, max int) (int, error) {
+	if min > max {
+		min = max
+	}
+	if def < min {
+		def = min
+	}
+	for {
+		answer, err := w.ask(prompt, strconv.Itoa(def))
+		if err != nil {
+			return 0, err
+		}
+		number, err := strconv.Atoi(answer)
+		if err == nil && number >= min && number <= max {
+			return number, nil
+		}
+		fmt.Fprintf(w.Out, "enter a number from %d to %d\n", min, max)
+	}
+}
+
+func (w *Wizard) writeEnv(token string) error {
+	if exists(w.EnvPath) {
+		overwrite, err := w.confirm(fmt.Sprintf("%s exists, overwrite it?", w.EnvPath))
+		if err != nil {
+			return err
+		}
+		if !overwrite {
+			fmt.Fprintf(w.Out, "kept %s, make sure it sets %s\n", w.EnvPath, TokenEnv)
+			return nil
+		}
+	}
+	if err := ioutil.WriteFile(w.EnvPath, []byte(TokenEnv+"="+token+"\n"), 0600); err != nil {
+		return err
+	}
+	fmt.Fprintf(w.Out, "wrote %s, keep it out of source control\n", w.EnvPath)
+	return nil
+}
+
+// ask - returns the line entered, or def when blank
+func (w *Wizard) ask(prompt, def string) (string, error) {
+	if def != "" {
+		fmt.Fprintf(w.Out, "%s [%s]: ", prompt, def)
+	} else {
+		fmt.Fprintf(w.Out, "%s: ", prompt)
+	}
+	answer, err := w.readLine()
+	if err != nil {
+		return "", err
+	}
+	if answer == "" {
+		return def, nil
+	}
+	return answer, nil
+}
+
+func (w *Wizard) confirm(prompt string) (bool, error) {
+	answer, err := w.ask(prompt+" y/N", "")
+	if err != nil {
+		return false, err
+	}
+	answer = strings.ToLower(answer)
+	return answer == "y" || answer == "yes", nil
+}
+
+func (w *Wizard) readToken() (string, error) {
+	if w.ReadToken != nil {
+		token, err := w.ReadToken()
+		fmt.Fprintln(w.Out)
+		return strings.TrimSpace(token), err
+	}
+	return w.readLine()
+}
+
+// readLine - returns the next line of In, trimmed; an error when In ends before a line is entered
+func (w *Wizard) readLine() (string, error) {
+	line, err := w.In.ReadString('\n')
+	if err == io.EOF && line != "" {
+		err = nil
+	}
+	if err != nil {
+		return "", errors.Wrap(err, "reading answer")
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// pick - returns the indexes of the comma separated numbers, from 1 to max
+func pick(answer string, max int) ([]int, error) {
+	indexes := make([]int, 0)
+	seen := make(map[int]bool)
+	for _, field := range strings.Split(answer, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		number, err := strconv.Atoi(field)
+		if err != nil || number < 1 || number > max {
+			return nil, fmt.Errorf("invalid board %q", field)
+		}
+		if !seen[number] {
+			seen[number] = true
+			indexes = append(indexes, number-1)
+		}
+	}
+	return indexes, nil
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
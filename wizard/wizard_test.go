@@ -0,0 +1,128 @@
+package wizard_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/3xcellent/github-metrics/config"
+	"github.com/3xcellent/github-metrics/metrics/runners"
+	"github.com/3xcellent/github-metrics/metrics/runners/runnersfakes"
+	"github.com/3xcellent/github-metrics/models"
+	"github.com/3xcellent/github-metrics/tools/testhelpers"
+	"github.com/3xcellent/github-metrics/wizard"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newWizard(t *testing.T, input string) (*wizard.Wizard, *runnersfakes.FakeClient, *config.APIConfig, string, func()) {
+	dir, err := ioutil.TempDir("", "wizard")
+	require.NoError(t, err)
+
+	fakeClient := new(runnersfakes.FakeClient)
+	fakeClient.GetProjectsReturns(models.Projects{{ID: 11, Name: "Board One"}, {ID: 22, Name: "Board Two"}}, nil)
+	fakeClient.GetProjectColumnsReturns(testhelpers.NewProjectColumns(4), nil)
+
+	api := new(config.APIConfig)
+	w := wizard.New(strings.NewReader(input), new(bytes.Buffer), func(ctx context.Context, cfg config.APIConfig) (runners.Client, error) {
+		*api = cfg
+		return fakeClient, nil
+	})
+	w.ConfigPath = filepath.Join(dir, "config.yaml")
+	w.EnvPath = filepath.Join(dir, ".env")
+	return w, fakeClient, api, dir, func() { os.RemoveAll(dir) }
+}
+
+func TestWizard_Run(t *testing.T) {
+	t.Run("writes the config of the boards picked and the token to .env", func(t *testing.T) {
+		input := strings.Join([]string{
+			"https://github.example.com/api/v3/", // base url
+			"",                                   // upload url
+			"secret",                             // token
+			"myorg",                              // owner
+			"9, 2",                               // invalid board, then
+			"2",                                  // boards
+			"Two",                                // name
+			"",                                   // start column, default col 1
+			"3",                                  // end column
+		}, "\n") + "\n"
+		w, fakeClient, api, _, cleanup := newWizard(t, input)
+		defer cleanup()
+
+		require.NoError(t, w.Run(context.Background()))
+
+		assert.Equal(t, config.APIConfig{Token: "secret", Owner: "myorg", BaseURL: "https://github.example.com/api/v3/"}, *api)
+		_, owner := fakeClient.GetProjectsArgsForCall(0)
+		assert.Equal(t, "myorg", owner)
+		_, projectID := fakeClient.GetProjectColumnsArgsForCall(0)
+		assert.Equal(t, int64(22), projectID)
+
+		data, err := ioutil.ReadFile(w.ConfigPath)
+		require.NoError(t, err)
+		cfg, err := config.Parse(data)
+		require.NoError(t, err)
+		assert.Equal(t, "myorg", cfg.Owner)
+		assert.Equal(t, "https://github.example.com/api/v3/", cfg.API.BaseURL)
+		assert.Empty(t, cfg.API.Token)
+		require.Len(t, cfg.RunConfigs, 1)
+		assert.Equal(t, config.RunConfig{Name: "Two", Owner: "myorg", ProjectID: 22, StartColumn: "col 1", EndColumn: "col 2"}, cfg.RunConfigs[0])
+
+		env, err := ioutil.ReadFile(w.EnvPath)
+		require.NoError(t, err)
+		assert.Equal(t, "GH_METRICS_API_TOKEN=secret\n", string(env))
+		info, err := os.Stat(w.EnvPath)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	})
+
+	t.Run("does not overwrite an existing config unless confirmed", func(t *testing.T) {
+		w, fakeClient, _, _, cleanup := newWizard(t, "n\n")
+		defer cleanup()
+		require.NoError(t, ioutil.WriteFile(w.ConfigPath, []byte("Owner: mine\n"), 0644))
+
+		assert.Equal(t, wizard.ErrNotOverwritten, w.Run(context.Background()))
+		assert.Equal(t, 0, fakeClient.GetProjectsCallCount())
+		data, err := ioutil.ReadFile(w.ConfigPath)
+		require.NoError(t, err)
+		assert.Equal(t, "Owner: mine\n", string(data))
+	})
+
+	t.Run("keeps an existing .env unless confirmed", func(t *testing.T) {
+		w, _, _, _, cleanup := newWizard(t, "\ntoken\nmyorg\n1,2\n\n\n\n\n\n\nno\n")
+		defer cleanup()
+		require.NoError(t, ioutil.WriteFile(w.EnvPath, []byte("OTHER=1\n"), 0600))
+
+		require.NoError(t, w.Run(context.Background()))
+		data, err := ioutil.ReadFile(w.ConfigPath)
+		require.NoError(t, err)
+		cfg, err := config.Parse(data)
+		require.NoError(t, err)
+		assert.Len(t, cfg.RunConfigs, 2)
+
+		env, err := ioutil.ReadFile(w.EnvPath)
+		require.NoError(t, err)
+		assert.Equal(t, "OTHER=1\n", string(env))
+	})
+
+	t.Run("fails when the input ends", func(t *testing.T) {
+		w, _, _, _, cleanup := newWizard(t, "\ntoken\n")
+		defer cleanup()
+		assert.Error(t, w.Run(context.Background()))
+		_, err := os.Stat(w.ConfigPath)
+		assert.True(t, os.IsNotExist(err))
+	})
+}
+
+func TestMarshal(t *testing.T) {
+	t.Run("rejects duplicate names", func(t *testing.T) {
+		_, err := wizard.Marshal(wizard.File{RunConfigs: []wizard.FileRunConfig{
+			{Name: "a", ProjectID: 1, StartColumn: "s", EndColumn: "e"},
+			{Name: "a", ProjectID: 2, StartColumn: "s", EndColumn: "e"},
+		}})
+		assert.Error(t, err)
+	})
+}